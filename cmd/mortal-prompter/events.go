@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/logger"
+)
+
+// eventStream is the machine-readable event stream requested with --events.
+// A nil *eventStream stands for no stream, so callers need not check.
+type eventStream struct {
+	w      io.Writer
+	file   *os.File
+	bus    *events.Bus
+	writer *events.JSONWriter
+}
+
+// openEventStream opens the output of the event stream configured in cfg, or
+// returns nil if none was requested. When the stream goes to stdout the
+// terminal output of log is moved to stderr.
+func openEventStream(cfg *config.Config, log *logger.Logger) (*eventStream, error) {
+	if cfg.Events == "" {
+		return nil, nil
+	}
+	if cfg.EventsToStdout() {
+		log.SetOutputWriters(os.Stderr, os.Stderr)
+		return &eventStream{w: os.Stdout}, nil
+	}
+
+	f, err := os.Create(cfg.EventsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events file: %w", err)
+	}
+	return &eventStream{w: f, file: f}, nil
+}
+
+// attach subscribes the stream to the events of b. The stream is written
+// from a goroutine of its own, so that a slow destination never holds up the
+// battle.
+func (s *eventStream) attach(b battle) {
+	if s == nil {
+		return
+	}
+	s.bus = b.Events()
+	s.writer = events.NewJSONWriter(s.w)
+	s.bus.Subscribe(s.writer)
+}
+
+// Close waits for the pending events to be written, closes the events file
+// and reports the first error writing the stream.
+func (s *eventStream) Close() error {
+	if s == nil {
+		return nil
+	}
+	var err error
+	if s.writer != nil {
+		s.bus.Close()
+		err = s.writer.Err()
+	}
+	if s.file != nil {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+	return nil
+}
+
+// closeEventStream closes stream, logging any error writing it.
+func closeEventStream(stream *eventStream, log *logger.Logger) {
+	if err := stream.Close(); err != nil {
+		log.Error(err)
+	}
+}
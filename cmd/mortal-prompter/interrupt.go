@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/diegoram/mortal-prompter/internal/logger"
+	"github.com/diegoram/mortal-prompter/internal/proc"
+)
+
+// forceQuitHint tells the user how to stop without waiting for the fighters.
+const forceQuitHint = "Stopping the fighters, press Ctrl+C again to force quit..."
+
+// handleInterrupts cancels ctx on the first interrupt, so the session stops
+// its fighters and keeps what the current round got done. Once ctx is done,
+// for this or any other reason, another interrupt kills the fighters' process
+// groups and exits at once. The returned function stops the handling.
+func handleInterrupts(ctx context.Context, cancel context.CancelFunc, log *logger.Logger) func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigChan:
+			log.Info("Received interrupt signal, shutting down...")
+			log.Info(forceQuitHint)
+			cancel()
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		select {
+		case <-sigChan:
+			log.Info("Force quitting")
+			proc.KillAll()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
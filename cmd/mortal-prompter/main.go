@@ -1,21 +1,23 @@
 // Package main is the entry point for the mortal-prompter CLI application.
-// Mortal Prompter orchestrates a code review battle between Claude Code and OpenAI Codex.
+// Mortal Prompter orchestrates a code review battle between an implementer and a reviewer fighter.
 package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fatih/color"
 	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
 	"github.com/diegoram/mortal-prompter/internal/logger"
 	"github.com/diegoram/mortal-prompter/internal/orchestrator"
 	"github.com/diegoram/mortal-prompter/internal/reporter"
+	"github.com/diegoram/mortal-prompter/internal/session"
 	"github.com/diegoram/mortal-prompter/internal/tui"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 	"github.com/spf13/cobra"
@@ -48,19 +50,27 @@ func execute() error {
 
 	rootCmd := &cobra.Command{
 		Use:   "mortal-prompter",
-		Short: "Orchestrate code review battles between Claude Code and Codex",
+		Short: "Orchestrate code review battles between AI coding assistants",
 		Long: `Mortal Prompter - A CLI that orchestrates a development and code review loop
-between Claude Code (implementer) and OpenAI Codex (reviewer).
+between two AI coding assistants (Claude Code, Codex or Gemini).
 
 The tool acts as a referee in a Mortal Kombat-style battle:
-  - CLAUDE CODE (Fighter 1): Executes development/implementation tasks
-  - CODEX (Fighter 2): Reviews the code and finds issues
+  - IMPLEMENTER (Fighter 1, default Claude Code): Executes development/implementation tasks
+  - REVIEWER (Fighter 2, default Codex): Reviews the code and finds issues
 
-The loop continues until Codex finds no more issues or the iteration limit is reached.
+The loop continues until the reviewer finds no more issues or the iteration limit is reached.
 
 Example usage:
   mortal-prompter -p "implement JWT authentication" --auto-commit -v
-  mortal-prompter --prompt "add unit tests for users module" -m 5 -i`,
+  mortal-prompter --prompt "add unit tests for users module" -m 5 -i
+  mortal-prompter -p "refactor the parser" --implementer gemini --reviewer claude
+  mortal-prompter -p "add caching" --reviewer codex,gemini --review-policy quorum:2
+  mortal-prompter -p "add caching" --worktree
+  mortal-prompter -p "fix the parser" --verify "go build ./..." --verify "go test ./..."
+  mortal-prompter -p "add caching" --session-timeout 1h --round-timeout 15m --max-cost 5
+  mortal-prompter -p "add caching" --tournament claude,codex,gemini --judge claude
+  mortal-prompter -p "migrate the storage layer" --plan
+  mortal-prompter review --base main --fix`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,7 +87,7 @@ Example usage:
 			useCLI := cfg.NoTUI || cfg.Prompt != ""
 
 			if useCLI {
-				return runCLI(cfg)
+				return runCLI(cfg, nil)
 			}
 			return runTUI(cfg)
 		},
@@ -86,6 +96,11 @@ Example usage:
 	// Bind configuration flags
 	cfg.BindFlags(rootCmd)
 
+	// Add subcommands
+	rootCmd.AddCommand(newResumeCmd())
+	rootCmd.AddCommand(newRollbackCmd())
+	rootCmd.AddCommand(newReviewCmd())
+
 	// Add version flag
 	rootCmd.Flags().Bool("version", false, "Display version information and exit")
 
@@ -93,7 +108,7 @@ Example usage:
 }
 
 // printBanner displays the arcade-style startup banner.
-func printBanner() {
+func printBanner(implementer, reviewer string) {
 	banner := `
 ╔══════════════════════════════════════════════════════════════════════════╗
 ║                                                                          ║
@@ -117,7 +132,7 @@ func printBanner() {
 	fmt.Println()
 	successColor.Println("                         FIGHT!")
 	fmt.Println()
-	infoColor.Printf("       Claude Code vs Codex - Code Review Battle Arena\n")
+	infoColor.Printf("       %s vs %s - Code Review Battle Arena\n", implementer, reviewer)
 	infoColor.Printf("       Version: %s (built: %s)\n", Version, BuildTime)
 	fmt.Println()
 	fmt.Println("══════════════════════════════════════════════════════════════════════════")
@@ -136,6 +151,24 @@ func runTUI(cfg *config.Config) error {
 	if err := validateWorkDir(cfg); err != nil {
 		return err
 	}
+	if err := cfg.LoadProject(); err != nil {
+		return err
+	}
+	if err := cfg.ValidatePrompts(); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateConfirmations(); err != nil {
+		return err
+	}
+
+	// The event stream cannot share the terminal with the TUI
+	if err := cfg.ValidateEvents(); err != nil {
+		return err
+	}
+	if cfg.EventsToStdout() {
+		return errors.New("--events needs --events-file in TUI mode")
+	}
 
 	// Initialize logger (for file logging, even in TUI mode)
 	log, err := logger.New(cfg.OutputDir, cfg.Verbose)
@@ -147,17 +180,20 @@ func runTUI(cfg *config.Config) error {
 	// Create TUI model
 	model := tui.NewModel(cfg)
 
-	// Setup context
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle interrupt
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		cancel()
-	}()
+	// Offer to resume the most recent interrupted session
+	store := session.NewStore(cfg.OutputDir)
+	interrupted, err := store.LatestInterrupted()
+	if err != nil {
+		log.Error(fmt.Errorf("failed to look for interrupted sessions: %w", err))
+	}
+	if interrupted != nil {
+		model.SetResumeCandidate(&tui.ResumeCandidate{
+			SessionID: interrupted.ID,
+			Prompt:    interrupted.Prompt,
+			NextRound: interrupted.NextRound(),
+			UpdatedAt: interrupted.UpdatedAt,
+		})
+	}
 
 	// Create and run the program
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -170,6 +206,20 @@ func runTUI(cfg *config.Config) error {
 
 	m := finalModel.(tui.Model)
 
+	// Resume the interrupted session if requested
+	if m.ResumeRequested() && interrupted != nil {
+		interrupted.Settings.Apply(cfg)
+		return runBattleTUI(cfg, log, interrupted.Prompt, interrupted.ImagePath, interrupted)
+	}
+
+	// Don't offer a dismissed session again; it can still be resumed by ID
+	if m.ResumeDeclined() && interrupted != nil {
+		interrupted.State = types.StateAborted
+		if err := store.Save(interrupted); err != nil {
+			log.Error(fmt.Errorf("failed to dismiss session %s: %w", interrupted.ID, err))
+		}
+	}
+
 	// If battle wasn't started (user quit from prompt), exit gracefully
 	if !m.IsBattleStarted() {
 		return nil
@@ -181,13 +231,28 @@ func runTUI(cfg *config.Config) error {
 		return nil
 	}
 
-	// Get image path if one was attached
-	imagePath := m.GetImagePath()
-
 	// Now run the actual battle (TUI was just for input)
+	// Set the fighters chosen on the selection screen in config
+	cfg.Implementer = m.GetImplementerType()
+	cfg.Reviewers = m.GetReviewerTypes()
+
+	return runBattleTUI(cfg, log, prompt, m.GetImagePath(), nil)
+}
+
+// runBattleTUI runs the orchestrator alongside the battle TUI.
+// If cp is not nil, the battle continues from that checkpoint.
+func runBattleTUI(cfg *config.Config, log *logger.Logger, prompt, imagePath string, cp *session.Checkpoint) error {
 	// Set the prompt in config
 	cfg.Prompt = prompt
 
+	// Setup context
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle interrupt
+	stopInterrupts := handleInterrupts(ctx, cancel, log)
+	defer stopInterrupts()
+
 	// Create a new TUI model for battle phase
 	battleModel := tui.NewModel(cfg)
 	battleModel.SetBattleStarted(prompt)
@@ -196,11 +261,29 @@ func runTUI(cfg *config.Config) error {
 	// Create observer using the battle model's channels
 	observer := tui.NewChannelObserver(battleModel.GetEventChannel(), battleModel.GetResponseChannel())
 
+	// Write the event stream alongside the TUI, if requested
+	stream, err := openEventStream(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer closeEventStream(stream, log)
+
+	// Create the battle with observer
+	orch, err := newBattle(cfg, log, observer, cp)
+	if err != nil {
+		return err
+	}
+	stream.attach(orch)
+	setConfirmer(cfg, orch)
+	battleModel.SetFighterNames(orch.ImplementerName(), orch.ReviewerName())
+
+	if cp != nil {
+		battleModel.SetPreviousRounds(cp.Rounds)
+	}
+
 	// Enable silent mode on logger - TUI handles display
 	log.SetSilentMode(true)
-
-	// Create orchestrator with observer
-	orch := orchestrator.NewWithObserver(cfg, log, observer)
+	log.Info(fmt.Sprintf("Session: %s", orch.SessionID()))
 
 	// Set image path if one was attached
 	if imagePath != "" {
@@ -226,8 +309,15 @@ func runTUI(cfg *config.Config) error {
 		return fmt.Errorf("TUI error: %w", tuiErr)
 	}
 
-	// Wait for orchestrator to finish
-	<-done
+	// Quitting the TUI stops the battle; wait for the fighters to be stopped
+	// and the interrupted round to be recorded
+	select {
+	case <-done:
+	default:
+		cancel()
+		infoColor.Println(forceQuitHint)
+		<-done
+	}
 
 	// Generate report if we have results
 	if result != nil {
@@ -242,6 +332,7 @@ func runTUI(cfg *config.Config) error {
 			successColor.Printf("\nSession completed successfully in %d round(s)\n", result.TotalRounds)
 		} else {
 			infoColor.Printf("\nSession ended after %d round(s)\n", result.TotalRounds)
+			printResumeHint(result)
 		}
 
 		infoColor.Printf("Log file: %s\n", log.GetLogFilePath())
@@ -254,16 +345,14 @@ func runTUI(cfg *config.Config) error {
 	return nil
 }
 
-// runCLI runs the original CLI-based interface
-func runCLI(cfg *config.Config) error {
+// runCLI runs the original CLI-based interface.
+// If cp is not nil, the battle continues from that checkpoint.
+func runCLI(cfg *config.Config, cp *session.Checkpoint) error {
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return err
 	}
 
-	// Print banner and start
-	printBanner()
-
 	// Initialize logger
 	log, err := logger.New(cfg.OutputDir, cfg.Verbose)
 	if err != nil {
@@ -271,30 +360,60 @@ func runCLI(cfg *config.Config) error {
 	}
 	defer log.Close()
 
+	// Open the machine-readable event stream, if requested
+	stream, err := openEventStream(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer closeEventStream(stream, log)
+
+	// Initialize the battle with the selected fighters
+	orch, err := newBattle(cfg, log, nil, cp)
+	if err != nil {
+		return err
+	}
+	if stream != nil {
+		// Nobody is at the terminal to answer questions in a headless run
+		orch.SetResponder(orchestrator.AutoResponder{})
+		stream.attach(orch)
+	}
+	setConfirmer(cfg, orch)
+
+	return fightCLI(cfg, log, orch)
+}
+
+// fightCLI runs a battle in CLI mode and prints its summary.
+func fightCLI(cfg *config.Config, log *logger.Logger, orch battle) error {
+	// Stdout only carries the event stream when it is written there
+	quiet := cfg.EventsToStdout()
+
+	// Print banner and start
+	if !quiet {
+		printBanner(orch.ImplementerName(), orch.ReviewerName())
+	}
+
 	// Setup context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Handle interrupt signals
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		log.Info("Received interrupt signal, shutting down...")
-		cancel()
-	}()
+	stopInterrupts := handleInterrupts(ctx, cancel, log)
+	defer stopInterrupts()
 
 	// Log session start
+	log.Info(fmt.Sprintf("Session: %s", orch.SessionID()))
 	log.Info(fmt.Sprintf("Initial prompt: %s", cfg.Prompt))
 	log.Info(fmt.Sprintf("Working directory: %s", cfg.WorkDir))
 	log.Info(fmt.Sprintf("Max iterations: %d", cfg.MaxIterations))
-	fmt.Println()
+	log.Info(fmt.Sprintf("Fighters: %s vs %s", orch.ImplementerName(), orch.ReviewerName()))
+	if !quiet {
+		fmt.Println()
+	}
 
-	// Initialize and run orchestrator
-	orch := orchestrator.New(cfg, log)
+	// Run orchestrator; an interrupted session still has a result to report
 	result, err := orch.Run(ctx)
 
-	if err != nil {
+	if result == nil {
 		return err
 	}
 
@@ -305,19 +424,211 @@ func runCLI(cfg *config.Config) error {
 		log.Error(fmt.Errorf("failed to generate report: %w", reportErr))
 	}
 
+	if quiet {
+		if reportErr == nil {
+			log.Info(fmt.Sprintf("Report: %s", reportPath))
+		}
+		return err
+	}
+
 	// Print summary
 	if result.Success {
 		successColor.Printf("\nSession completed successfully in %d round(s)\n", result.TotalRounds)
 	} else {
 		infoColor.Printf("\nSession ended after %d round(s)\n", result.TotalRounds)
+		printResumeHint(result)
 	}
 
+	if result.StopReason != "" {
+		infoColor.Printf("Stop reason: %s\n", result.StopReason)
+	}
+	if result.Usage.CostUSD > 0 {
+		infoColor.Printf("Cost: $%.2f (%d input / %d output tokens)\n",
+			result.Usage.CostUSD, result.Usage.InputTokens, result.Usage.OutputTokens)
+	}
+	if result.Branch != "" {
+		infoColor.Printf("Branch: %s\n", result.Branch)
+	}
 	infoColor.Printf("Log file: %s\n", log.GetLogFilePath())
 	if reportErr == nil {
 		infoColor.Printf("Report: %s\n", reportPath)
 	}
 
-	return nil
+	return err
+}
+
+// battle is a session run by the CLI or the TUI: a single battle or a tournament.
+type battle interface {
+	Run(ctx context.Context) (*types.SessionResult, error)
+	SessionID() string
+	ImplementerName() string
+	ReviewerName() string
+	SetImagePath(imagePath string)
+	Events() *events.Bus
+	SetResponder(r orchestrator.Responder)
+	SetConfirmer(c confirm.Confirmer)
+}
+
+// setConfirmer has the confirmations of b answered by the endpoint set with
+// --approval-url, or with yes if --yes is set. Otherwise they are left to the
+// TUI or the terminal, or answered with their defaults when stdin is not one.
+func setConfirmer(cfg *config.Config, b battle) {
+	switch {
+	case cfg.ApprovalURL != "":
+		b.SetConfirmer(confirm.NewHTTP(cfg.ApprovalURL, confirm.DefaultHTTPTimeout))
+	case cfg.Yes:
+		b.SetConfirmer(confirm.Fixed(true))
+	}
+}
+
+// newBattle creates the battle configured in cfg: a tournament when several
+// contenders are set, or a single battle continuing from cp if it is not nil.
+func newBattle(cfg *config.Config, log *logger.Logger, observer orchestrator.Observer, cp *session.Checkpoint) (battle, error) {
+	if len(cfg.Contenders) > 0 {
+		if cp != nil {
+			return nil, fmt.Errorf("session %s cannot be resumed as a tournament", cp.ID)
+		}
+		return orchestrator.NewTournament(cfg, log, observer)
+	}
+
+	orch, err := orchestrator.NewWithObserver(cfg, log, observer)
+	if err != nil {
+		return nil, err
+	}
+	if cp != nil {
+		orch.Resume(cp)
+	}
+	return orch, nil
+}
+
+// newResumeCmd creates the command that continues an interrupted session.
+func newResumeCmd() *cobra.Command {
+	cfg := config.New()
+
+	cmd := &cobra.Command{
+		Use:   "resume [session-id]",
+		Short: "Resume an interrupted session from its last checkpoint",
+		Long: `Resume continues a session from the round after its last checkpoint,
+feeding the issues of the last review back to the implementer.
+
+Without a session ID the most recently interrupted session is resumed.`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateWorkDir(cfg); err != nil {
+				return err
+			}
+
+			store := session.NewStore(cfg.OutputDir)
+
+			var cp *session.Checkpoint
+			var err error
+			if len(args) == 1 {
+				cp, err = store.Load(args[0])
+			} else {
+				cp, err = store.LatestInterrupted()
+				if err == nil && cp == nil {
+					err = fmt.Errorf("no interrupted session found in %s", cfg.OutputDir)
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			if !cp.Resumable() {
+				return fmt.Errorf("session %s already completed", cp.ID)
+			}
+
+			cp.Settings.Apply(cfg)
+			cfg.Prompt = cp.Prompt
+			cfg.NoTUI = true
+			return runCLI(cfg, cp)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&cfg.WorkDir, "dir", "d", ".",
+		"Working directory of the session")
+	flags.StringVarP(&cfg.OutputDir, "output", "o", config.DefaultOutputDir,
+		"Directory for logs and reports")
+	flags.BoolVarP(&cfg.Verbose, "verbose", "v", false,
+		"Enable verbose/detailed output")
+	flags.BoolVarP(&cfg.Yes, "yes", "y", false,
+		"Answer yes to every confirmation instead of asking")
+	flags.StringVar(&cfg.ApprovalURL, "approval-url", "",
+		"Endpoint that answers confirmations instead of the user")
+
+	return cmd
+}
+
+// newRollbackCmd creates the command that restores the working tree to a round snapshot.
+func newRollbackCmd() *cobra.Command {
+	cfg := config.New()
+	var sessionID string
+	var round int
+
+	cmd := &cobra.Command{
+		Use:   "rollback --round N",
+		Short: "Restore the working tree to the snapshot taken after a round",
+		Long: `Rollback restores the working tree and index to the snapshot recorded after
+the given round, dropping the changes of every later round.
+
+The session checkpoint is truncated to that round, so "mortal-prompter resume"
+continues with the following round. Later snapshots are kept under
+refs/mortal-prompter/<session>/ and can still be restored.
+
+Without --session the most recently updated session is used.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateWorkDir(cfg); err != nil {
+				return err
+			}
+
+			store := session.NewStore(cfg.OutputDir)
+
+			if sessionID == "" {
+				latest, err := store.Latest()
+				if err != nil {
+					return err
+				}
+				if latest == nil {
+					return fmt.Errorf("no session found in %s", cfg.OutputDir)
+				}
+				sessionID = latest.ID
+			}
+
+			cp, err := store.Rollback(sessionID, round)
+			if err != nil {
+				return err
+			}
+
+			successColor.Printf("Restored session %s to round %d\n", cp.ID, round)
+			infoColor.Printf("Resume with: mortal-prompter resume %s\n", cp.ID)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&round, "round", 0, "Round to restore (required)")
+	flags.StringVar(&sessionID, "session", "", "Session to roll back (default: most recent)")
+	flags.StringVarP(&cfg.WorkDir, "dir", "d", ".",
+		"Working directory of the session")
+	flags.StringVarP(&cfg.OutputDir, "output", "o", config.DefaultOutputDir,
+		"Directory for logs and reports")
+	_ = cmd.MarkFlagRequired("round")
+
+	return cmd
+}
+
+// printResumeHint tells the user how to continue an unfinished session.
+func printResumeHint(result *types.SessionResult) {
+	if result.SessionID == "" {
+		return
+	}
+	infoColor.Printf("Resume with: mortal-prompter resume %s\n", result.SessionID)
 }
 
 // validateWorkDir validates and resolves the working directory
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/logger"
+	"github.com/diegoram/mortal-prompter/internal/orchestrator"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// newReviewCmd creates the command that runs the reviewers on existing changes.
+func newReviewCmd() *cobra.Command {
+	cfg := config.New()
+	var target orchestrator.ReviewTarget
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review existing changes, commits or a branch without an implementer",
+		Long: `Review runs the reviewer half of the battle on work done by hand: the
+uncommitted changes of the working tree (staged first, as in a battle round),
+a range of commits, or the changes of the current branch since a base branch.
+
+The issues found are printed grouped by severity and file, and the command
+exits with a non-zero status if any is at or above the --fail-on severity.
+With --fix the issues are handed to the implementer and the normal battle
+loop continues until the reviewers approve.
+
+Example usage:
+  mortal-prompter review
+  mortal-prompter review --commits HEAD~3..HEAD --reviewer codex,gemini
+  mortal-prompter review --base main --fail-on high
+  mortal-prompter review --fix --implementer claude`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(cfg.Contenders) > 0 {
+				return errors.New("--tournament cannot be used with review")
+			}
+			if fix && cfg.Worktree && target.Uncommitted() {
+				return errors.New("--worktree cannot fix uncommitted changes: commit them or review a commit range or base branch")
+			}
+
+			cfg.NoTUI = true
+			if cfg.Prompt == "" {
+				cfg.Prompt = target.FixPrompt()
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+
+			log, err := logger.New(cfg.OutputDir, cfg.Verbose)
+			if err != nil {
+				return fmt.Errorf("failed to initialize logger: %w", err)
+			}
+			defer log.Close()
+
+			stream, err := openEventStream(cfg, log)
+			if err != nil {
+				return err
+			}
+			defer closeEventStream(stream, log)
+
+			orch, err := orchestrator.New(cfg, log)
+			if err != nil {
+				return err
+			}
+			if stream != nil {
+				orch.SetResponder(orchestrator.AutoResponder{})
+				stream.attach(orch)
+			}
+			setConfirmer(cfg, orch)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopInterrupts := handleInterrupts(ctx, cancel, log)
+			round, err := orch.ReviewChanges(ctx, target)
+			stopInterrupts()
+			cancel()
+			if err != nil {
+				return err
+			}
+
+			if !cfg.EventsToStdout() {
+				printReview(round, target)
+			}
+
+			if !round.HasIssues {
+				return nil
+			}
+			if !fix {
+				return fmt.Errorf("review found %d issue(s) in %s", len(round.Issues), target)
+			}
+
+			log.Info(fmt.Sprintf("Handing %d issue(s) to %s", len(round.Issues), orch.ImplementerName()))
+			orch.SetPendingIssues(round.Issues)
+			return fightCLI(cfg, log, orch)
+		},
+	}
+
+	cfg.BindFlags(cmd)
+
+	flags := cmd.Flags()
+	flags.StringVar(&target.Commits, "commits", "",
+		"Review a revision range, e.g. HEAD~3..HEAD, or a single commit")
+	flags.StringVar(&target.Base, "base", "",
+		"Review the changes of the current branch since it forked from this branch")
+	flags.BoolVar(&fix, "fix", false,
+		"Hand the issues found to the implementer and continue with the battle loop")
+
+	return cmd
+}
+
+// printReview prints the issues of a review grouped by severity and file.
+func printReview(round *types.Round, target orchestrator.ReviewTarget) {
+	fmt.Println()
+	if len(round.Findings) == 0 {
+		successColor.Printf("%s found no issues in %s\n", round.Reviewer, target)
+		return
+	}
+
+	if round.HasIssues {
+		errorColor.Printf("%s found %d issue(s) in %s\n", round.Reviewer, len(round.Findings), target)
+	} else {
+		successColor.Printf("%s approved %s with %d non-blocking issue(s)\n", round.Reviewer, target, len(round.Findings))
+	}
+
+	issues := append([]types.Issue(nil), round.Findings...)
+	types.SortIssues(issues)
+	attribute := len(round.Reviews) > 1
+
+	for i, issue := range issues {
+		newSeverity := i == 0 || issue.Severity != issues[i-1].Severity
+		if newSeverity {
+			fmt.Println()
+			severity := strings.ToUpper(string(issue.Severity))
+			if issue.Severity.AtLeast(types.SeverityHigh) {
+				errorColor.Println(severity)
+			} else {
+				titleColor.Println(severity)
+			}
+		}
+		if newSeverity || issue.File != issues[i-1].File {
+			file := issue.File
+			if file == "" {
+				file = "(general)"
+			}
+			infoColor.Printf("  %s\n", file)
+		}
+
+		line := issue.Description
+		if issue.Category != "" {
+			line = fmt.Sprintf("[%s] %s", issue.Category, line)
+		}
+		if issue.StartLine > 0 {
+			lines := fmt.Sprintf("L%d", issue.StartLine)
+			if issue.EndLine > issue.StartLine {
+				lines += fmt.Sprintf("-%d", issue.EndLine)
+			}
+			line = lines + "  " + line
+		}
+		if attribute && issue.Reviewer != "" {
+			line += fmt.Sprintf(" (%s)", issue.Reviewer)
+		}
+		fmt.Printf("    %s\n", strings.ReplaceAll(line, "\n", "\n    "))
+	}
+	fmt.Println()
+}
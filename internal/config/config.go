@@ -6,9 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -17,8 +24,98 @@ const (
 	DefaultMaxIterations = 10
 	DefaultOutputDir     = ".mortal-prompter"
 	DefaultCommitMessage = "feat: implemented via mortal-prompter"
+	DefaultOnStall       = StallActionAsk
+	DefaultOnMaxIter     = MaxIterationsAsk
+	DefaultOnNoChanges   = NoChangesRetry
+	DefaultOnDirty       = DirtyKeep
+	DefaultFailOn        = types.SeverityLow
+
+	// DefaultReviewChunkSize is the largest diff, in bytes, reviewed at once
+	DefaultReviewChunkSize = 100 * 1024
 )
 
+// Stall actions decide what happens when the battle stops making progress
+const (
+	// StallActionStop ends the session in the stalled state
+	StallActionStop = "stop"
+
+	// StallActionAsk asks the user whether to keep going
+	StallActionAsk = "ask"
+)
+
+// No-changes actions decide what happens when the implementer leaves the tree unchanged
+const (
+	// NoChangesRetry runs another round, nudging the implementer to make changes
+	NoChangesRetry = "retry"
+
+	// NoChangesFail ends the session as failed
+	NoChangesFail = "fail"
+
+	// NoChangesAsk asks the user whether to run another round
+	NoChangesAsk = "ask"
+)
+
+// Dirty-tree actions decide what happens to uncommitted changes found in the
+// working tree when a session starts
+const (
+	// DirtyKeep leaves the changes in place; only the session's own changes
+	// are reviewed and committed
+	DirtyKeep = "keep"
+
+	// DirtyStash stashes the changes for the session and restores them after it
+	DirtyStash = "stash"
+
+	// DirtyRefuse refuses to start the session
+	DirtyRefuse = "refuse"
+
+	// DirtyAsk asks whether to stash the changes, refusing to start if not
+	DirtyAsk = "ask"
+)
+
+// Max-iterations actions decide what happens when the battle reaches --max-iterations
+const (
+	// MaxIterationsAbort ends the session without asking
+	MaxIterationsAbort = "abort"
+
+	// MaxIterationsContinue runs a fixed number of extra rounds without asking ("continue:N")
+	MaxIterationsContinue = "continue"
+
+	// MaxIterationsAsk asks whether to run each extra round
+	MaxIterationsAsk = "ask"
+)
+
+// MaxIterationsPolicy is a parsed --on-max-iterations value.
+type MaxIterationsPolicy struct {
+	Action string
+
+	// Rounds is the number of extra rounds of the continue action
+	Rounds int
+}
+
+// ParseMaxIterationsPolicy parses an --on-max-iterations value: "abort",
+// "continue:N" or "ask".
+func ParseMaxIterationsPolicy(s string) (MaxIterationsPolicy, error) {
+	spec := strings.ToLower(strings.TrimSpace(s))
+
+	switch {
+	case spec == MaxIterationsAbort:
+		return MaxIterationsPolicy{Action: MaxIterationsAbort}, nil
+	case spec == MaxIterationsAsk:
+		return MaxIterationsPolicy{Action: MaxIterationsAsk}, nil
+	case strings.HasPrefix(spec, MaxIterationsContinue+":"):
+		n, err := strconv.Atoi(strings.TrimPrefix(spec, MaxIterationsContinue+":"))
+		if err != nil || n < 1 {
+			return MaxIterationsPolicy{}, fmt.Errorf("invalid number of rounds in on-max-iterations %q: must be a positive number", s)
+		}
+		return MaxIterationsPolicy{Action: MaxIterationsContinue, Rounds: n}, nil
+	default:
+		return MaxIterationsPolicy{}, fmt.Errorf("invalid on-max-iterations action: %s (valid: abort, continue:N, ask)", s)
+	}
+}
+
+// EventsJSON is the --events format writing newline-delimited JSON
+const EventsJSON = "json"
+
 // Config holds all configuration options for mortal-prompter.
 type Config struct {
 	// Prompt is the initial prompt to send to the implementer (required in CLI mode)
@@ -48,25 +145,158 @@ type Config struct {
 	// NoTUI disables the TUI and uses CLI mode instead
 	NoTUI bool
 
-	// Implementer is the fighter type used as implementer (claude, codex, gemini)
+	// Worktree runs the session on its own branch in a separate git worktree
+	Worktree bool
+
+	// OnStall decides what happens when the battle stops making progress (stop, ask)
+	OnStall string
+
+	// OnMaxIterations decides what happens when MaxIterations is reached (abort, continue:N, ask)
+	OnMaxIterations string
+
+	// OnNoChanges decides what happens when the implementer makes no changes (retry, fail, ask)
+	OnNoChanges string
+
+	// OnDirty decides what happens when the working tree has uncommitted changes at start (keep, stash, refuse, ask)
+	OnDirty string
+
+	// Yes answers yes to every confirmation instead of asking
+	Yes bool
+
+	// ApprovalURL is an endpoint that answers the confirmations instead of the user
+	ApprovalURL string
+
+	// FighterTimeout is the time limit for a single fighter execution
+	FighterTimeout time.Duration
+
+	// RoundTimeout is the time limit for a whole round (0 means no limit)
+	RoundTimeout time.Duration
+
+	// SessionTimeout is the wall-clock limit for the session (0 means no limit)
+	SessionTimeout time.Duration
+
+	// MaxCost is the spend limit in US dollars for fighters that report usage (0 means no limit)
+	MaxCost float64
+
+	// Verify are shell commands that must pass after each implementer run
+	// before the changes are sent to review (e.g. "go test ./...")
+	Verify []string
+
+	// ReviewChunkSize is the largest diff in bytes reviewed at once; larger
+	// diffs are reviewed in parts (0 means never split)
+	ReviewChunkSize int
+
+	// ParallelChunks reviews the parts of a large diff concurrently
+	ParallelChunks bool
+
+	// Implementer is the fighter type used as implementer (claude, codex,
+	// gemini, or a command fighter of the project file)
 	Implementer fighters.FighterType
 
-	// Reviewer is the fighter type used as reviewer (claude, codex, gemini)
-	Reviewer fighters.FighterType
+	// Reviewers are the fighter types on the review panel (claude, codex,
+	// gemini, or command fighters of the project file)
+	Reviewers []fighters.FighterType
+
+	// ReviewPolicy decides how the reviewer verdicts are combined (any, all, quorum:N)
+	ReviewPolicy string
+
+	// FailOn is the lowest severity of the issues that keep the battle going;
+	// a round with only less serious issues passes (critical, high, medium, low)
+	FailOn types.Severity
+
+	// Contenders are the implementers competing in tournament mode; the same
+	// fighter may be listed more than once for several independent runs
+	Contenders []fighters.FighterType
+
+	// Judge is the fighter that picks the winner of a tournament
+	Judge fighters.FighterType
+
+	// Plan enables the planning phase: before round 1 a plan is drafted,
+	// critiqued by the reviewers and approved by the user
+	Plan bool
+
+	// Planner is the fighter that drafts the plan (empty means the implementer)
+	Planner fighters.FighterType
+
+	// Events is the format of the machine-readable event stream (json), empty for none
+	Events string
+
+	// EventsFile is the file the event stream is written to (empty or "-" means stdout)
+	EventsFile string
+
+	// Hooks are the shell commands run at each point of the session, read
+	// from the project file
+	Hooks map[hooks.Point][]string
+
+	// Paths are the glob patterns limiting the paths staged, reviewed and
+	// committed, read from the project file
+	Paths types.PathRules
+
+	// Guardrails are the protected paths and change-size limits checked after
+	// each implementer run, read from the project file
+	Guardrails guard.Rules
+
+	// PromptLanguage is the shipped set of prompt templates (en, es), empty
+	// for the built-in templates
+	PromptLanguage string
+
+	// Prompts are the prompt templates of all fighters, read from the
+	// template files of the project file
+	Prompts prompts.Overrides
+
+	// FighterPrompts are the prompt templates of single fighters, read from
+	// the template files of the project file
+	FighterPrompts map[fighters.FighterType]prompts.Overrides
 }
 
 // New creates a new Config with default values.
 func New() *Config {
 	return &Config{
-		WorkDir:       ".",
-		MaxIterations: DefaultMaxIterations,
-		OutputDir:     DefaultOutputDir,
-		CommitMessage: DefaultCommitMessage,
-		Implementer:   fighters.FighterTypeClaude,
-		Reviewer:      fighters.FighterTypeCodex,
+		WorkDir:         ".",
+		MaxIterations:   DefaultMaxIterations,
+		OutputDir:       DefaultOutputDir,
+		CommitMessage:   DefaultCommitMessage,
+		Implementer:     fighters.FighterTypeClaude,
+		Reviewers:       []fighters.FighterType{fighters.FighterTypeCodex},
+		ReviewPolicy:    review.DefaultPolicy,
+		FailOn:          DefaultFailOn,
+		OnStall:         DefaultOnStall,
+		OnMaxIterations: DefaultOnMaxIter,
+		OnNoChanges:     DefaultOnNoChanges,
+		OnDirty:         DefaultOnDirty,
+		FighterTimeout:  fighters.DefaultTimeout,
+		ReviewChunkSize: DefaultReviewChunkSize,
+		Judge:           fighters.FighterTypeClaude,
 	}
 }
 
+// PlannerType returns the fighter that drafts the plan: the configured
+// planner, or the implementer if none is set.
+func (c *Config) PlannerType() fighters.FighterType {
+	if c.Planner != "" {
+		return c.Planner
+	}
+	return c.Implementer
+}
+
+// PromptTemplates returns the templates the prompts of fighter ft are rendered
+// from: the built-in templates, replaced by those of the prompt language, then
+// by the project's templates for all fighters, then by those for ft.
+func (c *Config) PromptTemplates(ft fighters.FighterType) (*prompts.Templates, error) {
+	overrides := make(prompts.Overrides, len(c.Prompts)+len(c.FighterPrompts[ft]))
+	for name, text := range c.Prompts {
+		overrides[name] = text
+	}
+	for name, text := range c.FighterPrompts[ft] {
+		overrides[name] = text
+	}
+	templates, err := prompts.Load(c.PromptLanguage, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt templates for %s: %w", ft, err)
+	}
+	return templates, nil
+}
+
 // BindFlags binds the configuration flags to a Cobra command.
 // This sets up all CLI flags and their descriptions.
 func (c *Config) BindFlags(cmd *cobra.Command) {
@@ -99,40 +329,177 @@ func (c *Config) BindFlags(cmd *cobra.Command) {
 	flags.BoolVar(&c.NoTUI, "no-tui", false,
 		"Disable TUI and use CLI mode (requires -p/--prompt)")
 
+	flags.BoolVar(&c.Worktree, "worktree", false,
+		"Run the session on its own branch in an isolated git worktree")
+
+	flags.StringVar(&c.OnStall, "on-stall", DefaultOnStall,
+		"What to do when rounds stop making progress (stop, ask)")
+
+	flags.StringVar(&c.OnMaxIterations, "on-max-iterations", DefaultOnMaxIter,
+		"What to do when max-iterations is reached (abort, continue:N, ask)")
+
+	flags.StringVar(&c.OnNoChanges, "on-no-changes", DefaultOnNoChanges,
+		"What to do when the implementer makes no changes (retry, fail, ask)")
+
+	flags.StringVar(&c.OnDirty, "on-dirty", DefaultOnDirty,
+		"What to do with uncommitted changes in the working tree at start (keep, stash, refuse, ask)")
+
+	flags.BoolVarP(&c.Yes, "yes", "y", false,
+		"Answer yes to every confirmation instead of asking")
+
+	flags.StringVar(&c.ApprovalURL, "approval-url", "",
+		"Endpoint that answers confirmations: each question is POSTed as JSON and it replies {\"approved\": bool}")
+
+	flags.DurationVar(&c.FighterTimeout, "fighter-timeout", fighters.DefaultTimeout,
+		"Time limit for a single fighter execution")
+
+	flags.DurationVar(&c.RoundTimeout, "round-timeout", 0,
+		"Time limit for a whole round, e.g. 15m (0 means no limit)")
+
+	flags.DurationVar(&c.SessionTimeout, "session-timeout", 0,
+		"Wall-clock limit for the session, e.g. 1h (0 means no limit)")
+
+	flags.Float64Var(&c.MaxCost, "max-cost", 0,
+		"Spend limit in USD for fighters that report usage (0 means no limit)")
+
+	flags.StringArrayVar(&c.Verify, "verify", nil,
+		"Command that must pass before each review, e.g. \"go test ./...\" (repeatable)")
+
+	flags.IntVar(&c.ReviewChunkSize, "review-chunk-size", DefaultReviewChunkSize,
+		"Largest diff in bytes reviewed at once; larger diffs are reviewed in parts plus a cross-file pass (0 means never split)")
+
+	flags.BoolVar(&c.ParallelChunks, "parallel-chunks", false,
+		"Review the parts of a large diff concurrently")
+
+	flags.StringVar(&c.PromptLanguage, "prompt-language", "",
+		"Language of the prompts sent to the fighters ("+strings.Join(prompts.Languages(), ", ")+"; default: the built-in prompts)")
+
 	// Fighter selection flags
-	var implementer, reviewer string
+	var implementer string
+	var reviewers []string
 	flags.StringVar(&implementer, "implementer", "claude",
-		"Fighter to use as implementer (claude, codex, gemini)")
-	flags.StringVar(&reviewer, "reviewer", "codex",
-		"Fighter to use as reviewer (claude, codex, gemini)")
+		"Fighter to use as implementer (claude, codex, gemini, or a command fighter of the project file)")
+	flags.StringSliceVar(&reviewers, "reviewer", []string{"codex"},
+		"Fighter(s) to use as reviewer, comma-separated for a review panel (claude, codex, gemini, or command fighters of the project file)")
+
+	flags.StringVar(&c.ReviewPolicy, "review-policy", review.DefaultPolicy,
+		"How a review panel decides a round has issues (any, all, quorum:N)")
+
+	var failOn string
+	flags.StringVar(&failOn, "fail-on", string(DefaultFailOn),
+		"Lowest issue severity that fails a round; less serious issues are reported but accepted (critical, high, medium, low)")
+
+	flags.BoolVar(&c.Plan, "plan", false,
+		"Draft and approve a numbered plan before the first round; every round is checked against it")
+
+	var planner string
+	flags.StringVar(&planner, "planner", "",
+		"Fighter that drafts the plan (claude, codex, gemini, or a command fighter of the project file; default: the implementer)")
+
+	flags.StringVar(&c.Events, "events", "",
+		"Write every session event to stdout (or --events-file) in a machine-readable format (json)")
+
+	flags.StringVar(&c.EventsFile, "events-file", "",
+		"File to write the --events stream to instead of stdout")
+
+	var contenders []string
+	var judge string
+	flags.StringSliceVar(&contenders, "tournament", nil,
+		"Implementers competing in parallel worktrees, comma-separated (e.g. claude,codex or claude,claude)")
+	flags.StringVar(&judge, "judge", "claude",
+		"Fighter that compares the tournament results and picks the winner (claude, codex, gemini, or a command fighter of the project file)")
 
 	// Store the string values to be parsed in a PreRun hook
 	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		// The fighters may be command fighters of the project file, so it
+		// is loaded first; loading it again later changes nothing. A bad
+		// working directory is reported by Validate
+		if info, err := os.Stat(c.WorkDir); err == nil && info.IsDir() {
+			if err := c.LoadProject(); err != nil {
+				return err
+			}
+		}
+
 		var err error
 		c.Implementer, err = parseFighterType(implementer)
 		if err != nil {
 			return fmt.Errorf("invalid implementer: %w", err)
 		}
-		c.Reviewer, err = parseFighterType(reviewer)
+		c.Reviewers, err = parseFighterTypes(reviewers)
 		if err != nil {
 			return fmt.Errorf("invalid reviewer: %w", err)
 		}
+		c.FailOn, err = types.ParseSeverity(failOn)
+		if err != nil {
+			return fmt.Errorf("invalid fail-on: %w", err)
+		}
+		c.Contenders, err = parseContenders(contenders)
+		if err != nil {
+			return fmt.Errorf("invalid tournament: %w", err)
+		}
+		c.Judge, err = parseFighterType(judge)
+		if err != nil {
+			return fmt.Errorf("invalid judge: %w", err)
+		}
+		if planner != "" {
+			c.Planner, err = parseFighterType(planner)
+			if err != nil {
+				return fmt.Errorf("invalid planner: %w", err)
+			}
+		}
 		return nil
 	}
 }
 
-// parseFighterType converts a string to a FighterType
+// parseFighterTypes converts a list of strings to distinct FighterTypes
+func parseFighterTypes(values []string) ([]fighters.FighterType, error) {
+	result := make([]fighters.FighterType, 0, len(values))
+	seen := make(map[fighters.FighterType]bool)
+	for _, v := range values {
+		ft, err := parseFighterType(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		if seen[ft] {
+			return nil, fmt.Errorf("fighter %s listed more than once", ft)
+		}
+		seen[ft] = true
+		result = append(result, ft)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("at least one fighter is required")
+	}
+	return result, nil
+}
+
+// parseContenders converts a list of strings to FighterTypes, allowing repeats
+func parseContenders(values []string) ([]fighters.FighterType, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make([]fighters.FighterType, 0, len(values))
+	for _, v := range values {
+		ft, err := parseFighterType(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ft)
+	}
+	return result, nil
+}
+
+// parseFighterType converts a string to a FighterType, built in or a
+// command fighter of the project file
 func parseFighterType(s string) (fighters.FighterType, error) {
-	switch strings.ToLower(s) {
-	case "claude":
-		return fighters.FighterTypeClaude, nil
-	case "codex":
-		return fighters.FighterTypeCodex, nil
-	case "gemini":
-		return fighters.FighterTypeGemini, nil
-	default:
-		return "", fmt.Errorf("unknown fighter type: %s (valid: claude, codex, gemini)", s)
+	all := fighters.AllFighterTypes()
+	names := make([]string, len(all))
+	for i, ft := range all {
+		if string(ft) == strings.ToLower(s) {
+			return ft, nil
+		}
+		names[i] = string(ft)
 	}
+	return "", fmt.Errorf("unknown fighter type: %s (valid: %s)", s, strings.Join(names, ", "))
 }
 
 // Validate checks that the configuration is valid and returns an error if not.
@@ -146,6 +513,44 @@ func (c *Config) Validate() error {
 		return errors.New("max-iterations must be at least 1")
 	}
 
+	if c.FighterTimeout <= 0 {
+		return errors.New("fighter-timeout must be positive")
+	}
+
+	if c.RoundTimeout < 0 || c.SessionTimeout < 0 {
+		return errors.New("round-timeout and session-timeout cannot be negative")
+	}
+
+	if c.MaxCost < 0 {
+		return errors.New("max-cost cannot be negative")
+	}
+
+	if c.ReviewChunkSize < 0 {
+		return errors.New("review-chunk-size cannot be negative")
+	}
+
+	if len(c.Contenders) == 1 {
+		return errors.New("a tournament needs at least two contenders")
+	}
+
+	if c.OnStall != StallActionStop && c.OnStall != StallActionAsk {
+		return fmt.Errorf("invalid on-stall action: %s (valid: stop, ask)", c.OnStall)
+	}
+
+	if err := c.ValidateConfirmations(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateEvents(); err != nil {
+		return err
+	}
+
+	// The panel size is only known once the fighters are chosen, so the
+	// quorum itself is checked when the orchestrator builds the panel
+	if _, err := review.ParsePolicy(c.ReviewPolicy); err != nil {
+		return err
+	}
+
 	// Resolve and validate working directory
 	absWorkDir, err := filepath.Abs(c.WorkDir)
 	if err != nil {
@@ -172,9 +577,67 @@ func (c *Config) Validate() error {
 		c.OutputDir = filepath.Join(c.WorkDir, c.OutputDir)
 	}
 
+	if err := c.LoadProject(); err != nil {
+		return err
+	}
+	return c.ValidatePrompts()
+}
+
+// ValidatePrompts checks the prompt language and the project's prompt
+// templates for every fighter. It is part of Validate and is also used in TUI
+// mode, once the project file is loaded.
+func (c *Config) ValidatePrompts() error {
+	if !prompts.ValidLanguage(c.PromptLanguage) {
+		return fmt.Errorf("invalid prompt-language: %s (valid: %s)", c.PromptLanguage, strings.Join(prompts.Languages(), ", "))
+	}
+	for _, ft := range fighters.AllFighterTypes() {
+		if _, err := c.PromptTemplates(ft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateEvents checks the event stream options. It is part of Validate and
+// is also used in TUI mode, where the rest of Validate does not apply.
+func (c *Config) ValidateEvents() error {
+	if c.Events != "" && c.Events != EventsJSON {
+		return fmt.Errorf("invalid events format: %s (valid: json)", c.Events)
+	}
+	if c.EventsFile != "" && c.Events == "" {
+		return errors.New("events-file requires --events")
+	}
 	return nil
 }
 
+// ValidateConfirmations checks the options deciding the confirmations. It is
+// part of Validate and is also used in TUI mode.
+func (c *Config) ValidateConfirmations() error {
+	if _, err := ParseMaxIterationsPolicy(c.OnMaxIterations); err != nil {
+		return err
+	}
+	switch c.OnNoChanges {
+	case NoChangesRetry, NoChangesFail, NoChangesAsk:
+	default:
+		return fmt.Errorf("invalid on-no-changes action: %s (valid: retry, fail, ask)", c.OnNoChanges)
+	}
+	switch c.OnDirty {
+	case DirtyKeep, DirtyStash, DirtyRefuse, DirtyAsk:
+	default:
+		return fmt.Errorf("invalid on-dirty action: %s (valid: keep, stash, refuse, ask)", c.OnDirty)
+	}
+	if c.Yes && c.ApprovalURL != "" {
+		return errors.New("--yes and --approval-url cannot be used together")
+	}
+	return nil
+}
+
+// EventsToStdout reports whether the event stream is written to stdout, in
+// which case nothing else may be printed there.
+func (c *Config) EventsToStdout() bool {
+	return c.Events != "" && (c.EventsFile == "" || c.EventsFile == "-")
+}
+
 // EnsureOutputDir creates the output directory if it doesn't exist.
 func (c *Config) EnsureOutputDir() error {
 	return os.MkdirAll(c.OutputDir, 0755)
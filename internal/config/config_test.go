@@ -3,8 +3,12 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +36,12 @@ func TestNew(t *testing.T) {
 	if cfg.AutoCommit {
 		t.Error("expected AutoCommit to be false")
 	}
+	if len(cfg.Reviewers) != 1 || cfg.Reviewers[0] != fighters.FighterTypeCodex {
+		t.Errorf("expected Reviewers to be [codex], got %v", cfg.Reviewers)
+	}
+	if cfg.ReviewPolicy != "any" {
+		t.Errorf("expected ReviewPolicy to be any, got %q", cfg.ReviewPolicy)
+	}
 }
 
 func TestValidate_MissingPrompt(t *testing.T) {
@@ -126,7 +136,7 @@ func TestBindFlags(t *testing.T) {
 	cfg.BindFlags(cmd)
 
 	// Test that all flags are registered
-	flags := []string{"prompt", "dir", "max-iterations", "interactive", "verbose", "output", "auto-commit", "commit-message", "no-tui"}
+	flags := []string{"prompt", "dir", "max-iterations", "interactive", "verbose", "output", "auto-commit", "commit-message", "no-tui", "worktree", "on-stall", "verify", "fighter-timeout", "round-timeout", "session-timeout", "max-cost", "tournament", "judge", "implementer", "reviewer", "review-policy"}
 	for _, flag := range flags {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("expected flag %q to be registered", flag)
@@ -152,6 +162,322 @@ func TestBindFlags(t *testing.T) {
 	}
 }
 
+func TestBindFlags_ReviewerPanel(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--reviewer", "codex,gemini", "--review-policy", "quorum:2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(cfg.Reviewers) != 2 || cfg.Reviewers[0] != fighters.FighterTypeCodex || cfg.Reviewers[1] != fighters.FighterTypeGemini {
+		t.Errorf("expected reviewers [codex gemini], got %v", cfg.Reviewers)
+	}
+	if cfg.ReviewPolicy != "quorum:2" {
+		t.Errorf("expected ReviewPolicy to be quorum:2, got %q", cfg.ReviewPolicy)
+	}
+}
+
+func TestBindFlags_Verify(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--verify", "go build ./...", "--verify", "go test -run 'A,B' ./..."})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"go build ./...", "go test -run 'A,B' ./..."}
+	if len(cfg.Verify) != len(want) || cfg.Verify[0] != want[0] || cfg.Verify[1] != want[1] {
+		t.Errorf("expected Verify %q, got %q", want, cfg.Verify)
+	}
+}
+
+func TestBindFlags_DuplicateReviewer(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--reviewer", "codex,codex"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for duplicate reviewer")
+	}
+}
+
+func TestBindFlags_Budgets(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--fighter-timeout", "2m", "--round-timeout", "15m", "--session-timeout", "1h", "--max-cost", "2.5"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if cfg.FighterTimeout != 2*time.Minute || cfg.RoundTimeout != 15*time.Minute ||
+		cfg.SessionTimeout != time.Hour || cfg.MaxCost != 2.5 {
+		t.Errorf("budgets = %v/%v/%v/%v", cfg.FighterTimeout, cfg.RoundTimeout, cfg.SessionTimeout, cfg.MaxCost)
+	}
+}
+
+func TestValidate_InvalidBudgets(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"zero fighter timeout", func(c *Config) { c.FighterTimeout = 0 }},
+		{"negative round timeout", func(c *Config) { c.RoundTimeout = -time.Minute }},
+		{"negative session timeout", func(c *Config) { c.SessionTimeout = -time.Minute }},
+		{"negative max cost", func(c *Config) { c.MaxCost = -1 }},
+		{"negative review chunk size", func(c *Config) { c.ReviewChunkSize = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestBindFlags_Tournament(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--tournament", "claude,claude,codex", "--judge", "gemini"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []fighters.FighterType{fighters.FighterTypeClaude, fighters.FighterTypeClaude, fighters.FighterTypeCodex}
+	if len(cfg.Contenders) != len(want) {
+		t.Fatalf("expected contenders %v, got %v", want, cfg.Contenders)
+	}
+	for i := range want {
+		if cfg.Contenders[i] != want[i] {
+			t.Errorf("contender %d = %s, want %s", i, cfg.Contenders[i], want[i])
+		}
+	}
+	if cfg.Judge != fighters.FighterTypeGemini {
+		t.Errorf("expected judge gemini, got %s", cfg.Judge)
+	}
+}
+
+func TestBindFlags_Plan(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--plan", "--implementer", "codex"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !cfg.Plan {
+		t.Error("expected the planning phase to be enabled")
+	}
+	if cfg.PlannerType() != fighters.FighterTypeCodex {
+		t.Errorf("expected the implementer to plan by default, got %s", cfg.PlannerType())
+	}
+
+	cfg.Planner = fighters.FighterTypeGemini
+	if cfg.PlannerType() != fighters.FighterTypeGemini {
+		t.Errorf("expected planner gemini, got %s", cfg.PlannerType())
+	}
+}
+
+func TestBindFlags_FailOn(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if cfg.FailOn != types.SeverityLow {
+		t.Errorf("expected every issue to fail a round by default, got fail-on %s", cfg.FailOn)
+	}
+
+	cfg = New()
+	cmd = &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--fail-on", "High"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if cfg.FailOn != types.SeverityHigh {
+		t.Errorf("expected fail-on high, got %s", cfg.FailOn)
+	}
+}
+
+func TestBindFlags_InvalidFailOn(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--fail-on", "blocker"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for an unknown severity")
+	}
+}
+
+func TestBindFlags_InvalidPlanner(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--plan", "--planner", "gpt"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for an unknown planner")
+	}
+}
+
+func TestValidate_SingleContender(t *testing.T) {
+	cfg := New()
+	cfg.Contenders = []fighters.FighterType{fighters.FighterTypeClaude}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a tournament with one contender")
+	}
+}
+
+func TestValidate_InvalidOnStall(t *testing.T) {
+	cfg := New()
+	cfg.OnStall = "panic"
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown on-stall action")
+	}
+}
+
+func TestValidate_Events(t *testing.T) {
+	cfg := New()
+	cfg.Events = EventsJSON
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !cfg.EventsToStdout() {
+		t.Error("expected events on stdout without --events-file")
+	}
+
+	cfg.EventsFile = "events.ndjson"
+	if cfg.EventsToStdout() {
+		t.Error("expected events in the file set with --events-file")
+	}
+
+	cfg = New()
+	cfg.Events = "xml"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown events format")
+	}
+
+	cfg = New()
+	cfg.EventsFile = "events.ndjson"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for events-file without events")
+	}
+}
+
+func TestParseMaxIterationsPolicy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    MaxIterationsPolicy
+		wantErr bool
+	}{
+		{"abort", MaxIterationsPolicy{Action: MaxIterationsAbort}, false},
+		{"ask", MaxIterationsPolicy{Action: MaxIterationsAsk}, false},
+		{"Continue:3", MaxIterationsPolicy{Action: MaxIterationsContinue, Rounds: 3}, false},
+		{"continue", MaxIterationsPolicy{}, true},
+		{"continue:0", MaxIterationsPolicy{}, true},
+		{"retry", MaxIterationsPolicy{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMaxIterationsPolicy(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseMaxIterationsPolicy(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseMaxIterationsPolicy(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestBindFlags_Confirmations(t *testing.T) {
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--on-max-iterations", "continue:2", "--on-no-changes", "fail", "-y"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if cfg.OnMaxIterations != "continue:2" || !cfg.Yes {
+		t.Errorf("expected on-max-iterations continue:2 and yes, got %q and %v", cfg.OnMaxIterations, cfg.Yes)
+	}
+	if cfg.OnNoChanges != NoChangesFail {
+		t.Errorf("expected on-no-changes fail, got %q", cfg.OnNoChanges)
+	}
+	if err := cfg.ValidateConfirmations(); err != nil {
+		t.Errorf("ValidateConfirmations() error = %v", err)
+	}
+}
+
+func TestValidate_InvalidConfirmations(t *testing.T) {
+	cfg := New()
+	cfg.OnMaxIterations = "forever"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown on-max-iterations action")
+	}
+
+	cfg = New()
+	cfg.OnNoChanges = "ignore"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown on-no-changes action")
+	}
+
+	cfg = New()
+	cfg.Yes = true
+	cfg.ApprovalURL = "https://ci.example.com/approve"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for --yes with --approval-url")
+	}
+}
+
+func TestValidate_InvalidReviewPolicy(t *testing.T) {
+	cfg := New()
+	cfg.ReviewPolicy = "majority"
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown review policy")
+	}
+}
+
+func TestValidate_InvalidPromptLanguage(t *testing.T) {
+	cfg := New()
+	cfg.Prompt = "test prompt"
+	cfg.PromptLanguage = "klingon"
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid prompt-language") {
+		t.Errorf("Validate() error = %v, want an invalid prompt-language error", err)
+	}
+}
+
 func TestEnsureOutputDir(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir, err := os.MkdirTemp("", "mortal-prompter-test")
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// ProjectFile is the name of the per-project configuration file, looked up in
+// the working directory and meant to be committed with the project.
+const ProjectFile = ".mortal-prompter.json"
+
+// Project is the content of the project file.
+type Project struct {
+	// Hooks are the shell commands run at each point of the session, by point name
+	Hooks map[string][]string `json:"hooks"`
+
+	// Paths are the glob patterns of the paths staged, reviewed and committed
+	Paths types.PathRules `json:"paths"`
+
+	// Guardrails are the protected paths and change-size limits of the implementer's changes
+	Guardrails guard.Rules `json:"guardrails"`
+
+	// Prompts are the template files replacing the built-in prompts
+	Prompts PromptFiles `json:"prompts"`
+
+	// Fighters are the command fighters of the project, by fighter type
+	Fighters map[string]fighters.CommandSpec `json:"fighters"`
+}
+
+// PromptFiles are the files of the prompt templates a project overrides, by
+// template name, relative to the working directory.
+type PromptFiles struct {
+	// Templates are the templates of all fighters
+	Templates map[prompts.Name]string `json:"templates"`
+
+	// Fighters are the templates of single fighters, by fighter type; they
+	// take precedence over Templates
+	Fighters map[fighters.FighterType]map[prompts.Name]string `json:"fighters"`
+}
+
+// LoadProject reads the project file in the working directory, if there is
+// one, and applies it to the configuration.
+func (c *Config) LoadProject() error {
+	path := filepath.Join(c.WorkDir, ProjectFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", ProjectFile, err)
+	}
+
+	project, err := parseProject(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", ProjectFile, err)
+	}
+	return c.applyProject(project)
+}
+
+// parseProject decodes a project file, rejecting unknown fields so that typos
+// do not go unnoticed.
+func parseProject(data []byte) (*Project, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var project Project
+	if err := dec.Decode(&project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// applyProject sets the options of project on the configuration.
+func (c *Config) applyProject(project *Project) error {
+	// Command fighters first: the rest of the file may refer to them
+	for name, spec := range project.Fighters {
+		if err := fighters.RegisterCommand(fighters.FighterType(name), spec); err != nil {
+			return err
+		}
+	}
+
+	if len(project.Hooks) > 0 {
+		c.Hooks = make(map[hooks.Point][]string, len(project.Hooks))
+		for name, commands := range project.Hooks {
+			point, err := hooks.ParsePoint(name)
+			if err != nil {
+				return err
+			}
+			c.Hooks[point] = commands
+		}
+	}
+
+	for _, pattern := range append(project.Paths.Include, project.Paths.Exclude...) {
+		if strings.TrimSpace(pattern) == "" {
+			return errors.New("empty path pattern")
+		}
+		if strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "..") {
+			return fmt.Errorf("path pattern %q is not relative to the working directory", pattern)
+		}
+	}
+	c.Paths = project.Paths
+
+	if err := project.Guardrails.Validate(); err != nil {
+		return err
+	}
+	c.Guardrails = project.Guardrails
+
+	overrides, err := c.readPromptFiles(project.Prompts.Templates)
+	if err != nil {
+		return err
+	}
+	c.Prompts = overrides
+	for name, files := range project.Prompts.Fighters {
+		ft, err := parseFighterType(string(name))
+		if err != nil {
+			return fmt.Errorf("invalid prompt templates: %w", err)
+		}
+		overrides, err := c.readPromptFiles(files)
+		if err != nil {
+			return err
+		}
+		if c.FighterPrompts == nil {
+			c.FighterPrompts = make(map[fighters.FighterType]prompts.Overrides)
+		}
+		c.FighterPrompts[ft] = overrides
+	}
+	return nil
+}
+
+// readPromptFiles reads the template files, by template name.
+func (c *Config) readPromptFiles(files map[prompts.Name]string) (prompts.Overrides, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	overrides := make(prompts.Overrides, len(files))
+	for name, file := range files {
+		if !prompts.ValidName(name) {
+			return nil, fmt.Errorf("unknown prompt template %q", name)
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(c.WorkDir, file)
+		}
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s prompt template: %w", name, err)
+		}
+		overrides[name] = string(text)
+	}
+	return overrides, nil
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/spf13/cobra"
+)
+
+func writeProject(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ProjectFile), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write project file: %v", err)
+	}
+}
+
+func TestLoadProject_Missing(t *testing.T) {
+	cfg := New()
+	cfg.WorkDir = t.TempDir()
+
+	if err := cfg.LoadProject(); err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+	if cfg.Hooks != nil {
+		t.Errorf("Hooks = %v, want none without a project file", cfg.Hooks)
+	}
+}
+
+func TestLoadProject_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, `{"hooks": {"after_diff": ["./scripts/lint.sh", "go vet ./..."], "on_abort": ["notify-send aborted"]}}`)
+
+	cfg := New()
+	cfg.Prompt = "test prompt"
+	cfg.WorkDir = dir
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if got := cfg.Hooks[hooks.AfterDiff]; len(got) != 2 || got[0] != "./scripts/lint.sh" {
+		t.Errorf("after_diff hooks = %q", got)
+	}
+	if got := cfg.Hooks[hooks.OnAbort]; len(got) != 1 {
+		t.Errorf("on_abort hooks = %q", got)
+	}
+}
+
+func TestLoadProject_Paths(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, `{"paths": {"include": ["src/**"], "exclude": ["**/package-lock.json", "vendor"]}}`)
+
+	cfg := New()
+	cfg.WorkDir = dir
+	if err := cfg.LoadProject(); err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+
+	if got := cfg.Paths.Include; len(got) != 1 || got[0] != "src/**" {
+		t.Errorf("include = %q", got)
+	}
+	if got := cfg.Paths.Exclude; len(got) != 2 || got[1] != "vendor" {
+		t.Errorf("exclude = %q", got)
+	}
+}
+
+func TestLoadProject_Guardrails(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, `{"guardrails": {"protected": ["migrations", ".github/**"], "max_files_changed": 20, "max_lines_deleted": 500, "on_violation": "ask"}}`)
+
+	cfg := New()
+	cfg.WorkDir = dir
+	if err := cfg.LoadProject(); err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+
+	if got := cfg.Guardrails.Protected; len(got) != 2 || got[0] != "migrations" {
+		t.Errorf("protected = %q", got)
+	}
+	if cfg.Guardrails.MaxFilesChanged != 20 || cfg.Guardrails.MaxLinesDeleted != 500 || cfg.Guardrails.MaxDiffBytes != 0 {
+		t.Errorf("limits = %+v", cfg.Guardrails)
+	}
+	if got := cfg.Guardrails.Action(); got != guard.ActionAsk {
+		t.Errorf("action = %q, want %q", got, guard.ActionAsk)
+	}
+}
+
+func TestLoadProject_Prompts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "prompts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range map[string]string{
+		"rules.tmpl":     "Only report security issues.\n",
+		"implement.tmpl": "Round {{.Round}}: {{.Prompt}}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "prompts", name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeProject(t, dir, `{"prompts": {"templates": {"review_rules": "prompts/rules.tmpl"}, "fighters": {"codex": {"implement": "prompts/implement.tmpl"}}}}`)
+
+	cfg := New()
+	cfg.Prompt = "test prompt"
+	cfg.WorkDir = dir
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if got := cfg.Prompts[prompts.ReviewRules]; got != "Only report security issues.\n" {
+		t.Errorf("review_rules template = %q", got)
+	}
+	codex, err := cfg.PromptTemplates(fighters.FighterTypeCodex)
+	if err != nil {
+		t.Fatalf("PromptTemplates() error = %v", err)
+	}
+	if got := codex.Render(prompts.Implement, prompts.Data{Prompt: "add caching", Round: 2}); got != "Round 2: add caching" {
+		t.Errorf("codex implement prompt = %q", got)
+	}
+	claude, err := cfg.PromptTemplates(fighters.FighterTypeClaude)
+	if err != nil {
+		t.Fatalf("PromptTemplates() error = %v", err)
+	}
+	if got := claude.Render(prompts.Implement, prompts.Data{Prompt: "add caching"}); got != "add caching" {
+		t.Errorf("claude implement prompt = %q, want the built-in one", got)
+	}
+	if got := claude.Render(prompts.Review, prompts.Data{Diff: "+x"}); !strings.Contains(got, "Only report security issues.") {
+		t.Errorf("claude review prompt = %q, want the project's rules", got)
+	}
+}
+
+func TestLoadProject_InvalidPrompts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte("{{.Diff"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeProject(t, dir, `{"prompts": {"templates": {"review": "broken.tmpl"}}}`)
+
+	cfg := New()
+	cfg.Prompt = "test prompt"
+	cfg.WorkDir = dir
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid review prompt template") {
+		t.Errorf("Validate() error = %v, want an invalid template error", err)
+	}
+}
+
+func TestLoadProject_Fighters(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, `{
+		"fighters": {"aider-test": {"name": "AIDER", "command": "aider", "args": ["--yes", "--message", "{{.Prompt}}"], "timeout": "10m"}},
+		"prompts": {"fighters": {"aider-test": {}}}
+	}`)
+
+	cfg := New()
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	cfg.BindFlags(cmd)
+	cmd.SetArgs([]string{"--dir", dir, "--implementer", "aider-test", "--reviewer", "codex,AIDER-TEST"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if cfg.Implementer != "aider-test" || len(cfg.Reviewers) != 2 || cfg.Reviewers[1] != "aider-test" {
+		t.Errorf("Implementer = %q, Reviewers = %v, want the command fighter", cfg.Implementer, cfg.Reviewers)
+	}
+	if fighters.DisplayName("aider-test") != "AIDER" {
+		t.Errorf("DisplayName() = %q, want AIDER", fighters.DisplayName("aider-test"))
+	}
+}
+
+func TestLoadProject_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown hook", `{"hooks": {"after_lunch": ["true"]}}`, "unknown hook: after_lunch"},
+		{"unknown field", `{"hook": {"after_diff": ["true"]}}`, "unknown field"},
+		{"malformed", `{"hooks": `, "invalid " + ProjectFile},
+		{"empty pattern", `{"paths": {"exclude": [""]}}`, "empty path pattern"},
+		{"absolute pattern", `{"paths": {"include": ["/src"]}}`, "not relative"},
+		{"absolute protected path", `{"guardrails": {"protected": ["/etc"]}}`, "not relative"},
+		{"negative limit", `{"guardrails": {"max_files_changed": -1}}`, "cannot be negative"},
+		{"unknown action", `{"guardrails": {"on_violation": "ignore"}}`, "invalid guardrail action"},
+		{"unknown template", `{"prompts": {"templates": {"summary": "summary.tmpl"}}}`, `unknown prompt template "summary"`},
+		{"unknown prompt fighter", `{"prompts": {"fighters": {"copilot": {"review": "review.tmpl"}}}}`, "invalid prompt templates"},
+		{"missing template file", `{"prompts": {"templates": {"review": "missing.tmpl"}}}`, "failed to read the review prompt template"},
+		{"fighter without command", `{"fighters": {"aider": {"args": ["{{.Prompt}}"]}}}`, "invalid fighter aider: command is required"},
+		{"built-in fighter", `{"fighters": {"codex": {"command": "codex", "input": "stdin"}}}`, "fighter type codex is built in"},
+		{"unknown fighter field", `{"fighters": {"aider": {"command": "aider", "argv": ["{{.Prompt}}"]}}}`, "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeProject(t, dir, tt.content)
+
+			cfg := New()
+			cfg.WorkDir = dir
+			err := cfg.LoadProject()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadProject() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package confirm decides the yes/no questions asked during a session, such
+// as whether to keep going after the maximum number of iterations.
+//
+// The answer may come from the user at the terminal or in the TUI, from a
+// fixed policy, or from an external approval endpoint. When nobody can be
+// asked, every question gets its default answer instead of blocking.
+package confirm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Question is a yes/no question asked during a session.
+type Question struct {
+	// Message is the question as shown to the user
+	Message string `json:"message"`
+
+	// Default is the answer given when nobody can be asked
+	Default bool `json:"default"`
+
+	// SessionID identifies the session asking, for external approvers
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// Confirmer answers the yes/no questions of a session. An error means no
+// answer could be had, in which case the question's default applies.
+type Confirmer interface {
+	Confirm(q Question) (bool, error)
+}
+
+// Func adapts a function to the Confirmer interface.
+type Func func(q Question) (bool, error)
+
+// Confirm calls f.
+func (f Func) Confirm(q Question) (bool, error) {
+	return f(q)
+}
+
+// Fixed answers every question the same way, as --yes does.
+type Fixed bool
+
+// Confirm returns the fixed answer.
+func (f Fixed) Confirm(q Question) (bool, error) {
+	return bool(f), nil
+}
+
+// Defaults answers every question with its default, for runs without a
+// terminal to ask on.
+type Defaults struct{}
+
+// Confirm returns the default answer of q.
+func (Defaults) Confirm(q Question) (bool, error) {
+	return q.Default, nil
+}
+
+// Terminal asks the questions on a terminal and reads the answers from it.
+type Terminal struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewTerminal creates a Terminal reading the answers from in and writing the
+// questions to out.
+func NewTerminal(in io.Reader, out io.Writer) *Terminal {
+	return &Terminal{in: bufio.NewReader(in), out: out}
+}
+
+// Confirm asks q and reads a yes/no answer; an empty answer is the default.
+func (t *Terminal) Confirm(q Question) (bool, error) {
+	hint := "[y/N]"
+	if q.Default {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(t.out, "\n%s %s: ", q.Message, hint)
+
+	response, err := t.in.ReadString('\n')
+	if err != nil && response == "" {
+		return q.Default, fmt.Errorf("failed to read the answer: %w", err)
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "" {
+		return q.Default, nil
+	}
+	return response == "y" || response == "yes", nil
+}
+
+// Stdin returns a Terminal on the standard input and output, or Defaults when
+// the standard input is not a terminal, as in CI jobs, where reading it would
+// block forever or consume input meant for something else.
+func Stdin() Confirmer {
+	if !IsTerminal(os.Stdin) {
+		return Defaults{}
+	}
+	return NewTerminal(os.Stdin, os.Stdout)
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// DefaultHTTPTimeout is the default time an approval endpoint has to answer.
+// It is generous since a person may be deciding on the other end.
+const DefaultHTTPTimeout = 10 * time.Minute
+
+// HTTP asks an external approval endpoint. Every question is POSTed to the
+// endpoint as JSON, and the endpoint answers with {"approved": true|false}.
+type HTTP struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewHTTP creates an HTTP confirmer for the endpoint at url.
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	return &HTTP{url: url, client: http.DefaultClient, timeout: timeout}
+}
+
+// approval is the body of an approval endpoint's answer.
+type approval struct {
+	Approved *bool `json:"approved"`
+}
+
+// Confirm POSTs q to the endpoint and returns its decision.
+func (h *HTTP) Confirm(q Question) (bool, error) {
+	body, err := json.Marshal(q)
+	if err != nil {
+		return q.Default, fmt.Errorf("failed to encode the question: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return q.Default, fmt.Errorf("invalid approval endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return q.Default, fmt.Errorf("approval endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return q.Default, fmt.Errorf("approval endpoint returned %s", resp.Status)
+	}
+
+	var a approval
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&a); err != nil {
+		return q.Default, fmt.Errorf("invalid answer from the approval endpoint: %w", err)
+	}
+	if a.Approved == nil {
+		return q.Default, errors.New(`invalid answer from the approval endpoint: missing "approved"`)
+	}
+	return *a.Approved, nil
+}
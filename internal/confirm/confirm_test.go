@@ -0,0 +1,119 @@
+package confirm
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTerminal(t *testing.T) {
+	var out bytes.Buffer
+	term := NewTerminal(strings.NewReader("y\n\nNO\n"), &out)
+
+	tests := []struct {
+		question Question
+		want     bool
+	}{
+		{Question{Message: "Merge?"}, true},
+		{Question{Message: "Proceed?", Default: true}, true},
+		{Question{Message: "Continue?", Default: true}, false},
+	}
+	for _, tt := range tests {
+		got, err := term.Confirm(tt.question)
+		if err != nil {
+			t.Fatalf("Confirm(%q) error = %v", tt.question.Message, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.question.Message, got, tt.want)
+		}
+	}
+
+	if !strings.Contains(out.String(), "Merge? [y/N]: ") || !strings.Contains(out.String(), "Proceed? [Y/n]: ") {
+		t.Errorf("questions not written with their hints: %q", out.String())
+	}
+
+	// Once the input is exhausted the default applies, without blocking
+	got, err := term.Confirm(Question{Message: "Again?", Default: true})
+	if err == nil || !got {
+		t.Errorf("Confirm() at EOF = %v, %v, want the default and an error", got, err)
+	}
+}
+
+func TestFixedAndDefaults(t *testing.T) {
+	q := Question{Message: "Continue?", Default: false}
+	if got, _ := Fixed(true).Confirm(q); !got {
+		t.Error("Fixed(true) declined")
+	}
+	if got, _ := (Defaults{}).Confirm(q); got {
+		t.Error("Defaults accepted a question defaulting to no")
+	}
+	if got, _ := (Defaults{}).Confirm(Question{Default: true}); !got {
+		t.Error("Defaults declined a question defaulting to yes")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("IsTerminal() = true for a regular file")
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var got Question
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("body is not a question: %v", err)
+		}
+		w.Write([]byte(`{"approved": true}`))
+	}))
+	defer server.Close()
+
+	approved, err := NewHTTP(server.URL, time.Second).Confirm(Question{Message: "Merge?", SessionID: "abc"})
+	if err != nil || !approved {
+		t.Fatalf("Confirm() = %v, %v, want approved", approved, err)
+	}
+	if got.Message != "Merge?" || got.SessionID != "abc" {
+		t.Errorf("endpoint got %+v", got)
+	}
+}
+
+func TestHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusInternalServerError, `{"approved": true}`},
+		{"missing decision", http.StatusOK, `{}`},
+		{"not json", http.StatusOK, `yes`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			approved, err := NewHTTP(server.URL, time.Second).Confirm(Question{Message: "Continue?", Default: false})
+			if err == nil || approved {
+				t.Errorf("Confirm() = %v, %v, want the default and an error", approved, err)
+			}
+		})
+	}
+}
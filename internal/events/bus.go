@@ -0,0 +1,155 @@
+package events
+
+import "sync"
+
+// Subscriber receives the events published on a Bus.
+type Subscriber interface {
+	HandleEvent(e Event)
+}
+
+// SubscriberFunc adapts a function to a Subscriber.
+type SubscriberFunc func(e Event)
+
+// HandleEvent calls f(e).
+func (f SubscriberFunc) HandleEvent(e Event) {
+	f(e)
+}
+
+// Bus delivers every published event to each of its subscribers, in the
+// order the events were published.
+//
+// A subscriber added with Subscribe gets its events from a queue of its own,
+// so that a slow one, such as a file or network sink, never holds up the
+// battle. Subscribers added with SubscribeSync get them before Publish
+// returns; they must be quick, and are meant for the TUI and the terminal
+// logger, which have to stay in step with the questions asked on screen.
+//
+// A nil *Bus drops every event.
+type Bus struct {
+	mu     sync.Mutex
+	sync   []Subscriber
+	queues []*queue
+	closed bool
+}
+
+// NewBus creates a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a subscriber that gets the events in its own goroutine.
+func (b *Bus) Subscribe(s Subscriber) {
+	q := newQueue()
+	go q.run(s)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		q.close()
+		return
+	}
+	b.queues = append(b.queues, q)
+}
+
+// SubscribeSync adds a subscriber that gets the events in the publisher's goroutine.
+func (b *Bus) SubscribeSync(s Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sync = append(b.sync, s)
+}
+
+// Publish delivers e to every subscriber. It is safe for concurrent use.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	for _, q := range b.queues {
+		q.push(e)
+	}
+	subscribers := b.sync
+	b.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.HandleEvent(e)
+	}
+}
+
+// Close waits until every subscriber has handled the events published so far.
+// Events published after Close are dropped.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	queues := b.queues
+	b.mu.Unlock()
+
+	for _, q := range queues {
+		q.close()
+		<-q.done
+	}
+}
+
+// queue holds the events not yet handled by an asynchronous subscriber. It is
+// unbounded so that publishing never blocks.
+type queue struct {
+	mu      sync.Mutex
+	ready   *sync.Cond
+	pending []Event
+	closed  bool
+	done    chan struct{}
+}
+
+func newQueue() *queue {
+	q := &queue{done: make(chan struct{})}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *queue) push(e Event) {
+	q.mu.Lock()
+	q.pending = append(q.pending, e)
+	q.mu.Unlock()
+	q.ready.Signal()
+}
+
+func (q *queue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.ready.Signal()
+}
+
+// run hands the queued events to s until the queue is closed and empty.
+func (q *queue) run(s Subscriber) {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.ready.Wait()
+		}
+		batch := q.pending
+		q.pending = nil
+		closed := q.closed
+		q.mu.Unlock()
+
+		for _, e := range batch {
+			s.HandleEvent(e)
+		}
+		if closed && len(batch) == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a Subscriber recording the types of the events it handles.
+type recorder struct {
+	mu    sync.Mutex
+	types []string
+}
+
+func (r *recorder) HandleEvent(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = append(r.types, e.Type())
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.types...)
+}
+
+func TestBus_DeliversToEverySubscriber(t *testing.T) {
+	bus := NewBus()
+	syncSub, asyncSub := &recorder{}, &recorder{}
+	bus.SubscribeSync(syncSub)
+	bus.Subscribe(asyncSub)
+	bus.Subscribe(&recorder{})
+
+	published := []Event{RoundStart{Round: 1}, ChangesDetected{Files: 2}, NoIssues{}}
+	for _, e := range published {
+		bus.Publish(e)
+	}
+
+	// Synchronous subscribers have the events once Publish returns
+	if got := syncSub.got(); len(got) != len(published) {
+		t.Fatalf("sync subscriber got %v before Close, want all %d events", got, len(published))
+	}
+
+	bus.Close()
+	want := []string{TypeRoundStart, TypeChangesDetected, TypeNoIssues}
+	for name, sub := range map[string]*recorder{"sync": syncSub, "async": asyncSub} {
+		got := sub.got()
+		if len(got) != len(want) {
+			t.Fatalf("%s subscriber got %v, want %v", name, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s subscriber got %v, want %v in order", name, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+	release := make(chan struct{})
+	slow := &recorder{}
+	bus.Subscribe(SubscriberFunc(func(e Event) {
+		<-release
+		slow.HandleEvent(e)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 100; i++ {
+			bus.Publish(RoundStart{Round: i})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a slow subscriber")
+	}
+
+	close(release)
+	bus.Close()
+	if got := slow.got(); len(got) != 100 {
+		t.Errorf("slow subscriber got %d events after Close, want 100", len(got))
+	}
+}
+
+func TestBus_Closed(t *testing.T) {
+	bus := NewBus()
+	sub := &recorder{}
+	bus.Subscribe(sub)
+	bus.Close()
+	bus.Close()
+
+	bus.Publish(NoIssues{})
+	bus.Subscribe(&recorder{})
+	if got := sub.got(); len(got) != 0 {
+		t.Errorf("got %v after Close, want events dropped", got)
+	}
+
+	// A nil bus drops everything
+	var none *Bus
+	none.Publish(NoIssues{})
+	none.Close()
+}
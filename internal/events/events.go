@@ -0,0 +1,223 @@
+// Package events defines the events of a mortal-prompter session and the bus
+// that delivers them to any number of subscribers, such as the TUI, the
+// logger and the machine-readable event stream.
+package events
+
+import (
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Event is something that happened during a session.
+type Event interface {
+	// Type is the stable name of the event, as written in the event stream
+	Type() string
+}
+
+// Event types, as returned by Event.Type
+const (
+	TypeSessionStart         = "session_start"
+	TypeRoundStart           = "round_start"
+	TypeFighterEnter         = "fighter_enter"
+	TypeFighterAction        = "fighter_action"
+	TypeFighterInput         = "fighter_input"
+	TypeFighterOutput        = "fighter_output"
+	TypeFighterFinish        = "fighter_finish"
+	TypeContenderProgress    = "contender_progress"
+	TypeDiffCaptured         = "diff_captured"
+	TypeChangesDetected      = "changes_detected"
+	TypeVerification         = "verification"
+	TypeHooks                = "hooks"
+	TypeGuardrails           = "guardrails"
+	TypeReviewChunk          = "review_chunk"
+	TypeReviewVerdicts       = "review_verdicts"
+	TypeUsage                = "usage"
+	TypeIssuesFound          = "issues_found"
+	TypeNoIssues             = "no_issues"
+	TypeRoundComplete        = "round_complete"
+	TypeConfirmationRequired = "confirmation_required"
+	TypePlanProposed         = "plan_proposed"
+	TypeSessionComplete      = "session_complete"
+	TypeInfo                 = "info"
+	TypeWarning              = "warning"
+	TypeError                = "error"
+)
+
+// SessionStart is published when a session, a tournament or a review starts.
+type SessionStart struct {
+	SessionID   string
+	Implementer string
+	Reviewer    string
+}
+
+// RoundStart is published at the start of every round.
+type RoundStart struct {
+	Round int
+}
+
+// FighterEnter is published when a fighter starts working.
+type FighterEnter struct {
+	Fighter string
+}
+
+// FighterAction describes what a fighter, or the orchestrator on its behalf, is doing.
+type FighterAction struct {
+	Fighter string
+	Action  string
+}
+
+// FighterInput carries the prompt sent to a fighter.
+type FighterInput struct {
+	Fighter string
+	Input   string
+}
+
+// FighterOutput carries the raw output of a fighter or a verification command.
+type FighterOutput struct {
+	Fighter string
+	Output  string
+}
+
+// FighterFinish is published when a fighter completes its task.
+type FighterFinish struct {
+	Fighter  string
+	Duration time.Duration
+}
+
+// ContenderProgress reports the progress of a tournament contender, whose own
+// events stay on the contender's bus.
+type ContenderProgress struct {
+	// Fighter is the implementer name the tournament reports its contenders under
+	Fighter string
+
+	// Contender labels the contender, e.g. "#2 CODEX"
+	Contender string
+
+	Message string
+}
+
+// DiffCaptured carries the staged diff of the implementer's changes.
+type DiffCaptured struct {
+	Diff string
+}
+
+// ChangesDetected is published when the implementer changed files.
+type ChangesDetected struct {
+	Files int
+}
+
+// Verification carries the results of the verification gate.
+type Verification struct {
+	Results []types.VerificationResult
+}
+
+// Hooks carries the results of the lifecycle hooks run at a point of the session.
+type Hooks struct {
+	Point   string
+	Results []types.HookResult
+}
+
+// Guardrails carries the guardrails the implementer's changes broke, and
+// whether the offending changes were reverted.
+type Guardrails struct {
+	Violations []types.Violation
+}
+
+// ReviewChunk carries the panel's verdict on one part of a diff reviewed in parts.
+type ReviewChunk struct {
+	Chunk types.ChunkReview
+}
+
+// ReviewVerdicts carries the review of every reviewer on the panel, and their
+// combined verdicts on the issues of earlier rounds.
+type ReviewVerdicts struct {
+	Reviews  []types.ReviewResult
+	Verdicts []types.IssueVerdict
+}
+
+// UsageUpdated carries the total usage reported by the fighters so far.
+type UsageUpdated struct {
+	Total types.Usage
+}
+
+// IssuesFound is published when a round ends with issues.
+type IssuesFound struct {
+	// Source is the reviewer, or the verification gate, that raised the issues
+	Source string
+
+	Issues []types.Issue
+}
+
+// NoIssues is published when a round ends without issues.
+type NoIssues struct{}
+
+// RoundComplete is published when a round is recorded, with how it ended.
+type RoundComplete struct {
+	Round   int
+	Outcome types.RoundOutcome
+
+	// Error is the error that ended the round early, if any
+	Error string
+}
+
+// ConfirmationRequired is published once a yes/no question has been answered.
+type ConfirmationRequired struct {
+	Message   string
+	Confirmed bool
+}
+
+// PlanProposed is published once the plan drafted in the planning phase has
+// been approved or rejected. Text is the approved text, possibly edited.
+type PlanProposed struct {
+	Plan     types.Plan
+	Text     string
+	Approved bool
+}
+
+// SessionComplete is published when a session ends without an error.
+type SessionComplete struct {
+	Result  *types.SessionResult
+	Success bool
+}
+
+// Info is a progress message.
+type Info struct {
+	Message string
+}
+
+// Warning is an error that does not end the session.
+type Warning struct {
+	Err error
+}
+
+// Error is an error that ends the session.
+type Error struct {
+	Err error
+}
+
+func (SessionStart) Type() string         { return TypeSessionStart }
+func (RoundStart) Type() string           { return TypeRoundStart }
+func (FighterEnter) Type() string         { return TypeFighterEnter }
+func (FighterAction) Type() string        { return TypeFighterAction }
+func (FighterInput) Type() string         { return TypeFighterInput }
+func (FighterOutput) Type() string        { return TypeFighterOutput }
+func (FighterFinish) Type() string        { return TypeFighterFinish }
+func (ContenderProgress) Type() string    { return TypeContenderProgress }
+func (DiffCaptured) Type() string         { return TypeDiffCaptured }
+func (ChangesDetected) Type() string      { return TypeChangesDetected }
+func (Verification) Type() string         { return TypeVerification }
+func (Hooks) Type() string                { return TypeHooks }
+func (Guardrails) Type() string           { return TypeGuardrails }
+func (ReviewChunk) Type() string          { return TypeReviewChunk }
+func (ReviewVerdicts) Type() string       { return TypeReviewVerdicts }
+func (UsageUpdated) Type() string         { return TypeUsage }
+func (IssuesFound) Type() string          { return TypeIssuesFound }
+func (NoIssues) Type() string             { return TypeNoIssues }
+func (RoundComplete) Type() string        { return TypeRoundComplete }
+func (ConfirmationRequired) Type() string { return TypeConfirmationRequired }
+func (PlanProposed) Type() string         { return TypePlanProposed }
+func (SessionComplete) Type() string      { return TypeSessionComplete }
+func (Info) Type() string                 { return TypeInfo }
+func (Warning) Type() string              { return TypeWarning }
+func (Error) Type() string                { return TypeError }
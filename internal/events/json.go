@@ -0,0 +1,405 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// SchemaVersion is the version of the event stream format. It only changes
+// when a field is removed or changes meaning; new event types and fields can
+// be added within a version, so consumers should ignore what they don't know.
+const SchemaVersion = 1
+
+// Line is a single line of the event stream.
+type Line struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	SessionID     string    `json:"session_id,omitempty"`
+
+	// Round is the number of the round the event happened in, 0 before the first round
+	Round int `json:"round,omitempty"`
+
+	// Data holds the fields of the event type, if it has any
+	Data any `json:"data,omitempty"`
+}
+
+// SessionStartData is the data of a session_start line.
+type SessionStartData struct {
+	Implementer string `json:"implementer"`
+	Reviewer    string `json:"reviewer"`
+}
+
+// FighterData is the data of the fighter_enter, fighter_action,
+// fighter_input, fighter_output and fighter_finish lines.
+type FighterData struct {
+	Fighter    string `json:"fighter"`
+	Action     string `json:"action,omitempty"`
+	Input      string `json:"input,omitempty"`
+	Output     string `json:"output,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+}
+
+// ContenderProgressData is the data of a contender_progress line.
+type ContenderProgressData struct {
+	Contender string `json:"contender"`
+	Message   string `json:"message"`
+}
+
+// DiffData is the data of a diff_captured line.
+type DiffData struct {
+	Diff string `json:"diff"`
+}
+
+// ChangesDetectedData is the data of a changes_detected line.
+type ChangesDetectedData struct {
+	Files int `json:"files"`
+}
+
+// VerificationData is the data of a verification line.
+type VerificationData struct {
+	Commands []VerificationCommand `json:"commands"`
+}
+
+// VerificationCommand is the result of a single verification command.
+type VerificationCommand struct {
+	Command    string `json:"command"`
+	Passed     bool   `json:"passed"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	Output     string `json:"output,omitempty"`
+}
+
+// HooksData is the data of a hooks line.
+type HooksData struct {
+	Hook    string     `json:"hook"`
+	Results []HookData `json:"results"`
+}
+
+// HookData is the result of a single lifecycle hook.
+type HookData struct {
+	Command    string   `json:"command"`
+	Vetoed     bool     `json:"vetoed"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMS int64    `json:"duration_ms"`
+	Issues     []string `json:"issues,omitempty"`
+	Output     string   `json:"output,omitempty"`
+}
+
+// GuardrailsData is the data of a guardrails line.
+type GuardrailsData struct {
+	Violations []ViolationData `json:"violations"`
+}
+
+// ViolationData is a single broken guardrail.
+type ViolationData struct {
+	Rule     string `json:"rule"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+	Reverted bool   `json:"reverted"`
+}
+
+// ReviewChunkData is the data of a review_chunk line.
+type ReviewChunkData struct {
+	// Index is the 1-based number of the part, 0 for the cross-file pass
+	Index      int      `json:"index"`
+	Total      int      `json:"total"`
+	Files      []string `json:"files"`
+	Bytes      int      `json:"bytes"`
+	HasIssues  bool     `json:"has_issues"`
+	Issues     int      `json:"issues"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
+// ReviewVerdictsData is the data of a review_verdicts line.
+type ReviewVerdictsData struct {
+	Reviews []ReviewVerdict `json:"reviews"`
+
+	// Issues are the verdicts on the issues of earlier rounds
+	Issues []IssueVerdictData `json:"issues,omitempty"`
+}
+
+// IssueVerdictData is the verdict on an issue of an earlier round.
+type IssueVerdictData struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Note     string `json:"note,omitempty"`
+	Reviewer string `json:"reviewer,omitempty"`
+}
+
+// ReviewVerdict is the verdict of a single reviewer on the panel.
+type ReviewVerdict struct {
+	Reviewer   string `json:"reviewer"`
+	HasIssues  bool   `json:"has_issues"`
+	Issues     int    `json:"issues"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// UsageData is the data of a usage line, and the usage of a session_complete line.
+type UsageData struct {
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// IssuesData is the data of an issues_found line.
+type IssuesData struct {
+	Source string      `json:"source,omitempty"`
+	Issues []IssueData `json:"issues"`
+}
+
+// IssueData is a review issue in structured form.
+type IssueData struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Category    string `json:"category,omitempty"`
+	File        string `json:"file,omitempty"`
+	StartLine   int    `json:"start_line,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	Description string `json:"description"`
+	Reviewer    string `json:"reviewer,omitempty"`
+}
+
+// RoundCompleteData is the data of a round_complete line.
+type RoundCompleteData struct {
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ConfirmationData is the data of a confirmation_required line.
+type ConfirmationData struct {
+	Message   string `json:"message"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// PlanProposedData is the data of a plan_proposed line.
+type PlanProposedData struct {
+	Planner  string   `json:"planner"`
+	Plan     string   `json:"plan"`
+	Critique []string `json:"critique,omitempty"`
+	Approved bool     `json:"approved"`
+	Edited   bool     `json:"edited,omitempty"`
+}
+
+// SessionCompleteData is the data of a session_complete line.
+type SessionCompleteData struct {
+	Success       bool      `json:"success"`
+	State         string    `json:"state"`
+	StopReason    string    `json:"stop_reason,omitempty"`
+	Rounds        int       `json:"rounds"`
+	DurationMS    int64     `json:"duration_ms"`
+	Usage         UsageData `json:"usage"`
+	Branch        string    `json:"branch,omitempty"`
+	FilesModified []string  `json:"files_modified,omitempty"`
+
+	// Issues are the issues found in the last round
+	Issues []IssueData `json:"issues,omitempty"`
+}
+
+// MessageData is the data of the info, warning and error lines.
+type MessageData struct {
+	Message string `json:"message"`
+}
+
+// JSONWriter is a Subscriber that writes every event as a line of JSON.
+// Subscribe it with Bus.Subscribe so that a slow destination never holds up
+// the battle.
+type JSONWriter struct {
+	enc       *json.Encoder
+	sessionID string
+	round     int
+	err       error
+}
+
+// NewJSONWriter creates a subscriber writing events to w.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{enc: json.NewEncoder(w)}
+}
+
+// Err returns the first error writing an event, if any. Events after a failed
+// write are dropped. Call it once the bus is closed.
+func (j *JSONWriter) Err() error {
+	return j.err
+}
+
+// HandleEvent writes e as a single line.
+func (j *JSONWriter) HandleEvent(e Event) {
+	switch e := e.(type) {
+	case SessionStart:
+		j.sessionID = e.SessionID
+	case RoundStart:
+		j.round = e.Round
+	}
+
+	if j.err != nil {
+		return
+	}
+	j.err = j.enc.Encode(Line{
+		SchemaVersion: SchemaVersion,
+		Type:          e.Type(),
+		Time:          time.Now().UTC(),
+		SessionID:     j.sessionID,
+		Round:         j.round,
+		Data:          lineData(e),
+	})
+}
+
+// lineData returns the data of the line written for e, nil if it has none.
+func lineData(e Event) any {
+	switch e := e.(type) {
+	case SessionStart:
+		return SessionStartData{Implementer: e.Implementer, Reviewer: e.Reviewer}
+	case FighterEnter:
+		return FighterData{Fighter: e.Fighter}
+	case FighterAction:
+		return FighterData{Fighter: e.Fighter, Action: e.Action}
+	case FighterInput:
+		return FighterData{Fighter: e.Fighter, Input: e.Input}
+	case FighterOutput:
+		return FighterData{Fighter: e.Fighter, Output: e.Output}
+	case FighterFinish:
+		return FighterData{Fighter: e.Fighter, DurationMS: e.Duration.Milliseconds()}
+	case ContenderProgress:
+		return ContenderProgressData{Contender: e.Contender, Message: e.Message}
+	case DiffCaptured:
+		return DiffData{Diff: e.Diff}
+	case ChangesDetected:
+		return ChangesDetectedData{Files: e.Files}
+	case Verification:
+		data := VerificationData{Commands: make([]VerificationCommand, 0, len(e.Results))}
+		for _, r := range e.Results {
+			data.Commands = append(data.Commands, VerificationCommand{
+				Command:    r.Command,
+				Passed:     r.Passed,
+				ExitCode:   r.ExitCode,
+				DurationMS: r.Duration.Milliseconds(),
+				Output:     r.Output,
+			})
+		}
+		return data
+	case Hooks:
+		data := HooksData{Hook: e.Point, Results: make([]HookData, 0, len(e.Results))}
+		for _, r := range e.Results {
+			data.Results = append(data.Results, HookData{
+				Command:    r.Command,
+				Vetoed:     r.Vetoed,
+				ExitCode:   r.ExitCode,
+				DurationMS: r.Duration.Milliseconds(),
+				Issues:     r.Issues,
+				Output:     r.Output,
+			})
+		}
+		return data
+	case Guardrails:
+		data := GuardrailsData{Violations: make([]ViolationData, 0, len(e.Violations))}
+		for _, v := range e.Violations {
+			data.Violations = append(data.Violations, ViolationData{
+				Rule:     v.Rule,
+				Path:     v.Path,
+				Message:  v.Message,
+				Reverted: v.Reverted,
+			})
+		}
+		return data
+	case ReviewChunk:
+		return ReviewChunkData{
+			Index:      e.Chunk.Index,
+			Total:      e.Chunk.Total,
+			Files:      e.Chunk.Files,
+			Bytes:      e.Chunk.Bytes,
+			HasIssues:  e.Chunk.HasIssues,
+			Issues:     len(e.Chunk.Findings),
+			DurationMS: e.Chunk.Duration.Milliseconds(),
+		}
+	case ReviewVerdicts:
+		data := ReviewVerdictsData{Reviews: make([]ReviewVerdict, 0, len(e.Reviews))}
+		for _, r := range e.Reviews {
+			data.Reviews = append(data.Reviews, ReviewVerdict{
+				Reviewer:   r.Reviewer,
+				HasIssues:  r.HasIssues,
+				Issues:     len(r.Issues),
+				DurationMS: r.Duration.Milliseconds(),
+			})
+		}
+		for _, v := range e.Verdicts {
+			data.Issues = append(data.Issues, IssueVerdictData{
+				ID:       v.ID,
+				Status:   string(v.Status),
+				Note:     v.Note,
+				Reviewer: v.Reviewer,
+			})
+		}
+		return data
+	case UsageUpdated:
+		return toUsage(e.Total)
+	case IssuesFound:
+		return IssuesData{Source: e.Source, Issues: toIssues(e.Issues)}
+	case RoundComplete:
+		return RoundCompleteData{Outcome: string(e.Outcome), Error: e.Error}
+	case ConfirmationRequired:
+		return ConfirmationData{Message: e.Message, Confirmed: e.Confirmed}
+	case PlanProposed:
+		return PlanProposedData{
+			Planner:  e.Plan.Planner,
+			Plan:     e.Text,
+			Critique: e.Plan.Critique,
+			Approved: e.Approved,
+			Edited:   e.Approved && e.Text != e.Plan.Text,
+		}
+	case SessionComplete:
+		return sessionCompleteData(e.Result, e.Success)
+	case Info:
+		return MessageData{Message: e.Message}
+	case Warning:
+		return MessageData{Message: e.Err.Error()}
+	case Error:
+		return MessageData{Message: e.Err.Error()}
+	default:
+		return nil
+	}
+}
+
+// sessionCompleteData returns the data of a session_complete line.
+func sessionCompleteData(result *types.SessionResult, success bool) SessionCompleteData {
+	data := SessionCompleteData{Success: success}
+	if result == nil {
+		return data
+	}
+	data.State = string(result.State)
+	data.StopReason = result.StopReason
+	data.Rounds = result.TotalRounds
+	data.DurationMS = result.TotalDuration.Milliseconds()
+	data.Usage = toUsage(result.Usage)
+	data.Branch = result.Branch
+	data.FilesModified = result.FilesModified
+	if n := len(result.Rounds); n > 0 {
+		data.Issues = toIssues(result.Rounds[n-1].Findings)
+	}
+	return data
+}
+
+// toUsage converts a usage to its event form.
+func toUsage(u types.Usage) UsageData {
+	return UsageData{InputTokens: u.InputTokens, OutputTokens: u.OutputTokens, CostUSD: u.CostUSD}
+}
+
+// toIssues converts issues to their event form.
+func toIssues(issues []types.Issue) []IssueData {
+	result := make([]IssueData, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, IssueData{
+			ID:          issue.ID,
+			Severity:    string(issue.Severity),
+			Category:    issue.Category,
+			File:        issue.File,
+			StartLine:   issue.StartLine,
+			EndLine:     issue.EndLine,
+			Description: issue.Description,
+			Reviewer:    issue.Reviewer,
+		})
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// rawEvent is an event with its data left undecoded.
+type rawEvent struct {
+	SchemaVersion int             `json:"schema_version"`
+	Type          string          `json:"type"`
+	Time          time.Time       `json:"time"`
+	SessionID     string          `json:"session_id"`
+	Round         int             `json:"round"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// readEvents decodes every line of buf.
+func readEvents(t *testing.T, buf *bytes.Buffer) []rawEvent {
+	t.Helper()
+	var events []rawEvent
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var e rawEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf)
+
+	for _, e := range []Event{
+		SessionStart{SessionID: "abc123", Implementer: "CLAUDE CODE", Reviewer: "CODEX"},
+		RoundStart{Round: 1},
+		FighterFinish{Fighter: "CLAUDE CODE", Duration: 1500 * time.Millisecond},
+		ChangesDetected{Files: 3},
+		Guardrails{Violations: []types.Violation{{Rule: "protected_path", Path: "migrations/001.sql", Message: "protected", Reverted: true}}},
+		IssuesFound{Source: "CODEX", Issues: []types.Issue{types.ParseIssue("[high] main.go:12 - Unchecked error")}},
+		SessionComplete{Result: &types.SessionResult{
+			State:       types.StateCompleted,
+			TotalRounds: 1,
+			Usage:       types.Usage{CostUSD: 0.5},
+		}, Success: true},
+		Error{Err: errors.New("boom")},
+	} {
+		w.HandleEvent(e)
+	}
+
+	if w.Err() != nil {
+		t.Fatalf("Err() = %v", w.Err())
+	}
+
+	events := readEvents(t, &buf)
+	wantTypes := []string{TypeSessionStart, TypeRoundStart, TypeFighterFinish, TypeChangesDetected, TypeGuardrails,
+		TypeIssuesFound, TypeSessionComplete, TypeError}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, e := range events {
+		if e.Type != wantTypes[i] {
+			t.Errorf("event %d type = %q, want %q", i, e.Type, wantTypes[i])
+		}
+		if e.SchemaVersion != SchemaVersion || e.SessionID != "abc123" || e.Time.IsZero() {
+			t.Errorf("event %d envelope = %+v, want schema %d and session abc123", i, e, SchemaVersion)
+		}
+	}
+	if events[0].Round != 0 || events[1].Round != 1 || events[5].Round != 1 {
+		t.Errorf("rounds = %d, %d, %d, want events stamped from round 1 on", events[0].Round, events[1].Round, events[5].Round)
+	}
+
+	var finish FighterData
+	if err := json.Unmarshal(events[2].Data, &finish); err != nil || finish.DurationMS != 1500 {
+		t.Errorf("fighter_finish data = %s, want duration_ms 1500", events[2].Data)
+	}
+
+	var guardrails GuardrailsData
+	if err := json.Unmarshal(events[4].Data, &guardrails); err != nil || len(guardrails.Violations) != 1 ||
+		guardrails.Violations[0].Path != "migrations/001.sql" || !guardrails.Violations[0].Reverted {
+		t.Errorf("guardrails data = %s", events[4].Data)
+	}
+
+	var issues IssuesData
+	if err := json.Unmarshal(events[5].Data, &issues); err != nil {
+		t.Fatal(err)
+	}
+	if issues.Source != "CODEX" || len(issues.Issues) != 1 || issues.Issues[0].Severity != "high" ||
+		issues.Issues[0].File != "main.go" || issues.Issues[0].StartLine != 12 || issues.Issues[0].ID == "" {
+		t.Errorf("issues_found data = %s", events[5].Data)
+	}
+
+	var complete SessionCompleteData
+	if err := json.Unmarshal(events[6].Data, &complete); err != nil {
+		t.Fatal(err)
+	}
+	if !complete.Success || complete.State != "completed" || complete.Usage.CostUSD != 0.5 {
+		t.Errorf("session_complete data = %s", events[6].Data)
+	}
+}
+
+func TestJSONWriter_Answers(t *testing.T) {
+	plan := types.Plan{Planner: "CLAUDE CODE", Text: "1. Add the cache"}
+
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf)
+	w.HandleEvent(ConfirmationRequired{Message: "Continue?", Confirmed: true})
+	w.HandleEvent(PlanProposed{Plan: plan, Text: "1. Add the cache\n2. Test it", Approved: true})
+
+	events := readEvents(t, &buf)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	var confirmation ConfirmationData
+	if err := json.Unmarshal(events[0].Data, &confirmation); err != nil || !confirmation.Confirmed || confirmation.Message != "Continue?" {
+		t.Errorf("confirmation_required data = %s", events[0].Data)
+	}
+	var proposed PlanProposedData
+	if err := json.Unmarshal(events[1].Data, &proposed); err != nil || !proposed.Approved || !proposed.Edited {
+		t.Errorf("plan_proposed data = %s", events[1].Data)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestJSONWriter_Err(t *testing.T) {
+	w := NewJSONWriter(failingWriter{})
+	w.HandleEvent(NoIssues{})
+	w.HandleEvent(Info{Message: "dropped"})
+	if w.Err() == nil || w.Err().Error() != "disk full" {
+		t.Errorf("Err() = %v, want the first write error", w.Err())
+	}
+}
@@ -3,11 +3,15 @@ package fighters
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
@@ -17,12 +21,23 @@ const DefaultTimeout = 5 * time.Minute
 // Claude represents the Claude Code fighter (the implementer).
 // It wraps the claude CLI tool for executing development tasks.
 type Claude struct {
-	workDir string
-	timeout time.Duration
+	workDir   string
+	timeout   time.Duration
+	templates *prompts.Templates
+
+	mu    sync.Mutex
+	usage types.Usage
 }
 
-// Ensure Claude implements the Fighter interface.
-var _ Fighter = (*Claude)(nil)
+// Ensure Claude implements the Implementer, Reviewer, UsageReporter,
+// PromptBuilder and Templated interfaces.
+var (
+	_ Implementer   = (*Claude)(nil)
+	_ Reviewer      = (*Claude)(nil)
+	_ UsageReporter = (*Claude)(nil)
+	_ PromptBuilder = (*Claude)(nil)
+	_ Templated     = (*Claude)(nil)
+)
 
 // NewClaude creates a new Claude fighter instance.
 // workDir specifies the working directory for command execution.
@@ -44,8 +59,9 @@ func (c *Claude) Name() string {
 
 // Execute runs Claude Code CLI with the provided prompt and optional image path.
 // It uses the context for timeout/cancellation support.
-// The command executed is: claude -p "<prompt>" --dangerously-skip-permissions
+// The command executed is: claude -p "<prompt>" --dangerously-skip-permissions --output-format json
 // If imagePath is provided, it is included in the prompt for Claude to analyze.
+// The JSON envelope is unwrapped into the result text and the reported usage.
 func (c *Claude) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
 	// Check if claude is installed
 	if _, err := exec.LookPath("claude"); err != nil {
@@ -64,17 +80,24 @@ func (c *Claude) Execute(ctx context.Context, prompt string, imagePath string) (
 	}
 
 	// Build and execute the command
-	cmd := exec.CommandContext(execCtx, "claude", "-p", finalPrompt, "--dangerously-skip-permissions")
+	cmd := proc.CommandContext(execCtx, "claude", "-p", finalPrompt, "--dangerously-skip-permissions", "--output-format", "json")
 	cmd.Dir = c.workDir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.Run(cmd)
+
+	output, usage, ok := parseClaudeJSON(stdout.String())
+	if ok {
+		c.mu.Lock()
+		c.usage = c.usage.Add(usage)
+		c.mu.Unlock()
+	}
 
 	// Combine stdout and stderr for complete output
-	combinedOutput := stdout.String()
+	combinedOutput := output
 	if stderr.Len() > 0 {
 		if combinedOutput != "" {
 			combinedOutput += "\n"
@@ -97,35 +120,28 @@ func (c *Claude) Execute(ctx context.Context, prompt string, imagePath string) (
 }
 
 // BuildPromptWithIssues constructs a prompt for Claude that includes
-// previous issues found during code review.
-// If there are no previous issues, it returns the basePrompt as-is.
-// If there are issues, it builds a structured prompt requesting corrections.
+// previous issues found during code review, from the implement template.
+// With the built-in template, if there are no previous issues it returns the
+// basePrompt as-is, and if there are it asks for corrections.
 func (c *Claude) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
-	if len(previousIssues) == 0 {
-		return basePrompt
-	}
-
-	var sb strings.Builder
-	sb.WriteString("CONTEXTO: Estas en una sesion de code review iterativo.\n\n")
-	sb.WriteString("ISSUES ENCONTRADOS EN LA REVISION ANTERIOR:\n")
-
-	for _, issue := range previousIssues {
-		sb.WriteString("- ")
-		sb.WriteString(issue)
-		sb.WriteString("\n")
-	}
+	return c.BuildPrompt(prompts.Data{Prompt: basePrompt, Issues: previousIssues})
+}
 
-	sb.WriteString("\nTAREA: Corrige los issues mencionados arriba.\n")
-	sb.WriteString("No expliques los cambios, solo implementa las correcciones.\n")
+// BuildPrompt renders the implementer prompt of a round from the implement template.
+func (c *Claude) BuildPrompt(data prompts.Data) string {
+	return c.templates.Render(prompts.Implement, data)
+}
 
-	return sb.String()
+// SetTemplates sets the templates Claude renders its prompts from.
+func (c *Claude) SetTemplates(t *prompts.Templates) {
+	c.templates = t
 }
 
 // Review executes Claude to review a git diff and returns the parsed review result.
 // It sends the diff as a prompt asking for code review.
-func (c *Claude) Review(ctx context.Context, gitDiff string) (*types.ReviewResult, error) {
+func (c *Claude) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
 	// Build the review prompt
-	reviewPrompt := c.buildReviewPrompt(gitDiff)
+	reviewPrompt := c.buildReviewPrompt(req)
 
 	// Execute Claude with the review prompt (no image for reviews)
 	output, err := c.Execute(ctx, reviewPrompt, "")
@@ -134,43 +150,34 @@ func (c *Claude) Review(ctx context.Context, gitDiff string) (*types.ReviewResul
 	}
 
 	// Parse the output and return the review result
-	return c.parseReviewOutput(output), nil
+	result := c.parseReviewOutput(ctx, output)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("claude review was interrupted: %w", err)
+	}
+	return result, nil
 }
 
 // buildReviewPrompt constructs the review prompt for Claude.
-func (c *Claude) buildReviewPrompt(gitDiff string) string {
-	return fmt.Sprintf(`Review the following git diff for issues.
-Find real issues: bugs, vulnerabilities, bad practices, missing error handling.
-If NO issues respond "LGTM: No issues found".
-If issues found, list each as "ISSUE: [description]".
-
-Git diff:
-%s`, gitDiff)
+// A plan without a diff is critiqued on its own.
+func (c *Claude) buildReviewPrompt(req types.ReviewRequest) string {
+	return reviewPrompt(c.templates, req)
 }
 
 // parseReviewOutput uses an LLM to intelligently parse the review output.
 // This allows handling any review format without rigid pattern matching.
-func (c *Claude) parseReviewOutput(output string) *types.ReviewResult {
+// The parsing call is cancelled along with ctx.
+func (c *Claude) parseReviewOutput(ctx context.Context, output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	// Use the LLM to interpret the review output
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	parsePrompt := fmt.Sprintf(`Analyze this code review output and extract any issues found.
-
-INSTRUCTIONS:
-1. If the review indicates the code is good (LGTM, no issues, looks good, etc.), respond with exactly: NO_ISSUES
-2. If there are issues, list each one on a separate line starting with "ISSUE: "
-3. Be concise - just the issue description, no explanations
-
-REVIEW OUTPUT:
-%s
-
-YOUR RESPONSE:`, output)
+	parsePrompt := parseReviewPrompt(c.templates, output)
 
 	// Execute the parsing prompt (no image for parsing)
 	parseOutput, err := c.Execute(ctx, parsePrompt, "")
@@ -201,6 +208,7 @@ YOUR RESPONSE:`, output)
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
@@ -209,6 +217,7 @@ func (c *Claude) parseReviewOutputFallback(output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	outputLower := strings.ToLower(output)
@@ -249,9 +258,45 @@ func (c *Claude) parseReviewOutputFallback(output string) *types.ReviewResult {
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
+// Usage returns the total usage reported by Claude Code across all executions.
+func (c *Claude) Usage() types.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// claudeResult is the JSON envelope printed by claude --output-format json.
+type claudeResult struct {
+	Result       string  `json:"result"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Usage        struct {
+		InputTokens              int `json:"input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// parseClaudeJSON extracts the result text and usage from the JSON output of
+// Claude Code. If the output is not JSON it is returned unchanged and ok is false.
+func parseClaudeJSON(output string) (string, types.Usage, bool) {
+	var result claudeResult
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &result); err != nil {
+		return output, types.Usage{}, false
+	}
+
+	usage := types.Usage{
+		InputTokens:  result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + result.Usage.CacheReadInputTokens,
+		OutputTokens: result.Usage.OutputTokens,
+		CostUSD:      result.TotalCostUSD,
+	}
+	return result.Result, usage, true
+}
+
 // WorkDir returns the working directory configured for this Claude instance.
 func (c *Claude) WorkDir() string {
 	return c.workDir
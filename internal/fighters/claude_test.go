@@ -4,6 +4,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
 func TestNewClaude(t *testing.T) {
@@ -157,3 +160,116 @@ func TestClaude_BuildPromptWithIssues_StructureIntegrity(t *testing.T) {
 		t.Error("ISSUES should come before TAREA")
 	}
 }
+
+func TestParseClaudeJSON(t *testing.T) {
+	output := `{"type":"result","subtype":"success","result":"LGTM: No issues found","total_cost_usd":0.0425,` +
+		`"usage":{"input_tokens":120,"cache_read_input_tokens":3000,"output_tokens":45}}`
+
+	text, usage, ok := parseClaudeJSON(output)
+	if !ok {
+		t.Fatal("parseClaudeJSON() should accept the JSON envelope")
+	}
+	if text != "LGTM: No issues found" {
+		t.Errorf("result = %q", text)
+	}
+	if usage.InputTokens != 3120 || usage.OutputTokens != 45 || usage.CostUSD != 0.0425 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestParseClaudeJSON_PlainText(t *testing.T) {
+	text, usage, ok := parseClaudeJSON("ISSUE: missing error handling")
+	if ok {
+		t.Error("parseClaudeJSON() should reject plain text")
+	}
+	if text != "ISSUE: missing error handling" {
+		t.Errorf("plain text should be returned unchanged, got %q", text)
+	}
+	if usage != (types.Usage{}) {
+		t.Errorf("usage = %+v, want zero", usage)
+	}
+}
+
+func TestClaude_ImplementsUsageReporter(t *testing.T) {
+	var _ UsageReporter = NewClaude("/tmp", 0)
+}
+
+func TestClaude_buildReviewPrompt_Plan(t *testing.T) {
+	claude := NewClaude("/tmp", 5*time.Minute)
+
+	prompt := claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line"})
+	if strings.Contains(prompt, "Approved plan") {
+		t.Error("review prompt without a plan should not mention one")
+	}
+
+	prompt = claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line", Plan: "1. Add the cache"})
+	for _, expected := range []string{"Approved plan:\n1. Add the cache", "missing or implemented differently", "Git diff:\n+added line"} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("buildReviewPrompt() should contain %q", expected)
+		}
+	}
+
+	prompt = claude.buildReviewPrompt(types.ReviewRequest{Plan: "1. Add the cache"})
+	if !strings.Contains(prompt, "implementation plan before any code is written") || strings.Contains(prompt, "Git diff:") {
+		t.Errorf("a plan without a diff should be critiqued on its own, got %q", prompt)
+	}
+}
+
+func TestClaude_buildReviewPrompt_OpenIssues(t *testing.T) {
+	claude := NewClaude("/tmp", 5*time.Minute)
+
+	prompt := claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line"})
+	if strings.Contains(prompt, "STATUS:") {
+		t.Error("review prompt without open issues should not ask for their status")
+	}
+
+	issue := types.ParseIssue("[high] main.go:12 - Unchecked error")
+	prompt = claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line", OpenIssues: []types.Issue{issue}})
+	for _, expected := range []string{"- #" + issue.ID + " [high] main.go:12: Unchecked error", `"STATUS: #id resolved|open|disputed - reason"`} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("buildReviewPrompt() should contain %q, got %q", expected, prompt)
+		}
+	}
+}
+
+func TestClaude_buildReviewPrompt_Chunk(t *testing.T) {
+	claude := NewClaude("/tmp", 5*time.Minute)
+
+	prompt := claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line"})
+	if strings.Contains(prompt, "reviewed in") {
+		t.Error("review prompt of a whole diff should not mention parts")
+	}
+
+	chunk := &types.ReviewChunk{Index: 2, Total: 3, Files: []string{"a.go", "b.go"}}
+	prompt = claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line", Chunk: chunk})
+	for _, expected := range []string{"part 2 of 3", "a.go, b.go", "Review this part only", "Git diff:\n+added line"} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("buildReviewPrompt() should contain %q, got %q", expected, prompt)
+		}
+	}
+
+	issue := types.ParseIssue("[high] a.go:12 - Unchecked error")
+	cross := &types.ReviewChunk{Total: 3, Files: []string{"a.go", "b.go"}, Findings: []types.Issue{issue}}
+	prompt = claude.buildReviewPrompt(types.ReviewRequest{Diff: "diff --git a/a.go b/a.go\n", Chunk: cross})
+	for _, expected := range []string{"outline of all of them", "span several parts", "Issues already found in the parts:\n- [high] a.go:12: Unchecked error"} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("cross-file prompt should contain %q, got %q", expected, prompt)
+		}
+	}
+}
+
+func TestClaude_SetTemplates(t *testing.T) {
+	claude := NewClaude("/tmp", 5*time.Minute)
+	templates, err := prompts.Load("es", prompts.Overrides{prompts.Implement: "Round {{.Round}}: {{.Prompt}}"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	SetTemplates(claude, templates)
+
+	if got := claude.BuildPrompt(prompts.Data{Prompt: "add caching", Round: 2}); got != "Round 2: add caching" {
+		t.Errorf("BuildPrompt() = %q, want the overridden template", got)
+	}
+	if prompt := claude.buildReviewPrompt(types.ReviewRequest{Diff: "+added line"}); !strings.HasPrefix(prompt, "Revisa el siguiente git diff") {
+		t.Errorf("buildReviewPrompt() = %q, want the Spanish prompt", prompt)
+	}
+}
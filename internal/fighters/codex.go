@@ -8,18 +8,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
 // Codex represents the Codex fighter (the reviewer).
 // It wraps the codex CLI tool for performing code reviews.
 type Codex struct {
-	workDir string
-	timeout time.Duration
+	workDir   string
+	timeout   time.Duration
+	templates *prompts.Templates
 }
 
-// Ensure Codex implements the Fighter interface.
-var _ Fighter = (*Codex)(nil)
+// Ensure Codex implements the Implementer, Reviewer, PromptBuilder and Templated interfaces.
+var (
+	_ Implementer   = (*Codex)(nil)
+	_ Reviewer      = (*Codex)(nil)
+	_ PromptBuilder = (*Codex)(nil)
+	_ Templated     = (*Codex)(nil)
+)
 
 // NewCodex creates a new Codex fighter instance.
 // workDir specifies the working directory for command execution.
@@ -40,79 +48,42 @@ func (c *Codex) Name() string {
 }
 
 // Review executes Codex to review a git diff and returns the parsed review result.
-// It uses `codex review --uncommitted` command.
-func (c *Codex) Review(ctx context.Context, gitDiff string) (*types.ReviewResult, error) {
-	// Check if codex is installed
-	if _, err := exec.LookPath("codex"); err != nil {
-		return nil, fmt.Errorf("codex CLI not found in PATH: %w", err)
-	}
-
-	// Create context with timeout if not already set
-	execCtx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	// Build and execute the command using `codex review --uncommitted`
-	cmd := exec.CommandContext(execCtx, "codex", "review", "--uncommitted")
-	cmd.Dir = c.workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	// Combine stdout and stderr for complete output
-	combinedOutput := stdout.String()
-	if stderr.Len() > 0 {
-		if combinedOutput != "" {
-			combinedOutput += "\n"
-		}
-		combinedOutput += stderr.String()
-	}
-
+// The diff is sent as a prompt rather than reviewed with `codex review --uncommitted`,
+// which would review the whole working tree: the diff of a request is relative to
+// the session's baseline and filtered by the project's path rules.
+func (c *Codex) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	output, err := c.Execute(ctx, c.buildDiffReviewPrompt(req), "")
 	if err != nil {
-		// Check if context was cancelled or timed out
-		if execCtx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("codex execution timed out after %v", c.timeout)
-		}
-		if execCtx.Err() == context.Canceled {
-			return nil, fmt.Errorf("codex execution was cancelled")
-		}
-		return nil, fmt.Errorf("codex execution failed: %w", err)
+		return nil, err
 	}
-
-	// Parse the output and return the review result
-	return c.parseReviewOutput(combinedOutput), nil
+	result := c.parseReviewOutput(ctx, output)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("codex review was interrupted: %w", err)
+	}
+	return result, nil
 }
 
-// buildReviewPrompt constructs the review instructions for Codex (kept for testing).
-func (c *Codex) buildReviewPrompt() string {
-	return `Find real issues: bugs, vulnerabilities, bad practices, missing error handling. If NO issues respond "LGTM: No issues found". If issues found, list each as "ISSUE: [description]".`
+// buildDiffReviewPrompt renders the prompt reviewing the diff, against the
+// plan if there is one, or critiquing the plan alone when there is no diff.
+func (c *Codex) buildDiffReviewPrompt(req types.ReviewRequest) string {
+	return reviewPrompt(c.templates, req)
 }
 
 // parseReviewOutput uses an LLM to intelligently parse the review output.
 // This allows handling any review format without rigid pattern matching.
-func (c *Codex) parseReviewOutput(output string) *types.ReviewResult {
+// The parsing call is cancelled along with ctx.
+func (c *Codex) parseReviewOutput(ctx context.Context, output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	// Use the LLM to interpret the review output
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	parsePrompt := fmt.Sprintf(`Analyze this code review output and extract any issues found.
-
-INSTRUCTIONS:
-1. If the review indicates the code is good (LGTM, no issues, looks good, etc.), respond with exactly: NO_ISSUES
-2. If there are issues, list each one on a separate line starting with "ISSUE: "
-3. Be concise - just the issue description, no explanations
-
-REVIEW OUTPUT:
-%s
-
-YOUR RESPONSE:`, output)
+	parsePrompt := parseReviewPrompt(c.templates, output)
 
 	// Execute the parsing prompt (no image for parsing)
 	parseOutput, err := c.Execute(ctx, parsePrompt, "")
@@ -143,6 +114,7 @@ YOUR RESPONSE:`, output)
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
@@ -151,6 +123,7 @@ func (c *Codex) parseReviewOutputFallback(output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	outputLower := strings.ToLower(output)
@@ -191,6 +164,7 @@ func (c *Codex) parseReviewOutputFallback(output string) *types.ReviewResult {
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
@@ -215,14 +189,14 @@ func (c *Codex) Execute(ctx context.Context, prompt string, imagePath string) (s
 	}
 
 	// Build and execute the command
-	cmd := exec.CommandContext(execCtx, "codex", args...)
+	cmd := proc.CommandContext(execCtx, "codex", args...)
 	cmd.Dir = c.workDir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.Run(cmd)
 
 	// Combine stdout and stderr for complete output
 	combinedOutput := stdout.String()
@@ -248,26 +222,19 @@ func (c *Codex) Execute(ctx context.Context, prompt string, imagePath string) (s
 }
 
 // BuildPromptWithIssues constructs a prompt for Codex that includes
-// previous issues found during code review.
+// previous issues found during code review, from the implement template.
 func (c *Codex) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
-	if len(previousIssues) == 0 {
-		return basePrompt
-	}
-
-	var sb strings.Builder
-	sb.WriteString("CONTEXTO: Estas en una sesion de code review iterativo.\n\n")
-	sb.WriteString("ISSUES ENCONTRADOS EN LA REVISION ANTERIOR:\n")
-
-	for _, issue := range previousIssues {
-		sb.WriteString("- ")
-		sb.WriteString(issue)
-		sb.WriteString("\n")
-	}
+	return c.BuildPrompt(prompts.Data{Prompt: basePrompt, Issues: previousIssues})
+}
 
-	sb.WriteString("\nTAREA: Corrige los issues mencionados arriba.\n")
-	sb.WriteString("No expliques los cambios, solo implementa las correcciones.\n")
+// BuildPrompt renders the implementer prompt of a round from the implement template.
+func (c *Codex) BuildPrompt(data prompts.Data) string {
+	return c.templates.Render(prompts.Implement, data)
+}
 
-	return sb.String()
+// SetTemplates sets the templates Codex renders its prompts from.
+func (c *Codex) SetTemplates(t *prompts.Templates) {
+	c.templates = t
 }
 
 // WorkDir returns the working directory configured for this Codex instance.
@@ -1,9 +1,16 @@
 package fighters
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
 func TestNewCodex(t *testing.T) {
@@ -62,22 +69,27 @@ func TestCodex_ImplementsFighter(t *testing.T) {
 	var _ Fighter = (*Codex)(nil)
 }
 
-func TestCodex_buildReviewPrompt(t *testing.T) {
+func TestCodex_buildDiffReviewPrompt(t *testing.T) {
 	codex := NewCodex("/tmp", 5*time.Minute)
 
-	result := codex.buildReviewPrompt()
-
-	// Verify the prompt contains expected elements
-	expectedContents := []string{
-		"LGTM: No issues found",
-		"ISSUE:",
+	prompt := codex.buildDiffReviewPrompt(types.ReviewRequest{Diff: "+added line"})
+	for _, expected := range []string{"LGTM: No issues found", "ISSUE:", "Git diff:\n+added line"} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("buildDiffReviewPrompt() should contain %q", expected)
+		}
 	}
 
-	for _, expected := range expectedContents {
-		if !strings.Contains(result, expected) {
-			t.Errorf("buildReviewPrompt() should contain %q", expected)
+	prompt = codex.buildDiffReviewPrompt(types.ReviewRequest{Diff: "+added line", Plan: "1. Add the cache"})
+	for _, expected := range []string{"ISSUE:", "Approved plan:\n1. Add the cache", "Git diff:\n+added line"} {
+		if !strings.Contains(prompt, expected) {
+			t.Errorf("buildDiffReviewPrompt() should contain %q", expected)
 		}
 	}
+
+	prompt = codex.buildDiffReviewPrompt(types.ReviewRequest{Plan: "1. Add the cache"})
+	if strings.Contains(prompt, "Git diff:") {
+		t.Error("a plan critique should not ask for a diff review")
+	}
 }
 
 func TestCodex_parseReviewOutput_LGTM(t *testing.T) {
@@ -111,7 +123,7 @@ func TestCodex_parseReviewOutput_LGTM(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := codex.parseReviewOutput(tt.output)
+			result := codex.parseReviewOutput(context.Background(), tt.output)
 
 			if result.HasIssues {
 				t.Errorf("parseReviewOutput(%q) HasIssues = true, want false", tt.output)
@@ -307,3 +319,47 @@ However:
 		})
 	}
 }
+
+func TestCodex_parseReviewOutputFallback_Findings(t *testing.T) {
+	codex := NewCodex("/tmp", 5*time.Minute)
+
+	result := codex.parseReviewOutputFallback(`- [P1] Reviewer selection ignored — orchestrator.go:34-63
+- [P3] Minor style issue`)
+
+	if len(result.Findings) != 2 {
+		t.Fatalf("parseReviewOutputFallback() got %d findings, want 2: %+v", len(result.Findings), result.Findings)
+	}
+	first := result.Findings[0]
+	if first.Severity != types.SeverityHigh || first.File != "orchestrator.go" || first.StartLine != 34 || first.EndLine != 63 {
+		t.Errorf("first finding = %+v, want a high issue in orchestrator.go:34-63", first)
+	}
+	if result.Findings[1].Severity != types.SeverityLow {
+		t.Errorf("second finding severity = %s, want low", result.Findings[1].Severity)
+	}
+}
+
+func TestCodex_Review_InterruptedWhileParsing(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("the fake codex CLI needs a POSIX shell")
+	}
+
+	// The fake codex reviews at once and hangs when asked to parse the review
+	bin := t.TempDir()
+	script := "#!/bin/sh\ncase \"$2\" in\n*\"REVIEW OUTPUT:\"*) sleep 30 ;;\n*) echo \"[P1] a.go:1 - Bug\" ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := NewCodex(t.TempDir(), time.Minute).Review(ctx, types.ReviewRequest{Round: 1, Diff: "+added line"})
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Review() = %+v, %v, want the interruption", result, err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Review() returned after %v, want the parsing stopped with the context", elapsed)
+	}
+}
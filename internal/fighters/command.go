@@ -0,0 +1,453 @@
+package fighters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// How a command fighter gets its prompt.
+const (
+	// InputArgv passes the prompt as an argument, with {{.Prompt}}
+	InputArgv = "argv"
+
+	// InputStdin writes the prompt to the command's standard input
+	InputStdin = "stdin"
+
+	// InputFile writes the prompt to a temporary file, passed as an argument
+	// with {{.PromptFile}} and removed when the command exits
+	InputFile = "file"
+)
+
+// How the output of a command fighter's review is parsed.
+const (
+	// ReviewOutputIssues reads the "ISSUE:" lines of the output; a review
+	// without any approves the changes
+	ReviewOutputIssues = "issues"
+
+	// ReviewOutputFighter asks the command itself to extract the issues from
+	// its review, like the built-in fighters do
+	ReviewOutputFighter = "fighter"
+
+	// ReviewOutputExitCode approves the changes when the command exits with
+	// status 0; otherwise its "ISSUE:" lines, or every line of its output if
+	// there are none, are the issues
+	ReviewOutputExitCode = "exit_code"
+)
+
+// CommandSpec defines a fighter that runs any command-line tool, such as
+// another coding agent or a project script. It is read from the project file.
+type CommandSpec struct {
+	// Name is the display name of the fighter, the upper-cased fighter type
+	// if empty
+	Name string `json:"name,omitempty"`
+
+	// Command is the binary to run, looked up in PATH, or a path relative to
+	// the working directory
+	Command string `json:"command"`
+
+	// Args are the arguments of the command, each a text/template rendered
+	// with {{.Prompt}}, {{.PromptFile}}, {{.Image}} and {{.WorkDir}}.
+	// Arguments rendered empty are dropped, so that an argument can depend on
+	// an attached image
+	Args []string `json:"args,omitempty"`
+
+	// Input is how the command gets its prompt, InputArgv (the default),
+	// InputStdin or InputFile
+	Input string `json:"input,omitempty"`
+
+	// Timeout is the time limit for a single execution, such as "10m",
+	// replacing --fighter-timeout for this fighter
+	Timeout string `json:"timeout,omitempty"`
+
+	// Env are environment variables set for the command, in addition to
+	// those of mortal-prompter
+	Env map[string]string `json:"env,omitempty"`
+
+	// ReviewOutput is how the output of a review is parsed,
+	// ReviewOutputIssues (the default), ReviewOutputFighter or
+	// ReviewOutputExitCode
+	ReviewOutput string `json:"review_output,omitempty"`
+}
+
+// commandData is what the arguments of a command fighter are rendered with.
+type commandData struct {
+	Prompt     string
+	PromptFile string
+	Image      string
+	WorkDir    string
+}
+
+// Validate checks that the spec can be run.
+func (s CommandSpec) Validate() error {
+	if strings.TrimSpace(s.Command) == "" {
+		return errors.New("command is required")
+	}
+
+	switch s.input() {
+	case InputArgv, InputStdin, InputFile:
+	default:
+		return fmt.Errorf("invalid input %q (must be %s, %s or %s)", s.Input, InputArgv, InputStdin, InputFile)
+	}
+	switch s.reviewOutput() {
+	case ReviewOutputIssues, ReviewOutputFighter, ReviewOutputExitCode:
+	default:
+		return fmt.Errorf("invalid review_output %q (must be %s, %s or %s)",
+			s.ReviewOutput, ReviewOutputIssues, ReviewOutputFighter, ReviewOutputExitCode)
+	}
+
+	if s.Timeout != "" {
+		timeout, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return errors.New("timeout must be positive")
+		}
+	}
+
+	for name := range s.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+	}
+
+	// Render the arguments with markers to catch mistakes now, and check
+	// that the prompt reaches the command
+	args, err := s.args(commandData{Prompt: "\x00prompt", PromptFile: "\x00file", Image: "image.png", WorkDir: "."})
+	if err != nil {
+		return err
+	}
+	joined := strings.Join(args, " ")
+	switch {
+	case s.input() == InputArgv && !strings.Contains(joined, "\x00prompt"):
+		return errors.New("no argument passes the prompt: use {{.Prompt}}, or set input to stdin or file")
+	case s.input() == InputFile && !strings.Contains(joined, "\x00file"):
+		return errors.New("no argument passes the prompt file: use {{.PromptFile}}")
+	}
+	return nil
+}
+
+// input returns how the command gets its prompt.
+func (s CommandSpec) input() string {
+	if s.Input == "" {
+		return InputArgv
+	}
+	return s.Input
+}
+
+// reviewOutput returns how the output of a review is parsed.
+func (s CommandSpec) reviewOutput() string {
+	if s.ReviewOutput == "" {
+		return ReviewOutputIssues
+	}
+	return s.ReviewOutput
+}
+
+// timeout returns the spec's time limit, or 0 if it has none.
+func (s CommandSpec) timeout() time.Duration {
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// args renders the arguments with data, dropping the empty ones.
+func (s CommandSpec) args(data commandData) ([]string, error) {
+	args := make([]string, 0, len(s.Args))
+	for i, arg := range s.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		if sb.Len() > 0 {
+			args = append(args, sb.String())
+		}
+	}
+	return args, nil
+}
+
+// Command is a fighter running a command-line tool defined by a CommandSpec.
+// It can act as both implementer and reviewer.
+type Command struct {
+	fighterType FighterType
+	spec        CommandSpec
+	workDir     string
+	timeout     time.Duration
+	templates   *prompts.Templates
+}
+
+// Ensure Command implements the Implementer, Reviewer, PromptBuilder and Templated interfaces.
+var (
+	_ Implementer   = (*Command)(nil)
+	_ Reviewer      = (*Command)(nil)
+	_ PromptBuilder = (*Command)(nil)
+	_ Templated     = (*Command)(nil)
+)
+
+// NewCommand creates a command fighter of type ft running spec.
+// workDir specifies the working directory for command execution.
+// timeout specifies the maximum duration for command execution, unless the
+// spec sets its own.
+func NewCommand(ft FighterType, spec CommandSpec, workDir string, timeout time.Duration) *Command {
+	if t := spec.timeout(); t > 0 {
+		timeout = t
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Command{
+		fighterType: ft,
+		spec:        spec,
+		workDir:     workDir,
+		timeout:     timeout,
+	}
+}
+
+// Name returns the display name of the command fighter.
+func (c *Command) Name() string {
+	if c.spec.Name != "" {
+		return c.spec.Name
+	}
+	return strings.ToUpper(string(c.fighterType))
+}
+
+// Execute runs the command with the provided prompt and optional image path.
+// It uses the context for timeout/cancellation support.
+func (c *Command) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	return c.run(ctx, prompt, imagePath)
+}
+
+// run runs the command with prompt and returns its combined output. A
+// non-zero exit status is returned as an error wrapping the *exec.ExitError.
+func (c *Command) run(ctx context.Context, prompt string, imagePath string) (string, error) {
+	command := c.spec.Command
+	if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+		command = filepath.Join(c.workDir, command)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return "", fmt.Errorf("%s not found: %w", c.spec.Command, err)
+	}
+
+	// Create context with timeout if not already set
+	execCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	data := commandData{Prompt: prompt, Image: imagePath, WorkDir: c.workDir}
+	if c.spec.input() == InputFile {
+		file, err := writePromptFile(prompt)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(file)
+		data.PromptFile = file
+	}
+	args, err := c.spec.args(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.fighterType, err)
+	}
+
+	cmd := proc.CommandContext(execCtx, command, args...)
+	cmd.Dir = c.workDir
+	if len(c.spec.Env) > 0 {
+		cmd.Env = os.Environ()
+		for name, value := range c.spec.Env {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	if c.spec.input() == InputStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = proc.Run(cmd)
+
+	// Combine stdout and stderr for complete output
+	combinedOutput := stdout.String()
+	if stderr.Len() > 0 {
+		if combinedOutput != "" {
+			combinedOutput += "\n"
+		}
+		combinedOutput += stderr.String()
+	}
+
+	if err != nil {
+		// Check if context was cancelled or timed out
+		if execCtx.Err() == context.DeadlineExceeded {
+			return combinedOutput, fmt.Errorf("%s execution timed out after %v", c.fighterType, c.timeout)
+		}
+		if execCtx.Err() == context.Canceled {
+			return combinedOutput, fmt.Errorf("%s execution was cancelled", c.fighterType)
+		}
+		return combinedOutput, fmt.Errorf("%s execution failed: %w", c.fighterType, err)
+	}
+
+	return combinedOutput, nil
+}
+
+// writePromptFile writes prompt to a new temporary file and returns its path.
+func writePromptFile(prompt string) (string, error) {
+	file, err := os.CreateTemp("", "mortal-prompter-prompt-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the prompt file: %w", err)
+	}
+	_, err = file.WriteString(prompt)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write the prompt file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// Review runs the command on the review prompt of req and parses its output
+// as the spec's ReviewOutput says.
+func (c *Command) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	output, err := c.run(ctx, c.buildReviewPrompt(req), "")
+
+	if c.spec.reviewOutput() == ReviewOutputExitCode {
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			return &types.ReviewResult{RawOutput: output, Issues: []string{}, Verdicts: types.ParseVerdicts(output)}, nil
+		case errors.As(err, &exitErr):
+			return failedReview(output), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.spec.reviewOutput() == ReviewOutputFighter {
+		result := c.parseReviewOutput(ctx, output)
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%s review was interrupted: %w", c.fighterType, err)
+		}
+		return result, nil
+	}
+	return issueLinesReview(output), nil
+}
+
+// buildReviewPrompt constructs the review prompt for the command.
+// A plan without a diff is critiqued on its own.
+func (c *Command) buildReviewPrompt(req types.ReviewRequest) string {
+	return reviewPrompt(c.templates, req)
+}
+
+// parseReviewOutput asks the command to extract the issues from its review,
+// falling back to the "ISSUE:" lines of the review if that fails.
+func (c *Command) parseReviewOutput(ctx context.Context, output string) *types.ReviewResult {
+	parseOutput, err := c.run(ctx, parseReviewPrompt(c.templates, output), "")
+	if err != nil {
+		return issueLinesReview(output)
+	}
+
+	result := &types.ReviewResult{
+		RawOutput: output,
+		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
+	}
+	if strings.Contains(strings.ToUpper(parseOutput), "NO_ISSUES") {
+		return result
+	}
+	result.Issues = issueLines(parseOutput)
+	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
+	return result
+}
+
+// issueLinesReview returns the review whose issues are the "ISSUE:" lines of output.
+func issueLinesReview(output string) *types.ReviewResult {
+	issues := issueLines(output)
+	return &types.ReviewResult{
+		RawOutput: output,
+		HasIssues: len(issues) > 0,
+		Issues:    issues,
+		Findings:  types.ParseIssues(issues),
+		Verdicts:  types.ParseVerdicts(output),
+	}
+}
+
+// failedReview returns the review of a command that exited with a non-zero
+// status: its "ISSUE:" lines, or every line of its output if there are none.
+func failedReview(output string) *types.ReviewResult {
+	issues := issueLines(output)
+	if len(issues) == 0 {
+		for _, line := range strings.Split(output, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				issues = append(issues, line)
+			}
+		}
+	}
+	if len(issues) == 0 {
+		issues = []string{"the review command failed without output"}
+	}
+	return &types.ReviewResult{
+		RawOutput: output,
+		HasIssues: true,
+		Issues:    issues,
+		Findings:  types.ParseIssues(issues),
+		Verdicts:  types.ParseVerdicts(output),
+	}
+}
+
+// issueLines returns the issues of the lines of output starting with "ISSUE:".
+func issueLines(output string) []string {
+	issues := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) >= 6 && strings.EqualFold(line[:6], "ISSUE:") {
+			if issue := strings.TrimSpace(line[6:]); issue != "" {
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// BuildPromptWithIssues constructs a prompt for the command that includes
+// previous issues found during code review, from the implement template.
+func (c *Command) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return c.BuildPrompt(prompts.Data{Prompt: basePrompt, Issues: previousIssues})
+}
+
+// BuildPrompt renders the implementer prompt of a round from the implement template.
+func (c *Command) BuildPrompt(data prompts.Data) string {
+	return c.templates.Render(prompts.Implement, data)
+}
+
+// SetTemplates sets the templates the command renders its prompts from.
+func (c *Command) SetTemplates(t *prompts.Templates) {
+	c.templates = t
+}
+
+// WorkDir returns the working directory configured for this command fighter.
+func (c *Command) WorkDir() string {
+	return c.workDir
+}
+
+// Timeout returns the timeout configured for this command fighter.
+func (c *Command) Timeout() time.Duration {
+	return c.timeout
+}
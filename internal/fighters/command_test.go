@@ -0,0 +1,192 @@
+package fighters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// registerCommand registers spec as ft for the duration of the test.
+func registerCommand(t *testing.T, ft FighterType, spec CommandSpec) {
+	t.Helper()
+	if err := RegisterCommand(ft, spec); err != nil {
+		t.Fatalf("RegisterCommand() error = %v", err)
+	}
+	t.Cleanup(func() {
+		commandsMu.Lock()
+		delete(commands, ft)
+		commandsMu.Unlock()
+	})
+}
+
+func TestCommandSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec CommandSpec
+		want string
+	}{
+		{"valid", CommandSpec{Command: "aider", Args: []string{"--message", "{{.Prompt}}"}}, ""},
+		{"stdin", CommandSpec{Command: "opencode", Input: InputStdin}, ""},
+		{"file", CommandSpec{Command: "review.sh", Args: []string{"{{.PromptFile}}"}, Input: InputFile, ReviewOutput: ReviewOutputExitCode}, ""},
+		{"no command", CommandSpec{Args: []string{"{{.Prompt}}"}}, "command is required"},
+		{"no prompt argument", CommandSpec{Command: "aider", Args: []string{"--yes"}}, "no argument passes the prompt"},
+		{"no prompt file argument", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}"}, Input: InputFile}, "no argument passes the prompt file"},
+		{"unknown input", CommandSpec{Command: "aider", Input: "pipe"}, "invalid input"},
+		{"unknown review output", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}"}, ReviewOutput: "json"}, "invalid review_output"},
+		{"bad timeout", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}"}, Timeout: "ten minutes"}, "invalid timeout"},
+		{"negative timeout", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}"}, Timeout: "-1m"}, "must be positive"},
+		{"bad argument", CommandSpec{Command: "aider", Args: []string{"{{.Prompt"}}, "invalid argument 1"},
+		{"unknown placeholder", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}", "{{.Model}}"}}, "invalid argument 2"},
+		{"bad env", CommandSpec{Command: "aider", Args: []string{"{{.Prompt}}"}, Env: map[string]string{"A=B": "c"}}, "invalid environment variable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterCommand(t *testing.T) {
+	registerCommand(t, "aider", CommandSpec{Name: "AIDER", Command: "aider", Args: []string{"--message", "{{.Prompt}}"}, Timeout: "10m"})
+
+	all := AllFighterTypes()
+	if len(all) != 4 || all[3] != "aider" {
+		t.Errorf("AllFighterTypes() = %v, want the built-in fighters then aider", all)
+	}
+	f, err := New("aider", "/tmp", time.Minute)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if f.Name() != "AIDER" || DisplayName("aider") != "AIDER" {
+		t.Errorf("Name() = %q, want AIDER", f.Name())
+	}
+	if got := f.(*Command).Timeout(); got != 10*time.Minute {
+		t.Errorf("Timeout() = %v, want the spec's 10m", got)
+	}
+
+	if err := RegisterCommand("claude", CommandSpec{Command: "claude", Input: InputStdin}); err == nil {
+		t.Error("RegisterCommand() should not replace a built-in fighter")
+	}
+	if err := RegisterCommand("My Agent", CommandSpec{Command: "agent", Input: InputStdin}); err == nil {
+		t.Error("RegisterCommand() should reject an invalid fighter type")
+	}
+	if err := RegisterCommand("broken", CommandSpec{}); err == nil {
+		t.Error("RegisterCommand() should reject an invalid spec")
+	}
+}
+
+func TestCommand_Execute(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		spec  CommandSpec
+		image string
+		want  string
+	}{
+		{"argv", CommandSpec{Command: "echo", Args: []string{"{{.Prompt}}"}}, "", "add caching\n"},
+		{"stdin", CommandSpec{Command: "cat", Input: InputStdin}, "", "add caching"},
+		{"file", CommandSpec{Command: "cat", Args: []string{"{{.PromptFile}}"}, Input: InputFile}, "", "add caching"},
+		{"image and workdir", CommandSpec{Command: "echo", Args: []string{"{{.Prompt}}", "{{if .Image}}--image={{.Image}}{{end}}", "{{.WorkDir}}"}}, "shot.png", "add caching --image=shot.png " + dir + "\n"},
+		{"empty argument dropped", CommandSpec{Command: "sh", Args: []string{"-c", `echo "$#"`, "sh", "{{.Image}}", "{{.Prompt}}"}}, "", "1\n"},
+		{"env", CommandSpec{Command: "sh", Args: []string{"-c", `printf '%s %s' "$GREETING" "$1"`, "sh", "{{.Prompt}}"}, Env: map[string]string{"GREETING": "hello"}}, "", "hello add caching"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCommand("test", tt.spec, dir, time.Minute)
+			got, err := c.Execute(context.Background(), "add caching", tt.image)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Execute_RelativeCommand(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "scripts", "agent.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"agent: $1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCommand("agent", CommandSpec{Command: "./scripts/agent.sh", Args: []string{"{{.Prompt}}"}}, dir, time.Minute)
+	got, err := c.Execute(context.Background(), "add caching", "")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got != "agent: add caching\n" {
+		t.Errorf("Execute() = %q", got)
+	}
+}
+
+func TestCommand_Execute_Errors(t *testing.T) {
+	c := NewCommand("missing", CommandSpec{Command: "mortal-prompter-no-such-agent", Input: InputStdin}, t.TempDir(), time.Minute)
+	if _, err := c.Execute(context.Background(), "add caching", ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Execute() error = %v, want a not found error", err)
+	}
+
+	c = NewCommand("slow", CommandSpec{Command: "sleep", Args: []string{"5"}, Input: InputStdin}, t.TempDir(), 50*time.Millisecond)
+	if _, err := c.Execute(context.Background(), "add caching", ""); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Execute() error = %v, want a timeout", err)
+	}
+}
+
+func TestCommand_Review(t *testing.T) {
+	req := types.ReviewRequest{Diff: "+cache"}
+	tests := []struct {
+		name   string
+		spec   CommandSpec
+		issues []string
+	}{
+		{"issues approved", CommandSpec{Command: "sh", Args: []string{"-c", "echo 'LGTM: No issues found'"}, Input: InputStdin}, nil},
+		{"issues found", CommandSpec{Command: "sh", Args: []string{"-c", "echo 'Two problems:'; echo 'ISSUE: [high] a.go:1 - Unchecked error'; echo 'issue: [low] b.go:2 - Typo'"}, Input: InputStdin},
+			[]string{"[high] a.go:1 - Unchecked error", "[low] b.go:2 - Typo"}},
+		{"exit code passed", CommandSpec{Command: "sh", Args: []string{"-c", "echo 'all checks passed'"}, Input: InputStdin, ReviewOutput: ReviewOutputExitCode}, nil},
+		{"exit code failed", CommandSpec{Command: "sh", Args: []string{"-c", "echo 'a.go:1: unused variable x'; exit 1"}, Input: InputStdin, ReviewOutput: ReviewOutputExitCode},
+			[]string{"a.go:1: unused variable x"}},
+		{"fighter", CommandSpec{Command: "sh", Args: []string{"-c", `if grep -q 'REVIEW OUTPUT' -; then echo 'ISSUE: [medium] a.go:3 - Missing test'; else echo 'The cache has no test.'; fi`}, Input: InputStdin, ReviewOutput: ReviewOutputFighter},
+			[]string{"[medium] a.go:3 - Missing test"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCommand("test", tt.spec, t.TempDir(), time.Minute)
+			result, err := c.Review(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Review() error = %v", err)
+			}
+			if result.HasIssues != (len(tt.issues) > 0) {
+				t.Errorf("HasIssues = %v, want %v (output %q)", result.HasIssues, len(tt.issues) > 0, result.RawOutput)
+			}
+			if strings.Join(result.Issues, "|") != strings.Join(tt.issues, "|") {
+				t.Errorf("Issues = %q, want %q", result.Issues, tt.issues)
+			}
+			if len(result.Findings) != len(tt.issues) {
+				t.Errorf("Findings = %v, want %d", result.Findings, len(tt.issues))
+			}
+		})
+	}
+
+	c := NewCommand("test", CommandSpec{Command: "sh", Args: []string{"-c", "exit 2"}, Input: InputStdin}, t.TempDir(), time.Minute)
+	if _, err := c.Review(context.Background(), req); err == nil {
+		t.Error("Review() should fail when the command fails outside of the exit_code review output")
+	}
+}
@@ -1,10 +1,12 @@
 // Package fighters provides wrappers for the LLM CLI tools used in mortal-prompter battles.
-// It defines the Claude, Codex, and Gemini fighters.
+// It defines the Claude, Codex, and Gemini fighters, and command fighters
+// running any other tool as a project defines it.
 package fighters
 
 import (
 	"context"
 
+	"github.com/diegoram/mortal-prompter/internal/prompts"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
@@ -17,8 +19,14 @@ const (
 	FighterTypeGemini FighterType = "gemini"
 )
 
-// AllFighterTypes returns all available fighter types
+// AllFighterTypes returns all available fighter types: the built-in ones,
+// then the registered command fighters
 func AllFighterTypes() []FighterType {
+	return append(builtinFighterTypes(), commandTypes()...)
+}
+
+// builtinFighterTypes returns the types of the built-in fighters
+func builtinFighterTypes() []FighterType {
 	return []FighterType{FighterTypeClaude, FighterTypeCodex, FighterTypeGemini}
 }
 
@@ -38,9 +46,47 @@ type Implementer interface {
 	BuildPromptWithIssues(basePrompt string, previousIssues []string) string
 }
 
+// UsageReporter is implemented by fighters whose CLI reports the tokens and
+// money spent. Fighters that cannot report usage simply do not implement it.
+type UsageReporter interface {
+	// Usage returns the total usage of all executions so far.
+	Usage() types.Usage
+}
+
+// UsageOf returns the usage reported by f, or zero usage if f does not report it.
+func UsageOf(f Fighter) types.Usage {
+	if reporter, ok := f.(UsageReporter); ok {
+		return reporter.Usage()
+	}
+	return types.Usage{}
+}
+
+// PromptBuilder is implemented by implementers that render their prompt from
+// the templates with everything known about the round, not only the issues.
+type PromptBuilder interface {
+	// BuildPrompt renders the implementer prompt of a round.
+	BuildPrompt(data prompts.Data) string
+}
+
+// Templated is implemented by fighters whose prompts are rendered from
+// templates. Fighters use the built-in templates until they are given others.
+type Templated interface {
+	// SetTemplates sets the templates the fighter renders its prompts from.
+	SetTemplates(t *prompts.Templates)
+}
+
+// SetTemplates gives f the templates to render its prompts from, if f renders
+// its prompts from templates.
+func SetTemplates(f Fighter, t *prompts.Templates) {
+	if templated, ok := f.(Templated); ok {
+		templated.SetTemplates(t)
+	}
+}
+
 // Reviewer is the interface for fighters that can review code.
 type Reviewer interface {
 	Fighter
-	// Review executes a code review on the git diff and returns the result.
-	Review(ctx context.Context, gitDiff string) (*types.ReviewResult, error)
+	// Review executes a code review on the request's git diff, checked against
+	// its plan if any, or critiques the plan alone, and returns the result.
+	Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error)
 }
@@ -8,18 +8,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
 // Gemini represents the Gemini CLI fighter.
 // It can act as both implementer and reviewer via the gemini CLI tool.
 type Gemini struct {
-	workDir string
-	timeout time.Duration
+	workDir   string
+	timeout   time.Duration
+	templates *prompts.Templates
 }
 
-// Ensure Gemini implements the Fighter interface.
-var _ Fighter = (*Gemini)(nil)
+// Ensure Gemini implements the Implementer, Reviewer, PromptBuilder and Templated interfaces.
+var (
+	_ Implementer   = (*Gemini)(nil)
+	_ Reviewer      = (*Gemini)(nil)
+	_ PromptBuilder = (*Gemini)(nil)
+	_ Templated     = (*Gemini)(nil)
+)
 
 // NewGemini creates a new Gemini fighter instance.
 // workDir specifies the working directory for command execution.
@@ -60,14 +68,14 @@ func (g *Gemini) Execute(ctx context.Context, prompt string, imagePath string) (
 	}
 
 	// Build and execute the command
-	cmd := exec.CommandContext(execCtx, "gemini", "-p", finalPrompt)
+	cmd := proc.CommandContext(execCtx, "gemini", "-p", finalPrompt)
 	cmd.Dir = g.workDir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.Run(cmd)
 
 	// Combine stdout and stderr for complete output
 	combinedOutput := stdout.String()
@@ -94,7 +102,7 @@ func (g *Gemini) Execute(ctx context.Context, prompt string, imagePath string) (
 
 // Review executes Gemini to review a git diff and returns the parsed review result.
 // It sends the diff as a prompt asking for code review.
-func (g *Gemini) Review(ctx context.Context, gitDiff string) (*types.ReviewResult, error) {
+func (g *Gemini) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
 	// Check if gemini is installed
 	if _, err := exec.LookPath("gemini"); err != nil {
 		return nil, fmt.Errorf("gemini CLI not found in PATH: %w", err)
@@ -105,17 +113,17 @@ func (g *Gemini) Review(ctx context.Context, gitDiff string) (*types.ReviewResul
 	defer cancel()
 
 	// Build the review prompt
-	reviewPrompt := g.buildReviewPrompt(gitDiff)
+	reviewPrompt := g.buildReviewPrompt(req)
 
 	// Build and execute the command
-	cmd := exec.CommandContext(execCtx, "gemini", "-p", reviewPrompt)
+	cmd := proc.CommandContext(execCtx, "gemini", "-p", reviewPrompt)
 	cmd.Dir = g.workDir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.Run(cmd)
 
 	// Combine stdout and stderr for complete output
 	combinedOutput := stdout.String()
@@ -138,43 +146,34 @@ func (g *Gemini) Review(ctx context.Context, gitDiff string) (*types.ReviewResul
 	}
 
 	// Parse the output and return the review result
-	return g.parseReviewOutput(combinedOutput), nil
+	result := g.parseReviewOutput(ctx, combinedOutput)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("gemini review was interrupted: %w", err)
+	}
+	return result, nil
 }
 
 // buildReviewPrompt constructs the review prompt for Gemini.
-func (g *Gemini) buildReviewPrompt(gitDiff string) string {
-	return fmt.Sprintf(`Review the following git diff for issues.
-Find real issues: bugs, vulnerabilities, bad practices, missing error handling.
-If NO issues respond "LGTM: No issues found".
-If issues found, list each as "ISSUE: [description]".
-
-Git diff:
-%s`, gitDiff)
+// A plan without a diff is critiqued on its own.
+func (g *Gemini) buildReviewPrompt(req types.ReviewRequest) string {
+	return reviewPrompt(g.templates, req)
 }
 
 // parseReviewOutput uses an LLM to intelligently parse the review output.
 // This allows handling any review format without rigid pattern matching.
-func (g *Gemini) parseReviewOutput(output string) *types.ReviewResult {
+// The parsing call is cancelled along with ctx.
+func (g *Gemini) parseReviewOutput(ctx context.Context, output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	// Use the LLM to interpret the review output
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	parsePrompt := fmt.Sprintf(`Analyze this code review output and extract any issues found.
-
-INSTRUCTIONS:
-1. If the review indicates the code is good (LGTM, no issues, looks good, etc.), respond with exactly: NO_ISSUES
-2. If there are issues, list each one on a separate line starting with "ISSUE: "
-3. Be concise - just the issue description, no explanations
-
-REVIEW OUTPUT:
-%s
-
-YOUR RESPONSE:`, output)
+	parsePrompt := parseReviewPrompt(g.templates, output)
 
 	// Execute the parsing prompt (no image for parsing)
 	parseOutput, err := g.Execute(ctx, parsePrompt, "")
@@ -205,6 +204,7 @@ YOUR RESPONSE:`, output)
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
@@ -213,6 +213,7 @@ func (g *Gemini) parseReviewOutputFallback(output string) *types.ReviewResult {
 	result := &types.ReviewResult{
 		RawOutput: output,
 		Issues:    []string{},
+		Verdicts:  types.ParseVerdicts(output),
 	}
 
 	outputLower := strings.ToLower(output)
@@ -253,32 +254,26 @@ func (g *Gemini) parseReviewOutputFallback(output string) *types.ReviewResult {
 	}
 
 	result.HasIssues = len(result.Issues) > 0
+	result.Findings = types.ParseIssues(result.Issues)
 	return result
 }
 
 // BuildPromptWithIssues constructs a prompt for Gemini that includes
-// previous issues found during code review.
-// If there are no previous issues, it returns the basePrompt as-is.
-// If there are issues, it builds a structured prompt requesting corrections.
+// previous issues found during code review, from the implement template.
+// With the built-in template, if there are no previous issues it returns the
+// basePrompt as-is, and if there are it asks for corrections.
 func (g *Gemini) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
-	if len(previousIssues) == 0 {
-		return basePrompt
-	}
-
-	var sb strings.Builder
-	sb.WriteString("CONTEXTO: Estas en una sesion de code review iterativo.\n\n")
-	sb.WriteString("ISSUES ENCONTRADOS EN LA REVISION ANTERIOR:\n")
-
-	for _, issue := range previousIssues {
-		sb.WriteString("- ")
-		sb.WriteString(issue)
-		sb.WriteString("\n")
-	}
+	return g.BuildPrompt(prompts.Data{Prompt: basePrompt, Issues: previousIssues})
+}
 
-	sb.WriteString("\nTAREA: Corrige los issues mencionados arriba.\n")
-	sb.WriteString("No expliques los cambios, solo implementa las correcciones.\n")
+// BuildPrompt renders the implementer prompt of a round from the implement template.
+func (g *Gemini) BuildPrompt(data prompts.Data) string {
+	return g.templates.Render(prompts.Implement, data)
+}
 
-	return sb.String()
+// SetTemplates sets the templates Gemini renders its prompts from.
+func (g *Gemini) SetTemplates(t *prompts.Templates) {
+	g.templates = t
 }
 
 // WorkDir returns the working directory configured for this Gemini instance.
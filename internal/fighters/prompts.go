@@ -0,0 +1,32 @@
+package fighters
+
+import (
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// reviewPrompt renders the prompt asking a reviewer to review the request's
+// diff, or to critique its plan when there is no diff.
+func reviewPrompt(t *prompts.Templates, req types.ReviewRequest) string {
+	data := prompts.Data{
+		Prompt:     req.Prompt,
+		Round:      req.Round,
+		Plan:       strings.TrimSpace(req.Plan),
+		Stats:      prompts.Stats(req.Diff),
+		Diff:       req.Diff,
+		Range:      req.Range,
+		OpenIssues: req.OpenIssues,
+		Chunk:      req.Chunk,
+	}
+	if req.IsPlanReview() {
+		return t.Render(prompts.PlanReview, data)
+	}
+	return t.Render(prompts.Review, data)
+}
+
+// parseReviewPrompt renders the prompt asking a fighter to extract the issues from a review.
+func parseReviewPrompt(t *prompts.Templates, output string) string {
+	return t.Render(prompts.ParseReview, prompts.Data{Output: output})
+}
@@ -0,0 +1,119 @@
+package fighters
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// New creates the fighter identified by ft.
+// workDir specifies the working directory for command execution.
+// timeout specifies the maximum duration for command execution.
+func New(ft FighterType, workDir string, timeout time.Duration) (Fighter, error) {
+	switch ft {
+	case FighterTypeClaude:
+		return NewClaude(workDir, timeout), nil
+	case FighterTypeCodex:
+		return NewCodex(workDir, timeout), nil
+	case FighterTypeGemini:
+		return NewGemini(workDir, timeout), nil
+	default:
+		if spec, ok := lookupCommand(ft); ok {
+			return NewCommand(ft, spec, workDir, timeout), nil
+		}
+		return nil, fmt.Errorf("unknown fighter type: %s", ft)
+	}
+}
+
+// NewImplementer creates the fighter identified by ft and returns it as an Implementer.
+// It returns an error if the fighter type is unknown or cannot implement code changes.
+func NewImplementer(ft FighterType, workDir string, timeout time.Duration) (Implementer, error) {
+	f, err := New(ft, workDir, timeout)
+	if err != nil {
+		return nil, err
+	}
+	impl, ok := f.(Implementer)
+	if !ok {
+		return nil, fmt.Errorf("fighter %s cannot act as implementer", ft)
+	}
+	return impl, nil
+}
+
+// NewReviewer creates the fighter identified by ft and returns it as a Reviewer.
+// It returns an error if the fighter type is unknown or cannot review code.
+func NewReviewer(ft FighterType, workDir string, timeout time.Duration) (Reviewer, error) {
+	f, err := New(ft, workDir, timeout)
+	if err != nil {
+		return nil, err
+	}
+	rev, ok := f.(Reviewer)
+	if !ok {
+		return nil, fmt.Errorf("fighter %s cannot act as reviewer", ft)
+	}
+	return rev, nil
+}
+
+// DisplayName returns the display name of the fighter identified by ft,
+// falling back to the upper-cased type for unknown fighters.
+func DisplayName(ft FighterType) string {
+	f, err := New(ft, "", 0)
+	if err != nil {
+		return strings.ToUpper(string(ft))
+	}
+	return f.Name()
+}
+
+// commandTypePattern is what the type of a command fighter must look like.
+var commandTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+var (
+	commandsMu sync.RWMutex
+	commands   = make(map[FighterType]CommandSpec)
+)
+
+// RegisterCommand makes spec available as the fighter ft, replacing the
+// command fighter registered as ft before, if any. The built-in fighters
+// cannot be replaced.
+func RegisterCommand(ft FighterType, spec CommandSpec) error {
+	if !commandTypePattern.MatchString(string(ft)) {
+		return fmt.Errorf("invalid fighter type %q: use lowercase letters, digits, - and _", ft)
+	}
+	for _, builtin := range builtinFighterTypes() {
+		if ft == builtin {
+			return fmt.Errorf("fighter type %s is built in", ft)
+		}
+	}
+	if err := spec.Validate(); err != nil {
+		return fmt.Errorf("invalid fighter %s: %w", ft, err)
+	}
+
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	commands[ft] = spec
+	return nil
+}
+
+// commandTypes returns the types of the registered command fighters, sorted.
+func commandTypes() []FighterType {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+
+	list := make([]FighterType, 0, len(commands))
+	for ft := range commands {
+		list = append(list, ft)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// lookupCommand returns the spec of the command fighter ft.
+func lookupCommand(ft FighterType) (CommandSpec, bool) {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+
+	spec, ok := commands[ft]
+	return spec, ok
+}
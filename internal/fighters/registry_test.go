@@ -0,0 +1,66 @@
+package fighters
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew_KnownFighters(t *testing.T) {
+	tests := []struct {
+		fighterType  FighterType
+		expectedName string
+	}{
+		{FighterTypeClaude, "CLAUDE CODE"},
+		{FighterTypeCodex, "CODEX"},
+		{FighterTypeGemini, "GEMINI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.fighterType), func(t *testing.T) {
+			f, err := New(tt.fighterType, "/tmp", time.Minute)
+			if err != nil {
+				t.Fatalf("New(%q) error = %v", tt.fighterType, err)
+			}
+			if f.Name() != tt.expectedName {
+				t.Errorf("New(%q).Name() = %q, want %q", tt.fighterType, f.Name(), tt.expectedName)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownFighter(t *testing.T) {
+	if _, err := New("skynet", "/tmp", time.Minute); err == nil {
+		t.Error("New() with unknown fighter type should return an error")
+	}
+}
+
+func TestNewImplementerAndReviewer(t *testing.T) {
+	for _, ft := range AllFighterTypes() {
+		impl, err := NewImplementer(ft, "/tmp", time.Minute)
+		if err != nil {
+			t.Errorf("NewImplementer(%q) error = %v", ft, err)
+		} else if impl.Name() != DisplayName(ft) {
+			t.Errorf("NewImplementer(%q).Name() = %q, want %q", ft, impl.Name(), DisplayName(ft))
+		}
+
+		rev, err := NewReviewer(ft, "/tmp", time.Minute)
+		if err != nil {
+			t.Errorf("NewReviewer(%q) error = %v", ft, err)
+		} else if rev.Name() != DisplayName(ft) {
+			t.Errorf("NewReviewer(%q).Name() = %q, want %q", ft, rev.Name(), DisplayName(ft))
+		}
+	}
+
+	if _, err := NewImplementer("skynet", "/tmp", time.Minute); err == nil {
+		t.Error("NewImplementer() with unknown fighter type should return an error")
+	}
+	if _, err := NewReviewer("skynet", "/tmp", time.Minute); err == nil {
+		t.Error("NewReviewer() with unknown fighter type should return an error")
+	}
+}
+
+func TestDisplayName_UnknownFighter(t *testing.T) {
+	if got := DisplayName("skynet"); got != "SKYNET" {
+		t.Errorf("DisplayName() = %q, want %q", got, "SKYNET")
+	}
+}
@@ -0,0 +1,187 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SnapshotWorkTree records the working tree, untracked files included, as a
+// commit on ref and returns its SHA. Unlike Snapshot it leaves the index
+// alone, so what was staged stays staged.
+func (g *Git) SnapshotWorkTree(ref, message string) (string, error) {
+	if ref == "" {
+		return "", errors.New("snapshot ref cannot be empty")
+	}
+
+	env, cleanup, err := g.tempIndex(true)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	if _, err := g.runGitCommandWith(env, "", "add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := g.runGitCommandWith(env, "", "write-tree")
+	if err != nil {
+		return "", err
+	}
+	return g.commitTree(strings.TrimSpace(tree), ref, message)
+}
+
+// GetStagedDiffFrom returns the diff of the staged changes relative to the
+// given commit instead of HEAD (git diff --staged <commit>).
+func (g *Git) GetStagedDiffFrom(base string) (string, error) {
+	if !g.IsGitRepo() {
+		return "", ErrNotGitRepo
+	}
+	if base == "" {
+		return "", errors.New("base commit cannot be empty")
+	}
+	return g.runGitCommand(append([]string{"diff", "--staged", base}, g.pathspecs()...)...)
+}
+
+// CommitSince commits the changes staged since the base commit on top of HEAD.
+// The changes base already had over HEAD are left out of the commit and stay
+// in the working tree. The paths the commit changed are unstaged; elsewhere
+// the index is reset to the index tree, if given, or else left alone. It returns ErrNoChanges if nothing changed
+// since base, and an error without committing if the changes since base
+// cannot be separated from the ones before it.
+func (g *Git) CommitSince(base, index, message string) error {
+	if message == "" {
+		return errors.New("commit message cannot be empty")
+	}
+	if base == "" {
+		return errors.New("base commit cannot be empty")
+	}
+
+	tree, err := g.runGitCommand("write-tree")
+	if err != nil {
+		return err
+	}
+	diff, err := g.runGitCommand(append([]string{"diff", "--binary", base, strings.TrimSpace(tree)}, g.pathspecs()...)...)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return ErrNoChanges
+	}
+	output, err := g.runGitCommand(append([]string{"diff", "--name-only", "--no-renames", "-z", base, strings.TrimSpace(tree)}, g.pathspecs()...)...)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, path := range strings.Split(strings.TrimRight(output, "\x00"), "\x00") {
+		paths = append(paths, ":(top,literal)"+path)
+	}
+
+	// Apply the changes since base to HEAD in an index of its own
+	env, cleanup, err := g.tempIndex(false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if _, err := g.runGitCommand("rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		if _, err := g.runGitCommandWith(env, "", "read-tree", "HEAD"); err != nil {
+			return err
+		}
+	}
+	if _, err := g.runGitCommandWith(env, diff, "apply", "--cached", "--binary"); err != nil {
+		return fmt.Errorf("changes overlap the uncommitted changes made before: %w", err)
+	}
+	commitTree, err := g.runGitCommandWith(env, "", "write-tree")
+	if err != nil {
+		return err
+	}
+
+	if _, err := g.commitTree(strings.TrimSpace(commitTree), "HEAD", message); err != nil {
+		return err
+	}
+
+	// Put back what was staged before the session, then unstage what was
+	// left out of the commit in the paths it changed
+	if index != "" {
+		if _, err := g.runGitCommand("read-tree", index); err != nil {
+			return fmt.Errorf("failed to restore the staged changes: %w", err)
+		}
+	}
+	_, err = g.runGitCommand(append([]string{"reset", "--quiet", "--"}, paths...)...)
+	return err
+}
+
+// IndexTree records the index as a tree and returns its SHA, so that what is
+// staged can be put back later.
+func (g *Git) IndexTree() (string, error) {
+	output, err := g.runGitCommand("write-tree")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// tempIndex returns the environment pointing git at a temporary index file,
+// a copy of the repository's index if copy is set and empty otherwise, and a
+// function removing it.
+func (g *Git) tempIndex(copy bool) ([]string, func(), error) {
+	dir, err := os.MkdirTemp("", "mortal-prompter-index")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	path := filepath.Join(dir, "index")
+
+	if copy {
+		// Starting from the index saves hashing the files that did not change
+		output, err := g.runGitCommand("rev-parse", "--git-path", "index")
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		index := strings.TrimSpace(output)
+		if !filepath.IsAbs(index) {
+			index = filepath.Join(g.workDir, index)
+		}
+		if data, err := os.ReadFile(index); err == nil {
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+		}
+	}
+
+	return []string{"GIT_INDEX_FILE=" + path}, cleanup, nil
+}
+
+// Stash stashes the uncommitted changes, untracked files included, and
+// returns the SHA of the stash.
+func (g *Git) Stash(message string) (string, error) {
+	if _, err := g.runGitCommand("stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", err
+	}
+	output, err := g.runGitCommand("rev-parse", "--verify", "--quiet", "refs/stash")
+	if err != nil {
+		return "", errors.New("nothing was stashed")
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// RestoreStash applies the stash with the given SHA to the working tree and
+// drops it. If it cannot be applied, the stash is kept.
+func (g *Git) RestoreStash(sha string) error {
+	output, err := g.runGitCommand("stash", "list", "--format=%gd %H")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		name, hash, ok := strings.Cut(line, " ")
+		if ok && hash == sha {
+			_, err := g.runGitCommand("stash", "pop", name)
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrStashNotFound, sha)
+}
@@ -0,0 +1,227 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDirtyRepo returns a repository with a commit and uncommitted work on
+// top of it: a staged change, an unstaged one and an untracked file.
+func newDirtyRepo(t *testing.T) *testRepo {
+	t.Helper()
+	repo := newTestRepo(t)
+	t.Cleanup(repo.cleanup)
+
+	repo.createFile("README.md", "# Test\n")
+	repo.createFile("main.go", "package main\n")
+	repo.createFile("staged.go", "package staged\n")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	repo.createFile("staged.go", "package staged // edited\n")
+	repo.run("add", "staged.go")
+	repo.createFile("README.md", "# Work in progress\n")
+	repo.createFile("notes.txt", "todo")
+	return repo
+}
+
+func TestSnapshotWorkTree(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+	status := repo.run("status", "--porcelain")
+
+	sha, err := g.SnapshotWorkTree("refs/mortal-prompter/s/baseline", "baseline")
+	if err != nil {
+		t.Fatalf("SnapshotWorkTree failed: %v", err)
+	}
+	if resolved, err := g.ResolveRef("refs/mortal-prompter/s/baseline"); err != nil || resolved != sha {
+		t.Errorf("ResolveRef = %s, %v, want %s", resolved, err, sha)
+	}
+
+	// The snapshot has every change, untracked files included
+	files := repo.run("ls-tree", "-r", "--name-only", sha)
+	if !strings.Contains(files, "notes.txt") {
+		t.Errorf("snapshot files = %q, want notes.txt", files)
+	}
+	if readme := repo.run("show", sha+":README.md"); readme != "# Work in progress\n" {
+		t.Errorf("snapshot README.md = %q", readme)
+	}
+
+	// The index and working tree are as they were
+	if got := repo.run("status", "--porcelain"); got != status {
+		t.Errorf("status after snapshot = %q, want %q", got, status)
+	}
+}
+
+func TestGetStagedDiffFrom(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+
+	base, err := g.SnapshotWorkTree("refs/mortal-prompter/s/baseline", "baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.createFile("main.go", "package main\n\nfunc main() {}\n")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := g.GetStagedDiffFrom(base)
+	if err != nil {
+		t.Fatalf("GetStagedDiffFrom failed: %v", err)
+	}
+	if !strings.Contains(diff, "+func main() {}") {
+		t.Errorf("diff = %q, want the change since the baseline", diff)
+	}
+	for _, file := range []string{"README.md", "notes.txt", "staged.go"} {
+		if strings.Contains(diff, file) {
+			t.Errorf("diff = %q, want no changes from before the baseline to %s", diff, file)
+		}
+	}
+
+	if _, err := g.GetStagedDiffFrom(""); err == nil {
+		t.Error("expected error for empty base")
+	}
+}
+
+func TestCommitSince(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+
+	index, err := g.IndexTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := g.SnapshotWorkTree("refs/mortal-prompter/s/baseline", "baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CommitSince(base, index, "nothing yet"); err != ErrNoChanges {
+		t.Errorf("CommitSince without changes = %v, want ErrNoChanges", err)
+	}
+
+	repo.createFile("main.go", "package main\n\nfunc main() {}\n")
+	repo.createFile("feature.go", "package main\n")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CommitSince(base, index, "add feature"); err != nil {
+		t.Fatalf("CommitSince failed: %v", err)
+	}
+
+	committed := repo.run("show", "--name-only", "--format=%s", "HEAD")
+	if !strings.Contains(committed, "add feature") || !strings.Contains(committed, "main.go") ||
+		!strings.Contains(committed, "feature.go") {
+		t.Errorf("commit = %q, want the changes since the baseline", committed)
+	}
+	for _, file := range []string{"README.md", "notes.txt", "staged.go"} {
+		if strings.Contains(committed, file) {
+			t.Errorf("commit = %q, want %s left out", committed, file)
+		}
+	}
+
+	// The work from before the baseline is still there, uncommitted and
+	// staged as it was
+	content, err := os.ReadFile(filepath.Join(repo.dir, "README.md"))
+	if err != nil || string(content) != "# Work in progress\n" {
+		t.Errorf("README.md = %q, %v, want the uncommitted work kept", content, err)
+	}
+	status := repo.run("status", "--porcelain")
+	for _, want := range []string{" M README.md", "M  staged.go", "?? notes.txt"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status = %q, want %q", status, want)
+		}
+	}
+	if strings.Contains(status, "main.go") || strings.Contains(status, "feature.go") {
+		t.Errorf("status = %q, want the committed changes clean", status)
+	}
+}
+
+func TestCommitSince_NoIndex(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+
+	base, err := g.SnapshotWorkTree("refs/mortal-prompter/s/baseline", "baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.createFile("main.go", "package main\n\nfunc main() {}\n")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CommitSince(base, "", "add main"); err != nil {
+		t.Fatalf("CommitSince failed: %v", err)
+	}
+
+	// Only the committed paths are reset, the rest stays staged
+	status := repo.run("status", "--porcelain")
+	if status != "M  README.md\nA  notes.txt\nM  staged.go\n" {
+		t.Errorf("status = %q, want the changes from before the baseline still staged", status)
+	}
+}
+
+func TestCommitSince_Overlap(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+
+	base, err := g.SnapshotWorkTree("refs/mortal-prompter/s/baseline", "baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := repo.run("rev-parse", "HEAD")
+
+	// Editing the line the earlier work changed cannot be committed without it
+	repo.createFile("README.md", "# Done\n")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CommitSince(base, "", "finish README"); err == nil {
+		t.Fatal("expected error for changes overlapping the earlier work")
+	}
+	if got := repo.run("rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s after a failed commit", got)
+	}
+}
+
+func TestStashAndRestore(t *testing.T) {
+	repo := newDirtyRepo(t)
+	g := New(repo.dir)
+
+	sha, err := g.Stash("mortal-prompter: session s")
+	if err != nil {
+		t.Fatalf("Stash failed: %v", err)
+	}
+	if dirty, err := g.HasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("HasUncommittedChanges after stash = %v, %v, want a clean tree", dirty, err)
+	}
+
+	// The session works on the clean tree
+	repo.createFile("feature.go", "package main\n")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RestoreStash(sha); err != nil {
+		t.Fatalf("RestoreStash failed: %v", err)
+	}
+	status := repo.run("status", "--porcelain")
+	for _, want := range []string{"README.md", "staged.go", "notes.txt", "feature.go"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status = %q, want %s", status, want)
+		}
+	}
+	if list := repo.run("stash", "list"); list != "" {
+		t.Errorf("stash list = %q, want the stash dropped", list)
+	}
+
+	if err := g.RestoreStash(sha); err == nil {
+		t.Error("expected error for a stash restored already")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -19,11 +20,18 @@ var (
 
 	// ErrNoChanges is returned when there are no changes to commit.
 	ErrNoChanges = errors.New("no changes to commit")
+
+	// ErrStashNotFound is returned when a stash to restore is no longer in the stash list.
+	ErrStashNotFound = errors.New("stash not found")
 )
 
 // Git provides git operations for a specific working directory.
 type Git struct {
 	workDir string
+
+	// include and exclude are the glob patterns limiting the paths staged and diffed
+	include []string
+	exclude []string
 }
 
 // New creates a new Git instance for the specified working directory.
@@ -33,12 +41,22 @@ func New(workDir string) *Git {
 	}
 }
 
+// SetPathRules limits the paths staged and diffed to the ones matching any of
+// the include patterns, or all of them if there are none, and none of the
+// exclude patterns. Patterns are git glob pathspecs relative to the working
+// directory: "*" matches within a directory and "**" across directories, and
+// a pattern matching a directory matches everything in it.
+func (g *Git) SetPathRules(include, exclude []string) {
+	g.include = append([]string(nil), include...)
+	g.exclude = append([]string(nil), exclude...)
+}
+
 // GetUnstagedDiff returns the diff of unstaged changes (git diff).
 func (g *Git) GetUnstagedDiff() (string, error) {
 	if !g.IsGitRepo() {
 		return "", ErrNotGitRepo
 	}
-	return g.runGitCommand("diff")
+	return g.runGitCommand(append([]string{"diff"}, g.pathspecs()...)...)
 }
 
 // GetStagedDiff returns the diff of staged changes (git diff --staged).
@@ -46,7 +64,7 @@ func (g *Git) GetStagedDiff() (string, error) {
 	if !g.IsGitRepo() {
 		return "", ErrNotGitRepo
 	}
-	return g.runGitCommand("diff", "--staged")
+	return g.runGitCommand(append([]string{"diff", "--staged"}, g.pathspecs()...)...)
 }
 
 // GetAllDiff returns the diff of all uncommitted changes (git diff HEAD).
@@ -54,12 +72,50 @@ func (g *Git) GetAllDiff() (string, error) {
 	if !g.IsGitRepo() {
 		return "", ErrNotGitRepo
 	}
-	return g.runGitCommand("diff", "HEAD")
+	return g.runGitCommand(append([]string{"diff", "HEAD"}, g.pathspecs()...)...)
+}
+
+// GetRangeDiff returns the diff of a revision range, e.g. "main...HEAD" (git diff <range>).
+func (g *Git) GetRangeDiff(revisions string) (string, error) {
+	if !g.IsGitRepo() {
+		return "", ErrNotGitRepo
+	}
+	if revisions == "" {
+		return "", errors.New("revision range cannot be empty")
+	}
+	return g.runGitCommand(append([]string{"diff", revisions}, g.pathspecs()...)...)
+}
+
+// GetCommitDiff returns the changes introduced by a single commit (git show).
+func (g *Git) GetCommitDiff(commit string) (string, error) {
+	if !g.IsGitRepo() {
+		return "", ErrNotGitRepo
+	}
+	if commit == "" {
+		return "", errors.New("commit cannot be empty")
+	}
+	return g.runGitCommand(append([]string{"show", "--format=", "--no-color", commit}, g.pathspecs()...)...)
 }
 
-// StageAll stages all changes including untracked files (git add -A).
+// StageAll stages all changes including untracked files (git add -A),
+// except in the paths the path rules leave out.
 func (g *Git) StageAll() error {
-	_, err := g.runGitCommand("add", "-A")
+	if len(g.include) == 0 && len(g.exclude) == 0 {
+		_, err := g.runGitCommand("add", "-A")
+		return err
+	}
+
+	// git add fails on patterns matching nothing or ignored paths, so the
+	// changed paths the rules keep are added by name
+	paths, err := g.changedPaths(g.pathspecs()...)
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	var list strings.Builder
+	for _, path := range paths {
+		list.WriteString(":(top,literal)" + path + "\x00")
+	}
+	_, err = g.runGitCommandWith(nil, list.String(), "add", "-A", "--pathspec-from-file=-", "--pathspec-file-nul")
 	return err
 }
 
@@ -69,12 +125,12 @@ func (g *Git) Commit(message string) error {
 		return errors.New("commit message cannot be empty")
 	}
 
-	// Check if there are changes to commit
-	hasChanges, err := g.HasUncommittedChanges()
+	// Check if there are changes to commit; only staged changes are committed
+	staged, err := g.runGitCommand("diff", "--staged", "--name-only")
 	if err != nil {
-		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+		return fmt.Errorf("failed to check for staged changes: %w", err)
 	}
-	if !hasChanges {
+	if strings.TrimSpace(staged) == "" {
 		return ErrNoChanges
 	}
 
@@ -93,9 +149,10 @@ func (g *Git) GetCurrentBranch() (string, error) {
 
 // HasUncommittedChanges returns true if there are uncommitted changes in the working directory.
 // This includes both staged and unstaged changes, as well as untracked files.
-func (g *Git) HasUncommittedChanges() (bool, error) {
+// Changes in the excluded paths are ignored.
+func (g *Git) HasUncommittedChanges(exclude ...string) (bool, error) {
 	// git status --porcelain returns empty output if there are no changes
-	output, err := g.runGitCommand("status", "--porcelain")
+	output, err := g.runGitCommand(append([]string{"status", "--porcelain"}, pathspecs(nil, exclude)...)...)
 	if err != nil {
 		return false, err
 	}
@@ -111,6 +168,12 @@ func (g *Git) IsGitRepo() bool {
 // runGitCommand executes a git command with the provided arguments.
 // It sets the working directory and captures both stdout and stderr.
 func (g *Git) runGitCommand(args ...string) (string, error) {
+	return g.runGitCommandWith(nil, "", args...)
+}
+
+// runGitCommandWith is like runGitCommand, adding env to the environment of
+// git and feeding it stdin.
+func (g *Git) runGitCommandWith(env []string, stdin string, args ...string) (string, error) {
 	// Check if git is installed
 	if _, err := exec.LookPath("git"); err != nil {
 		return "", ErrGitNotInstalled
@@ -118,6 +181,12 @@ func (g *Git) runGitCommand(args ...string) (string, error) {
 
 	cmd := exec.Command("git", args...)
 	cmd.Dir = g.workDir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -372,6 +372,58 @@ func TestGetAllDiff(t *testing.T) {
 	}
 }
 
+func TestGetRangeDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Original")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+	repo.createFile("feature.txt", "feature")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "add feature")
+	repo.createFile("README.md", "# Uncommitted")
+
+	g := New(repo.dir)
+	diff, err := g.GetRangeDiff("HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(diff, "feature.txt") {
+		t.Errorf("expected diff to contain feature.txt, got: %s", diff)
+	}
+	if strings.Contains(diff, "Uncommitted") {
+		t.Errorf("expected diff to leave out uncommitted changes, got: %s", diff)
+	}
+
+	if _, err := g.GetRangeDiff(""); err == nil {
+		t.Error("expected error for an empty range")
+	}
+}
+
+func TestGetCommitDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	// The root commit has no parent to diff against
+	repo.createFile("README.md", "# Original")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+	repo.createFile("README.md", "# Uncommitted")
+
+	g := New(repo.dir)
+	diff, err := g.GetCommitDiff("HEAD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(diff, "+# Original") {
+		t.Errorf("expected diff to contain the commit's changes, got: %s", diff)
+	}
+	if strings.Contains(diff, "Uncommitted") {
+		t.Errorf("expected diff to leave out uncommitted changes, got: %s", diff)
+	}
+}
+
 func TestStageAll(t *testing.T) {
 	repo := newTestRepo(t)
 	defer repo.cleanup()
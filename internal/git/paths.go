@@ -0,0 +1,133 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pathspecs returns the pathspec arguments of the path rules, starting with
+// the "--" separating them from revisions.
+func (g *Git) pathspecs() []string {
+	if specs := pathspecs(g.include, g.exclude); specs != nil {
+		return specs
+	}
+	return []string{"--"}
+}
+
+// pathspecs returns the arguments limiting a git command to the paths
+// matching any of the include glob patterns, or all paths if there are none,
+// and none of the exclude ones. It returns nil if there are no patterns.
+func pathspecs(include, exclude []string) []string {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	args := []string{"--"}
+	for _, pattern := range include {
+		args = append(args, ":(glob)"+pattern)
+	}
+	for _, pattern := range exclude {
+		args = append(args, ":(exclude,glob)"+pattern)
+	}
+	return args
+}
+
+// ExcludedChanges returns the paths with uncommitted changes, untracked files
+// included, that the path rules leave out of staging and diffing, sorted.
+// Paths are relative to the root of the repository.
+func (g *Git) ExcludedChanges() ([]string, error) {
+	if len(g.include) == 0 && len(g.exclude) == 0 {
+		return nil, nil
+	}
+
+	all, err := g.changedPaths()
+	if err != nil {
+		return nil, err
+	}
+	kept, err := g.changedPaths(g.pathspecs()...)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(kept))
+	for _, path := range kept {
+		keep[path] = true
+	}
+	var excluded []string
+	for _, path := range all {
+		if !keep[path] {
+			excluded = append(excluded, path)
+		}
+	}
+	sort.Strings(excluded)
+	return excluded, nil
+}
+
+// changedPaths returns the paths with uncommitted changes, limited to the
+// given pathspec arguments.
+func (g *Git) changedPaths(pathspecs ...string) ([]string, error) {
+	args := append([]string{"status", "--porcelain", "-z", "--untracked-files=all"}, pathspecs...)
+	output, err := g.runGitCommand(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		// A rename or copy is followed by the path it came from
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	return paths, nil
+}
+
+// ExcludeLocally adds path, relative to the working directory, to the
+// repository's info/exclude file, which ignores untracked files like
+// .gitignore does without being committed.
+func (g *Git) ExcludeLocally(path string) error {
+	prefix, err := g.runGitCommand("rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	pattern := "/" + strings.TrimSpace(prefix) + filepath.ToSlash(path)
+
+	commonDir, err := g.CommonDir()
+	if err != nil {
+		return err
+	}
+	path = filepath.Join(commonDir, "info", "exclude")
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(pattern + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathRules(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	repo.createFile("main.go", "package main")
+	repo.createFile("web/package-lock.json", "{}")
+	repo.createFile("vendor/lib/lib.go", "package lib")
+	repo.createFile("docs/guide.md", "# Guide")
+
+	g := New(repo.dir)
+	g.SetPathRules([]string{"**/*.go", "web/**"}, []string{"vendor", "**/package-lock.json"})
+
+	if err := g.StageAll(); err != nil {
+		t.Fatalf("StageAll failed: %v", err)
+	}
+	staged := repo.run("diff", "--staged", "--name-only")
+	if strings.TrimSpace(staged) != "main.go" {
+		t.Errorf("staged files = %q, want main.go", staged)
+	}
+
+	// Diffs leave the excluded paths out even if they were staged
+	repo.run("add", "vendor")
+	diff, err := g.GetStagedDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "main.go") || strings.Contains(diff, "vendor") {
+		t.Errorf("diff = %q, want only main.go", diff)
+	}
+
+	excluded, err := g.ExcludedChanges()
+	if err != nil {
+		t.Fatalf("ExcludedChanges failed: %v", err)
+	}
+	want := []string{"docs/guide.md", "vendor/lib/lib.go", "web/package-lock.json"}
+	if !reflect.DeepEqual(excluded, want) {
+		t.Errorf("ExcludedChanges = %v, want %v", excluded, want)
+	}
+}
+
+func TestPathRules_None(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("main.go", "package main")
+	g := New(repo.dir)
+
+	excluded, err := g.ExcludedChanges()
+	if err != nil || excluded != nil {
+		t.Errorf("ExcludedChanges = %v, %v, want nothing without rules", excluded, err)
+	}
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if staged := repo.run("diff", "--staged", "--name-only"); strings.TrimSpace(staged) != "main.go" {
+		t.Errorf("staged files = %q, want main.go", staged)
+	}
+}
+
+func TestCommit_OnlyExcludedChanges(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	repo.createFile("go.sum", "checksums")
+	g := New(repo.dir)
+	g.SetPathRules(nil, []string{"go.sum"})
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Commit("update checksums"); err != ErrNoChanges {
+		t.Errorf("Commit = %v, want ErrNoChanges", err)
+	}
+}
+
+func TestExcludeLocally(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile(".mortal-prompter/session.log", "log")
+	g := New(repo.dir)
+
+	for i := 0; i < 2; i++ {
+		if err := g.ExcludeLocally(".mortal-prompter"); err != nil {
+			t.Fatalf("ExcludeLocally failed: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(repo.dir, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(content), "/.mortal-prompter\n"); n != 1 {
+		t.Errorf("info/exclude has the pattern %d times, want once:\n%s", n, content)
+	}
+	if dirty, err := g.HasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("HasUncommittedChanges = %v, %v, want the output directory ignored", dirty, err)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"errors"
+	"strings"
+)
+
+// Snapshot records the staged tree as a commit on ref and returns its SHA.
+// HEAD, the index and the working tree are left untouched.
+func (g *Git) Snapshot(ref, message string) (string, error) {
+	if ref == "" {
+		return "", errors.New("snapshot ref cannot be empty")
+	}
+
+	tree, err := g.runGitCommand("write-tree")
+	if err != nil {
+		return "", err
+	}
+	return g.commitTree(strings.TrimSpace(tree), ref, message)
+}
+
+// commitTree records tree as a commit on top of HEAD, if there is one, on ref
+// and returns its SHA.
+func (g *Git) commitTree(tree, ref, message string) (string, error) {
+	args := []string{"commit-tree", tree, "-m", message}
+	if head, err := g.runGitCommand("rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		args = append(args, "-p", strings.TrimSpace(head))
+	}
+
+	output, err := g.runGitCommand(args...)
+	if err != nil {
+		return "", err
+	}
+	sha := strings.TrimSpace(output)
+
+	if _, err := g.runGitCommand("update-ref", ref, sha); err != nil {
+		return "", err
+	}
+
+	return sha, nil
+}
+
+// ResolveRef returns the SHA the given ref points to.
+func (g *Git) ResolveRef(ref string) (string, error) {
+	output, err := g.runGitCommand("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// RestoreSnapshot resets the index and working tree to the tree of the given
+// snapshot commit. Tracked files added after the snapshot are removed; HEAD is
+// left untouched.
+func (g *Git) RestoreSnapshot(sha string) error {
+	_, err := g.runGitCommand("read-tree", "-u", "--reset", sha)
+	return err
+}
+
+// RestorePaths resets the given paths, relative to the root of the repository,
+// in the index and working tree to their content in the given commit. Paths
+// the commit does not have are removed.
+func (g *Git) RestorePaths(commit string, paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := []string{"restore", "--source=" + commit, "--staged", "--worktree", "--"}
+	for _, path := range paths {
+		args = append(args, ":(top,literal)"+path)
+	}
+	_, err := g.runGitCommand(args...)
+	return err
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotAndRestore(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+	head := strings.TrimSpace(repo.run("rev-parse", "HEAD"))
+
+	g := New(repo.dir)
+
+	// Round 1: modify README
+	repo.createFile("README.md", "# Round 1")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	sha1, err := g.Snapshot("refs/mortal-prompter/s/round-1", "round 1")
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	// Round 2: add a new file and modify README again
+	repo.createFile("README.md", "# Round 2")
+	repo.createFile("extra.go", "package extra")
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Snapshot("refs/mortal-prompter/s/round-2", "round 2"); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	// Snapshots do not move HEAD
+	if got := strings.TrimSpace(repo.run("rev-parse", "HEAD")); got != head {
+		t.Errorf("Snapshot moved HEAD from %s to %s", head, got)
+	}
+
+	resolved, err := g.ResolveRef("refs/mortal-prompter/s/round-1")
+	if err != nil {
+		t.Fatalf("ResolveRef failed: %v", err)
+	}
+	if resolved != sha1 {
+		t.Errorf("ResolveRef = %s, want %s", resolved, sha1)
+	}
+
+	if err := g.RestoreSnapshot(sha1); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(repo.dir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "# Round 1" {
+		t.Errorf("README.md = %q, want %q", content, "# Round 1")
+	}
+	if _, err := os.Stat(filepath.Join(repo.dir, "extra.go")); !os.IsNotExist(err) {
+		t.Error("files added after the snapshot should be removed")
+	}
+
+	// The restored state is staged, ready for the next round's diff
+	staged, _ := g.GetStagedDiff()
+	if !strings.Contains(staged, "+# Round 1") {
+		t.Errorf("restored changes should be staged, got diff: %s", staged)
+	}
+}
+
+func TestRestorePaths(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.createFile("migrations/001.sql", "CREATE TABLE t;")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	repo.createFile("README.md", "# Changed")
+	repo.createFile("migrations/001.sql", "DROP TABLE t;")
+	repo.createFile("migrations/002.sql", "DROP TABLE u;")
+	g := New(repo.dir)
+	if err := g.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RestorePaths("HEAD", "migrations/001.sql", "migrations/002.sql"); err != nil {
+		t.Fatalf("RestorePaths failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(repo.dir, "migrations", "001.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "CREATE TABLE t;" {
+		t.Errorf("001.sql = %q, want the committed content", content)
+	}
+	if _, err := os.Stat(filepath.Join(repo.dir, "migrations", "002.sql")); !os.IsNotExist(err) {
+		t.Error("files the commit does not have should be removed")
+	}
+	if staged := repo.run("diff", "--staged", "--name-only"); strings.TrimSpace(staged) != "README.md" {
+		t.Errorf("staged files = %q, want only README.md", staged)
+	}
+}
+
+func TestSnapshot_EmptyRef(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	if _, err := New(repo.dir).Snapshot("", "message"); err == nil {
+		t.Error("expected error for empty ref")
+	}
+}
+
+func TestResolveRef_Missing(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	if _, err := New(repo.dir).ResolveRef("refs/mortal-prompter/missing"); err == nil {
+		t.Error("expected error for missing ref")
+	}
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CommonDir returns the absolute path of the git directory shared by all
+// worktrees of the repository.
+func (g *Git) CommonDir() (string, error) {
+	output, err := g.runGitCommand("rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+
+	dir := strings.TrimSpace(output)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(g.workDir, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// BranchExists returns true if a local branch with the given name exists.
+func (g *Git) BranchExists(branch string) bool {
+	_, err := g.runGitCommand("rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// AddWorktree creates a worktree at path with branch checked out.
+// If the branch does not exist yet it is created from HEAD.
+func (g *Git) AddWorktree(path, branch string) error {
+	if g.BranchExists(branch) {
+		_, err := g.runGitCommand("worktree", "add", path, branch)
+		return err
+	}
+	_, err := g.runGitCommand("worktree", "add", "-b", branch, path, "HEAD")
+	return err
+}
+
+// RemoveWorktree removes the worktree at path, discarding any changes left in it.
+func (g *Git) RemoveWorktree(path string) error {
+	_, err := g.runGitCommand("worktree", "remove", "--force", path)
+	return err
+}
+
+// DeleteBranch deletes a local branch, even if it has not been merged.
+func (g *Git) DeleteBranch(branch string) error {
+	_, err := g.runGitCommand("branch", "-D", branch)
+	return err
+}
+
+// Merge merges branch into the current branch.
+// If the merge fails the repository is restored to its state before the merge.
+func (g *Git) Merge(branch string) error {
+	if _, err := g.runGitCommand("merge", "--no-edit", branch); err != nil {
+		// Leave the working tree as it was; the abort fails harmlessly if no merge started
+		_, _ = g.runGitCommand("merge", "--abort")
+		return fmt.Errorf("failed to merge %s: %w", branch, err)
+	}
+	return nil
+}
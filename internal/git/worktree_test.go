@@ -0,0 +1,139 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommonDir(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("sub/file.txt", "content")
+
+	dir, err := New(filepath.Join(repo.dir, "sub")).CommonDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, _ := filepath.EvalSymlinks(filepath.Join(repo.dir, ".git"))
+	got, _ := filepath.EvalSymlinks(dir)
+	if got != want {
+		t.Errorf("expected common dir %q, got %q", want, got)
+	}
+}
+
+func TestWorktreeLifecycle(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	g := New(repo.dir)
+	path := filepath.Join(repo.dir, ".git", "mortal-prompter", "worktrees", "session-1")
+	branch := "mortal-prompter/session-1"
+
+	if g.BranchExists(branch) {
+		t.Fatal("branch should not exist before AddWorktree")
+	}
+	if err := g.AddWorktree(path, branch); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if !g.BranchExists(branch) {
+		t.Error("AddWorktree should create the branch")
+	}
+	if _, err := os.Stat(filepath.Join(path, "README.md")); err != nil {
+		t.Errorf("worktree should contain the checked out files: %v", err)
+	}
+
+	// Changes in the worktree do not touch the main working tree
+	if err := os.WriteFile(filepath.Join(path, "feature.txt"), []byte("feature"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt := New(path)
+	if err := wt.StageAll(); err != nil {
+		t.Fatalf("StageAll in worktree failed: %v", err)
+	}
+	if err := wt.Commit("add feature"); err != nil {
+		t.Fatalf("Commit in worktree failed: %v", err)
+	}
+	if hasChanges, _ := g.HasUncommittedChanges(); hasChanges {
+		t.Error("main working tree should be untouched by worktree changes")
+	}
+
+	if err := g.Merge(branch); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.dir, "feature.txt")); err != nil {
+		t.Errorf("merge should bring the worktree changes into the main tree: %v", err)
+	}
+
+	if err := g.RemoveWorktree(path); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("RemoveWorktree should delete the worktree directory")
+	}
+	if err := g.DeleteBranch(branch); err != nil {
+		t.Fatalf("DeleteBranch failed: %v", err)
+	}
+	if g.BranchExists(branch) {
+		t.Error("DeleteBranch should delete the branch")
+	}
+}
+
+func TestAddWorktree_ExistingBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+	repo.run("branch", "mortal-prompter/existing")
+
+	g := New(repo.dir)
+	path := filepath.Join(t.TempDir(), "existing")
+	if err := g.AddWorktree(path, "mortal-prompter/existing"); err != nil {
+		t.Fatalf("AddWorktree with an existing branch failed: %v", err)
+	}
+
+	branch, err := New(path).GetCurrentBranch()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "mortal-prompter/existing" {
+		t.Errorf("expected worktree on mortal-prompter/existing, got %q", branch)
+	}
+}
+
+func TestMerge_Conflict(t *testing.T) {
+	repo := newTestRepo(t)
+	defer repo.cleanup()
+
+	repo.createFile("README.md", "# Test")
+	repo.run("add", "-A")
+	repo.run("commit", "-m", "initial commit")
+
+	repo.run("checkout", "-b", "feature")
+	repo.createFile("README.md", "# Feature")
+	repo.run("commit", "-am", "feature change")
+	repo.run("checkout", "-")
+	repo.createFile("README.md", "# Main")
+	repo.run("commit", "-am", "main change")
+
+	g := New(repo.dir)
+	err := g.Merge("feature")
+	if err == nil {
+		t.Fatal("expected merge conflict error")
+	}
+	if !strings.Contains(err.Error(), "feature") {
+		t.Errorf("error should mention the branch, got: %v", err)
+	}
+	if hasChanges, _ := g.HasUncommittedChanges(); hasChanges {
+		t.Error("a failed merge should be aborted")
+	}
+}
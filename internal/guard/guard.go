@@ -0,0 +1,332 @@
+// Package guard checks the changes of each implementer run against the
+// project's guardrails: paths the implementer must not touch and limits on
+// the size of its changes.
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Actions taken on the changes that break the guardrails.
+const (
+	// ActionRevert reverts the offending changes and hands the violations to
+	// the implementer as issues
+	ActionRevert = "revert"
+
+	// ActionAsk asks the user whether to keep the offending changes
+	ActionAsk = "ask"
+)
+
+// Rules reported by a violation.
+const (
+	RuleProtectedPath   = "protected_path"
+	RuleMaxFilesChanged = "max_files_changed"
+	RuleMaxLinesDeleted = "max_lines_deleted"
+	RuleMaxDiffBytes    = "max_diff_bytes"
+)
+
+// Rules are the guardrails of a project. Limits of zero are not checked.
+type Rules struct {
+	// Protected are the glob patterns of the paths the implementer must not
+	// change, relative to the root of the repository. "**" matches any number
+	// of directories, and a pattern matching a directory protects everything
+	// under it
+	Protected []string `json:"protected,omitempty"`
+
+	// MaxFilesChanged is the most files a single implementer run may change
+	MaxFilesChanged int `json:"max_files_changed,omitempty"`
+
+	// MaxLinesDeleted is the most lines a single implementer run may delete
+	MaxLinesDeleted int `json:"max_lines_deleted,omitempty"`
+
+	// MaxDiffBytes is the largest diff a single implementer run may produce
+	MaxDiffBytes int `json:"max_diff_bytes,omitempty"`
+
+	// OnViolation is what to do with changes that break the guardrails,
+	// ActionRevert (the default) or ActionAsk
+	OnViolation string `json:"on_violation,omitempty"`
+}
+
+// Enabled reports whether there is any guardrail to check.
+func (r Rules) Enabled() bool {
+	return len(r.Protected) > 0 || r.MaxFilesChanged > 0 || r.MaxLinesDeleted > 0 || r.MaxDiffBytes > 0
+}
+
+// Action returns what to do with changes that break the guardrails.
+func (r Rules) Action() string {
+	if r.OnViolation == "" {
+		return ActionRevert
+	}
+	return r.OnViolation
+}
+
+// Validate checks the patterns, limits and action of the rules.
+func (r Rules) Validate() error {
+	for _, pattern := range r.Protected {
+		if strings.TrimSpace(pattern) == "" {
+			return errors.New("empty protected path pattern")
+		}
+		if strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "..") {
+			return fmt.Errorf("protected path pattern %q is not relative to the repository", pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid protected path pattern %q: %w", pattern, err)
+		}
+	}
+	if r.MaxFilesChanged < 0 || r.MaxLinesDeleted < 0 || r.MaxDiffBytes < 0 {
+		return errors.New("guardrail limits cannot be negative")
+	}
+	switch r.OnViolation {
+	case "", ActionRevert, ActionAsk:
+		return nil
+	default:
+		return fmt.Errorf("invalid guardrail action %q (must be %s or %s)", r.OnViolation, ActionRevert, ActionAsk)
+	}
+}
+
+// Check returns the guardrails the changes in diff break, protected paths
+// first. Diff is a git diff of a single implementer run.
+func (r Rules) Check(diff string) []types.Violation {
+	stats := parseDiff(diff)
+
+	var violations []types.Violation
+	for _, file := range stats.paths {
+		if pattern, ok := r.protects(file); ok {
+			violations = append(violations, types.Violation{
+				Rule:    RuleProtectedPath,
+				Path:    file,
+				Message: fmt.Sprintf("`%s` is protected (matches `%s`) and must not be changed", file, pattern),
+			})
+		}
+	}
+
+	if r.MaxFilesChanged > 0 && len(stats.paths) > r.MaxFilesChanged {
+		violations = append(violations, types.Violation{
+			Rule:    RuleMaxFilesChanged,
+			Message: fmt.Sprintf("%d files were changed, more than the limit of %d", len(stats.paths), r.MaxFilesChanged),
+		})
+	}
+	if r.MaxLinesDeleted > 0 && stats.deleted > r.MaxLinesDeleted {
+		violations = append(violations, types.Violation{
+			Rule:    RuleMaxLinesDeleted,
+			Message: fmt.Sprintf("%d lines were deleted, more than the limit of %d", stats.deleted, r.MaxLinesDeleted),
+		})
+	}
+	if r.MaxDiffBytes > 0 && len(diff) > r.MaxDiffBytes {
+		violations = append(violations, types.Violation{
+			Rule:    RuleMaxDiffBytes,
+			Message: fmt.Sprintf("the diff is %d bytes, more than the limit of %d", len(diff), r.MaxDiffBytes),
+		})
+	}
+	return violations
+}
+
+// protects returns the first protected pattern matching file.
+func (r Rules) protects(file string) (string, bool) {
+	for _, pattern := range r.Protected {
+		if Match(pattern, file) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Match reports whether the slash-separated path matches the glob pattern,
+// or is under a directory matching it. A "**" element matches any number of
+// directories; the other elements follow path.Match.
+func Match(pattern, name string) bool {
+	return matchElems(splitPath(pattern), splitPath(name))
+}
+
+// matchElems matches the path elements of a name against those of a pattern.
+func matchElems(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		// Everything under a matching directory matches
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchElems(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchElems(pattern[1:], name[1:])
+}
+
+// splitPath splits a slash-separated path into its elements, dropping empty
+// and "." ones.
+func splitPath(p string) []string {
+	var elems []string
+	for _, elem := range strings.Split(p, "/") {
+		if elem != "" && elem != "." {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// Protected returns the paths of the violations of protected paths.
+func Protected(violations []types.Violation) []string {
+	var paths []string
+	for _, v := range violations {
+		if v.Rule == RuleProtectedPath {
+			paths = append(paths, v.Path)
+		}
+	}
+	return paths
+}
+
+// Oversized reports whether any of the violations is of a size limit, which
+// the whole change breaks rather than some of its files.
+func Oversized(violations []types.Violation) bool {
+	for _, v := range violations {
+		if v.Rule != RuleProtectedPath {
+			return true
+		}
+	}
+	return false
+}
+
+// Reverted reports whether the offending changes of the violations were reverted.
+func Reverted(violations []types.Violation) bool {
+	for _, v := range violations {
+		if v.Reverted {
+			return true
+		}
+	}
+	return false
+}
+
+// Issues turns violations into issues for the implementer.
+func Issues(violations []types.Violation) []string {
+	var issues []string
+	for _, v := range violations {
+		issue := "Guardrail broken: " + v.Message
+		if v.Reverted {
+			issue += "; the changes were reverted"
+			if v.Rule != RuleProtectedPath {
+				issue += ", make a smaller change"
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// Findings returns the violations as critical issues, which no fail-on
+// threshold lets through.
+func Findings(violations []types.Violation) []types.Issue {
+	var findings []types.Issue
+	for i, issue := range Issues(violations) {
+		findings = append(findings, types.Issue{
+			ID:          types.IssueID(violations[i].Path, issue),
+			Severity:    types.SeverityCritical,
+			Category:    "guardrail",
+			File:        violations[i].Path,
+			Description: issue,
+		})
+	}
+	return findings
+}
+
+// diffStats are the figures of a diff the guardrails are checked against.
+type diffStats struct {
+	// paths are the paths changed, both sides of renames included
+	paths []string
+
+	// deleted is the number of lines deleted
+	deleted int
+}
+
+// parseDiff collects the changed paths and deleted lines of a git diff.
+func parseDiff(diff string) diffStats {
+	var stats diffStats
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			stats.paths = append(stats.paths, p)
+		}
+	}
+
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHunk = false
+			from, to := headerPaths(strings.TrimPrefix(line, "diff --git "))
+			add(from)
+			add(to)
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "-"):
+			stats.deleted++
+		}
+	}
+	return stats
+}
+
+// headerPaths returns the two paths of the "a/<from> b/<to>" part of a diff
+// header, unquoting them if git quoted them.
+func headerPaths(header string) (string, string) {
+	if strings.HasPrefix(header, `"`) {
+		from, rest, ok := cutQuoted(header)
+		if !ok {
+			return "", ""
+		}
+		to := strings.TrimSpace(rest)
+		if strings.HasPrefix(to, `"`) {
+			to, _, _ = cutQuoted(to)
+		}
+		return strings.TrimPrefix(from, "a/"), strings.TrimPrefix(to, "b/")
+	}
+	if strings.HasSuffix(header, `"`) {
+		i := strings.Index(header, ` "`)
+		if i < 0 {
+			return "", ""
+		}
+		to, _, _ := cutQuoted(header[i+1:])
+		return strings.TrimPrefix(header[:i], "a/"), strings.TrimPrefix(to, "b/")
+	}
+
+	// Without renames both paths are the same, which finds the split even
+	// when the path has " b/" in it
+	if n := len(header); n%2 == 1 {
+		from, to := header[:n/2], header[n/2+1:]
+		if strings.HasPrefix(from, "a/") && strings.HasPrefix(to, "b/") && from[2:] == to[2:] {
+			return from[2:], to[2:]
+		}
+	}
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return strings.TrimPrefix(header[:i], "a/"), header[i+3:]
+	}
+	return "", ""
+}
+
+// cutQuoted unquotes the C-style quoted string s starts with and returns the
+// rest of s after it.
+func cutQuoted(s string) (string, string, bool) {
+	prefix, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", "", false
+	}
+	unquoted, err := strconv.Unquote(prefix)
+	if err != nil {
+		return "", "", false
+	}
+	return unquoted, s[len(prefix):], true
+}
@@ -0,0 +1,182 @@
+package guard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,3 @@
+ package main
+--- a decremented comment
+-func old() {}
++func new() {}
+diff --git a/migrations/001.sql b/migrations/001.sql
+deleted file mode 100644
+index 3333333..0000000
+--- a/migrations/001.sql
++++ /dev/null
+@@ -1 +0,0 @@
+-CREATE TABLE t;
+diff --git a/docs/old.md b/docs/new.md
+similarity index 100%
+rename from docs/old.md
+rename to docs/new.md
+`
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"migrations", "migrations/001.sql", true},
+		{"migrations/", "migrations/001.sql", true},
+		{"migrations", "db/migrations/001.sql", false},
+		{"**/migrations", "db/migrations/001.sql", true},
+		{".github/**", ".github/workflows/ci.yml", true},
+		{"**/*.pem", "certs/server.pem", true},
+		{"**/*.pem", "server.pem", true},
+		{"*.pem", "certs/server.pem", false},
+		{".env*", ".env.local", true},
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "src/pkg/main.go", false},
+		{"secrets.yml", "config/secrets.yml", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules := Rules{
+		Protected:       []string{"migrations", "docs/new.md"},
+		MaxFilesChanged: 3,
+		MaxLinesDeleted: 2,
+		MaxDiffBytes:    len(testDiff) - 1,
+	}
+
+	violations := rules.Check(testDiff)
+
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.Rule+":"+v.Path)
+	}
+	want := []string{
+		"protected_path:migrations/001.sql",
+		"protected_path:docs/new.md",
+		"max_files_changed:",
+		"max_lines_deleted:",
+		"max_diff_bytes:",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+	if msg := violations[2].Message; !strings.Contains(msg, "4 files") {
+		t.Errorf("max files message = %q, want it to count both sides of the rename", msg)
+	}
+	if msg := violations[3].Message; !strings.Contains(msg, "3 lines") {
+		t.Errorf("max lines message = %q, want 3 deleted lines", msg)
+	}
+}
+
+func TestCheck_WithinLimits(t *testing.T) {
+	rules := Rules{Protected: []string{".github/**"}, MaxFilesChanged: 4, MaxLinesDeleted: 3, MaxDiffBytes: len(testDiff)}
+	if violations := rules.Check(testDiff); len(violations) != 0 {
+		t.Errorf("violations = %+v, want none", violations)
+	}
+	if violations := (Rules{}).Check(testDiff); len(violations) != 0 {
+		t.Errorf("violations = %+v, want none without rules", violations)
+	}
+}
+
+func TestHeaderPaths(t *testing.T) {
+	tests := []struct {
+		header, from, to string
+	}{
+		{"a/main.go b/main.go", "main.go", "main.go"},
+		{"a/dir b/x.go b/dir b/x.go", "dir b/x.go", "dir b/x.go"},
+		{"a/old.go b/new.go", "old.go", "new.go"},
+		{`"a/caf\303\251.txt" "b/caf\303\251.txt"`, "café.txt", "café.txt"},
+		{`a/plain.txt "b/tab\there.txt"`, "plain.txt", "tab\there.txt"},
+	}
+	for _, tt := range tests {
+		from, to := headerPaths(tt.header)
+		if from != tt.from || to != tt.to {
+			t.Errorf("headerPaths(%q) = %q, %q, want %q, %q", tt.header, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		want  string
+	}{
+		{"valid", Rules{Protected: []string{"migrations/**"}, MaxDiffBytes: 1000, OnViolation: ActionAsk}, ""},
+		{"empty pattern", Rules{Protected: []string{" "}}, "empty"},
+		{"parent pattern", Rules{Protected: []string{"../secrets"}}, "not relative"},
+		{"bad pattern", Rules{Protected: []string{"[a-"}}, "invalid protected path pattern"},
+		{"negative limit", Rules{MaxLinesDeleted: -5}, "negative"},
+		{"unknown action", Rules{OnViolation: "warn"}, "invalid guardrail action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuesAndFindings(t *testing.T) {
+	violations := []types.Violation{
+		{Rule: RuleProtectedPath, Path: "migrations/001.sql", Message: "`migrations/001.sql` is protected", Reverted: true},
+		{Rule: RuleMaxFilesChanged, Message: "40 files were changed", Reverted: true},
+		{Rule: RuleMaxDiffBytes, Message: "the diff is too big"},
+	}
+
+	issues := Issues(violations)
+	if len(issues) != 3 {
+		t.Fatalf("Issues() = %q, want 3 issues", issues)
+	}
+	if !strings.HasSuffix(issues[0], "the changes were reverted") {
+		t.Errorf("issue = %q, want it to say the change was reverted", issues[0])
+	}
+	if !strings.Contains(issues[1], "make a smaller change") {
+		t.Errorf("issue = %q, want it to ask for a smaller change", issues[1])
+	}
+	if strings.Contains(issues[2], "reverted") {
+		t.Errorf("issue = %q, kept changes were not reverted", issues[2])
+	}
+
+	findings := Findings(violations)
+	if findings[0].Severity != types.SeverityCritical || findings[0].Category != "guardrail" || findings[0].File != "migrations/001.sql" {
+		t.Errorf("finding = %+v", findings[0])
+	}
+
+	if got := Protected(violations); !reflect.DeepEqual(got, []string{"migrations/001.sql"}) {
+		t.Errorf("Protected() = %v", got)
+	}
+	if !Oversized(violations) || Oversized(violations[:1]) {
+		t.Error("Oversized() should only report size limits")
+	}
+	if !Reverted(violations) || Reverted(violations[2:]) {
+		t.Error("Reverted() should report reverted violations")
+	}
+}
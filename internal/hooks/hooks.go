@@ -0,0 +1,361 @@
+// Package hooks runs the user-defined commands configured for fixed points of
+// a session, such as before each implementer run or after each review.
+//
+// A hook gets the session and round data as JSON on stdin and in environment
+// variables. Exiting with a non-zero code vetoes the step it runs at, and
+// every non-blank line it writes to stdout is an issue for the implementer.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Point is a point of the session at which hooks run.
+type Point string
+
+const (
+	// BeforeSession runs once before the first round; a veto aborts the session
+	BeforeSession Point = "before_session"
+
+	// BeforeImplementer runs before every implementer run; a veto aborts the session
+	BeforeImplementer Point = "before_implementer"
+
+	// AfterImplementer runs after every implementer run; a veto rejects the changes
+	AfterImplementer Point = "after_implementer"
+
+	// AfterDiff runs once the diff of the round is captured; a veto rejects the changes
+	AfterDiff Point = "after_diff"
+
+	// AfterReview runs after every review; a veto fails the round
+	AfterReview Point = "after_review"
+
+	// OnSuccess runs when the changes are approved; a veto skips the commit
+	OnSuccess Point = "on_success"
+
+	// OnAbort runs when the session ends without success; it cannot veto anything
+	OnAbort Point = "on_abort"
+)
+
+// Points lists the hook points in the order they are reached.
+var Points = []Point{BeforeSession, BeforeImplementer, AfterImplementer, AfterDiff, AfterReview, OnSuccess, OnAbort}
+
+// ParsePoint converts a hook point name to a Point.
+func ParsePoint(s string) (Point, error) {
+	for _, p := range Points {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	names := make([]string, len(Points))
+	for i, p := range Points {
+		names[i] = string(p)
+	}
+	return "", fmt.Errorf("unknown hook: %s (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// DefaultTimeout is the default time limit for a single hook.
+const DefaultTimeout = 5 * time.Minute
+
+// maxOutputSize is the maximum number of bytes of stderr kept per result.
+const maxOutputSize = 16 * 1024
+
+// maxIssueLines is the number of stderr lines included in the issue of a veto.
+const maxIssueLines = 20
+
+// Payload is the session and round data a hook gets on stdin.
+type Payload struct {
+	Hook        Point  `json:"hook"`
+	SessionID   string `json:"session_id"`
+	WorkDir     string `json:"work_dir"`
+	Prompt      string `json:"prompt"`
+	Implementer string `json:"implementer"`
+	Reviewer    string `json:"reviewer"`
+
+	// Round is the current round, nil for the hooks run outside the rounds
+	Round *Round `json:"round,omitempty"`
+
+	// Session is the outcome of the session, set for on_success and on_abort
+	Session *Session `json:"session,omitempty"`
+}
+
+// Round is the data of the round a hook runs in, as far as it got.
+type Round struct {
+	Number            int      `json:"number"`
+	ImplementerPrompt string   `json:"implementer_prompt,omitempty"`
+	ImplementerOutput string   `json:"implementer_output,omitempty"`
+	Diff              string   `json:"diff,omitempty"`
+	ReviewerOutput    string   `json:"reviewer_output,omitempty"`
+	HasIssues         bool     `json:"has_issues"`
+	Issues            []string `json:"issues,omitempty"`
+}
+
+// Session is the outcome of the session.
+type Session struct {
+	Success       bool     `json:"success"`
+	State         string   `json:"state"`
+	StopReason    string   `json:"stop_reason,omitempty"`
+	Rounds        int      `json:"rounds"`
+	Branch        string   `json:"branch,omitempty"`
+	FilesModified []string `json:"files_modified,omitempty"`
+}
+
+// RoundData returns the payload data of round.
+func RoundData(round *types.Round) *Round {
+	if round == nil {
+		return nil
+	}
+	return &Round{
+		Number:            round.Number,
+		ImplementerPrompt: round.ImplementerPrompt,
+		ImplementerOutput: round.ImplementerOutput,
+		Diff:              round.GitDiff,
+		ReviewerOutput:    round.ReviewerOutput,
+		HasIssues:         round.HasIssues,
+		Issues:            round.Issues,
+	}
+}
+
+// SessionData returns the payload data of result.
+func SessionData(result *types.SessionResult) *Session {
+	if result == nil {
+		return nil
+	}
+	return &Session{
+		Success:       result.Success,
+		State:         string(result.State),
+		StopReason:    result.StopReason,
+		Rounds:        result.TotalRounds,
+		Branch:        result.Branch,
+		FilesModified: result.FilesModified,
+	}
+}
+
+// env returns the environment variables describing p, for hooks that do not read stdin.
+func (p Payload) env() []string {
+	env := []string{
+		"MORTAL_PROMPTER_HOOK=" + string(p.Hook),
+		"MORTAL_PROMPTER_SESSION_ID=" + p.SessionID,
+		"MORTAL_PROMPTER_WORK_DIR=" + p.WorkDir,
+		"MORTAL_PROMPTER_IMPLEMENTER=" + p.Implementer,
+		"MORTAL_PROMPTER_REVIEWER=" + p.Reviewer,
+	}
+	if p.Round != nil {
+		env = append(env,
+			"MORTAL_PROMPTER_ROUND="+strconv.Itoa(p.Round.Number),
+			"MORTAL_PROMPTER_HAS_ISSUES="+strconv.FormatBool(p.Round.HasIssues))
+	}
+	if p.Session != nil {
+		env = append(env,
+			"MORTAL_PROMPTER_STATE="+p.Session.State,
+			"MORTAL_PROMPTER_SUCCESS="+strconv.FormatBool(p.Session.Success))
+	}
+	return env
+}
+
+// Runner runs the hooks configured for each point in a working directory.
+type Runner struct {
+	hooks   map[Point][]string
+	workDir string
+	timeout time.Duration
+}
+
+// New creates a new Runner for the given hooks.
+// Blank commands are ignored.
+func New(workDir string, hooks map[Point][]string, timeout time.Duration) *Runner {
+	cmds := make(map[Point][]string, len(hooks))
+	for point, commands := range hooks {
+		for _, c := range commands {
+			if c = strings.TrimSpace(c); c != "" {
+				cmds[point] = append(cmds[point], c)
+			}
+		}
+	}
+	return &Runner{
+		hooks:   cmds,
+		workDir: workDir,
+		timeout: timeout,
+	}
+}
+
+// Enabled reports whether there is at least one hook to run at point.
+func (r *Runner) Enabled(point Point) bool {
+	return len(r.hooks[point]) > 0
+}
+
+// Run executes the hooks of payload.Hook in order through the shell, stopping
+// at the first veto. An error is returned only if ctx is cancelled.
+func (r *Runner) Run(ctx context.Context, payload Payload) ([]types.HookResult, error) {
+	payload.WorkDir = r.workDir
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook input: %w", err)
+	}
+
+	commands := r.hooks[payload.Hook]
+	results := make([]types.HookResult, 0, len(commands))
+	for _, command := range commands {
+		result := r.runHook(ctx, command, payload, input)
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		results = append(results, result)
+		if result.Vetoed {
+			break
+		}
+	}
+	return results, nil
+}
+
+// runHook executes a single hook and captures its outcome.
+func (r *Runner) runHook(ctx context.Context, command string, payload Payload, input []byte) types.HookResult {
+	execCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := shellCommand(execCtx, command)
+	cmd.Dir = r.workDir
+	cmd.Env = append(os.Environ(), payload.env()...)
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := proc.Run(cmd)
+	result := types.HookResult{
+		Hook:     string(payload.Hook),
+		Command:  command,
+		Vetoed:   err != nil,
+		Output:   truncateOutput(stderr.String()),
+		Issues:   parseIssues(stdout.String()),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case execCtx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.Output = appendLine(result.Output, fmt.Sprintf("timed out after %v", r.timeout))
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+		result.Output = appendLine(result.Output, err.Error())
+	}
+
+	return result
+}
+
+// shellCommand builds the command running command through the platform shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return proc.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return proc.CommandContext(ctx, "sh", "-c", command)
+}
+
+// parseIssues returns the non-blank lines of output.
+func parseIssues(output string) []string {
+	var issues []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			issues = append(issues, line)
+		}
+	}
+	return issues
+}
+
+// Vetoed reports whether any result vetoed its step.
+func Vetoed(results []types.HookResult) bool {
+	for _, r := range results {
+		if r.Vetoed {
+			return true
+		}
+	}
+	return false
+}
+
+// VetoError is returned when a hook vetoes a step the session cannot go on without.
+type VetoError struct {
+	Result types.HookResult
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("%s hook `%s` vetoed the session (exit code %d)", e.Result.Hook, e.Result.Command, e.Result.ExitCode)
+}
+
+// Veto returns a *VetoError for the result that vetoed its step, or nil if none did.
+func Veto(results []types.HookResult) *VetoError {
+	for _, r := range results {
+		if r.Vetoed {
+			return &VetoError{Result: r}
+		}
+	}
+	return nil
+}
+
+// Findings returns the issues the hooks wrote to stdout, and a critical issue
+// for a veto, with the tail of the hook's stderr, which no fail-on threshold
+// lets through.
+func Findings(results []types.HookResult) []types.Issue {
+	var findings []types.Issue
+	for _, r := range results {
+		for _, issue := range r.Issues {
+			findings = append(findings, types.ParseIssue(issue))
+		}
+		if !r.Vetoed {
+			continue
+		}
+		issue := fmt.Sprintf("%s hook `%s` vetoed the changes (exit code %d)", r.Hook, r.Command, r.ExitCode)
+		if tail := lastLines(strings.TrimSpace(r.Output), maxIssueLines); tail != "" {
+			issue += ":\n" + tail
+		}
+		findings = append(findings, types.Issue{
+			ID:          types.IssueID("", issue),
+			Severity:    types.SeverityCritical,
+			Category:    "hook",
+			Description: issue,
+		})
+	}
+	return findings
+}
+
+// truncateOutput keeps the last maxOutputSize bytes of output.
+func truncateOutput(output string) string {
+	if len(output) <= maxOutputSize {
+		return output
+	}
+	return "...(truncated)\n" + output[len(output)-maxOutputSize:]
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+// appendLine appends line to output on a line of its own.
+func appendLine(output, line string) string {
+	if output == "" || strings.HasSuffix(output, "\n") {
+		return output + line
+	}
+	return output + "\n" + line
+}
@@ -0,0 +1,150 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use POSIX shell commands")
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	for _, p := range Points {
+		got, err := ParsePoint(string(p))
+		if err != nil || got != p {
+			t.Errorf("ParsePoint(%q) = %q, %v", p, got, err)
+		}
+	}
+	if _, err := ParsePoint("after_lunch"); err == nil || !strings.Contains(err.Error(), "after_diff") {
+		t.Errorf("ParsePoint(after_lunch) error = %v, want the valid hooks listed", err)
+	}
+}
+
+func TestNew_IgnoresBlankCommands(t *testing.T) {
+	r := New(".", map[Point][]string{AfterDiff: {"./lint.sh", "  "}, OnAbort: {""}}, DefaultTimeout)
+	if !r.Enabled(AfterDiff) {
+		t.Error("Enabled(after_diff) = false, want true")
+	}
+	if r.Enabled(OnAbort) || r.Enabled(BeforeSession) {
+		t.Error("Enabled() = true for a point without commands")
+	}
+}
+
+func TestRun_Input(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	r := New(dir, map[Point][]string{
+		AfterReview: {`cat > input.json; echo "$MORTAL_PROMPTER_HOOK $MORTAL_PROMPTER_ROUND $MORTAL_PROMPTER_SESSION_ID" > env.txt`},
+	}, DefaultTimeout)
+
+	results, err := r.Run(context.Background(), Payload{
+		Hook:      AfterReview,
+		SessionID: "abc123",
+		Prompt:    "add a parser",
+		Round:     RoundData(&types.Round{Number: 2, GitDiff: "diff --git a/x b/x", HasIssues: true, Issues: []string{"bug"}}),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Vetoed || results[0].Hook != "after_review" {
+		t.Fatalf("results = %+v, want one passing after_review hook", results)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "input.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input Payload
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatalf("stdin is not JSON: %v\n%s", err, data)
+	}
+	if input.Hook != AfterReview || input.SessionID != "abc123" || input.WorkDir != dir ||
+		input.Round == nil || input.Round.Number != 2 || input.Round.Diff == "" || !input.Round.HasIssues {
+		t.Errorf("stdin = %s", data)
+	}
+
+	env, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(env)); got != "after_review 2 abc123" {
+		t.Errorf("environment = %q, want %q", got, "after_review 2 abc123")
+	}
+}
+
+func TestRun_IssuesAndVeto(t *testing.T) {
+	skipOnWindows(t)
+
+	r := New(t.TempDir(), map[Point][]string{
+		AfterDiff: {
+			"echo '[high] main.go:12 - Unchecked error'; echo; echo 'Missing changelog entry'",
+			"echo 'lint failed' >&2; exit 2",
+			"echo never",
+		},
+	}, DefaultTimeout)
+
+	results, err := r.Run(context.Background(), Payload{Hook: AfterDiff})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want the run to stop at the veto", len(results))
+	}
+	if got := results[0].Issues; len(got) != 2 || got[1] != "Missing changelog entry" {
+		t.Errorf("Issues = %q, want the non-blank stdout lines", got)
+	}
+	if !results[1].Vetoed || results[1].ExitCode != 2 || !strings.Contains(results[1].Output, "lint failed") {
+		t.Errorf("veto = %+v", results[1])
+	}
+	if !Vetoed(results) || Veto(results) == nil || Veto(results).Result.Command != results[1].Command {
+		t.Errorf("Vetoed() = %v, Veto() = %v, want the second hook", Vetoed(results), Veto(results))
+	}
+
+	findings := Findings(results)
+	if len(findings) != 3 {
+		t.Fatalf("Findings() = %+v, want two issues and the veto", findings)
+	}
+	if findings[0].Severity != types.SeverityHigh || findings[0].File != "main.go" {
+		t.Errorf("first finding = %+v, want the parsed issue", findings[0])
+	}
+	if findings[2].Severity != types.SeverityCritical || !strings.Contains(findings[2].Description, "lint failed") {
+		t.Errorf("veto finding = %+v, want a critical issue with the output", findings[2])
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	skipOnWindows(t)
+
+	r := New(t.TempDir(), map[Point][]string{OnSuccess: {"sleep 5"}}, 100*time.Millisecond)
+	results, err := r.Run(context.Background(), Payload{Hook: OnSuccess})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Vetoed || results[0].ExitCode != -1 || !strings.Contains(results[0].Output, "timed out") {
+		t.Errorf("results = %+v, want a timed out veto", results)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	skipOnWindows(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := New(t.TempDir(), map[Point][]string{BeforeSession: {"true"}}, DefaultTimeout)
+	if _, err := r.Run(ctx, Payload{Hook: BeforeSession}); err == nil {
+		t.Error("Run() error = nil, want the cancellation")
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// HandleEvent logs a session event, so that the logger can be subscribed to
+// the orchestrator's event bus. Events without terminal or log file output,
+// such as usage updates, are ignored.
+func (l *Logger) HandleEvent(e events.Event) {
+	switch e := e.(type) {
+	case events.RoundStart:
+		l.RoundStart(e.Round)
+	case events.FighterEnter:
+		l.FighterEnter(e.Fighter)
+	case events.FighterAction:
+		l.FighterAction(fmt.Sprintf("%s: %s", e.Fighter, e.Action))
+	case events.FighterInput:
+		l.CLIInput(e.Fighter, e.Input)
+	case events.FighterOutput:
+		l.CLIOutput(e.Fighter, e.Output)
+	case events.FighterFinish:
+		l.FighterFinish(e.Fighter, e.Duration)
+	case events.DiffCaptured:
+		l.GitDiff(e.Diff)
+	case events.ChangesDetected:
+		l.ChangesDetected(e.Files)
+	case events.Verification:
+		for _, r := range e.Results {
+			l.Verification(r.Command, r.Passed, r.ExitCode, r.Duration)
+		}
+	case events.Hooks:
+		for _, r := range e.Results {
+			l.Hook(r.Hook, r.Command, r.Vetoed, r.ExitCode, len(r.Issues), r.Duration)
+		}
+	case events.Guardrails:
+		for _, v := range e.Violations {
+			l.Violation(v.Message, v.Reverted)
+		}
+	case events.ReviewChunk:
+		l.Debug(chunkSummary(e.Chunk))
+	case events.ReviewVerdicts:
+		// A single reviewer's verdict is the round's, reported with its issues
+		if len(e.Reviews) > 1 {
+			for _, r := range e.Reviews {
+				l.ReviewerVerdict(r.Reviewer, len(r.Issues), r.HasIssues)
+			}
+		}
+		for _, v := range e.Verdicts {
+			l.IssueVerdict(v.ID, string(v.Status), v.Note)
+		}
+	case events.IssuesFound:
+		issues := make([]string, len(e.Issues))
+		for i, issue := range e.Issues {
+			issues[i] = issue.String()
+		}
+		l.IssuesFound(e.Source, issues)
+	case events.NoIssues:
+		l.NoIssues()
+	case events.SessionComplete:
+		// A tournament's contenders celebrate their own victories
+		if e.Success && e.Result != nil && e.Result.Tournament == nil {
+			l.FinalVictory(e.Result.TotalRounds, e.Result.TotalDuration)
+		}
+	case events.Info:
+		l.Info(e.Message)
+	case events.Warning:
+		l.Error(e.Err)
+	case events.Error:
+		l.Error(e.Err)
+	}
+}
+
+// chunkSummary describes the review of a part of a large diff for the verbose log.
+func chunkSummary(chunk types.ChunkReview) string {
+	part := fmt.Sprintf("Review part %d/%d (%d bytes: %s)", chunk.Index, chunk.Total, chunk.Bytes, strings.Join(chunk.Files, ", "))
+	if chunk.Index == 0 {
+		part = fmt.Sprintf("Cross-file review of %d parts", chunk.Total)
+	}
+	if !chunk.HasIssues && len(chunk.Findings) == 0 {
+		return fmt.Sprintf("%s: LGTM, took %s", part, formatDuration(chunk.Duration))
+	}
+	verdict := "non-blocking"
+	if chunk.HasIssues {
+		verdict = "blocking"
+	}
+	return fmt.Sprintf("%s: %d %s issue(s), took %s", part, len(chunk.Findings), verdict, formatDuration(chunk.Duration))
+}
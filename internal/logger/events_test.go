@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func TestHandleEvent(t *testing.T) {
+	tempDir := t.TempDir()
+	l, err := New(tempDir, false)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer l.Close()
+
+	var stdout, stderr bytes.Buffer
+	l.SetOutputWriters(&stdout, &stderr)
+
+	l.HandleEvent(events.RoundStart{Round: 2})
+	l.HandleEvent(events.IssuesFound{Source: "CODEX", Issues: []types.Issue{types.ParseIssue("[high] main.go:12 - Unchecked error")}})
+	l.HandleEvent(events.UsageUpdated{Total: types.Usage{CostUSD: 1}})
+	l.HandleEvent(events.Warning{Err: errors.New("checkpoint not saved")})
+	l.HandleEvent(events.Hooks{Point: "after_diff", Results: []types.HookResult{{Hook: "after_diff", Command: "./lint.sh", Vetoed: true, ExitCode: 1}}})
+	l.HandleEvent(events.ReviewVerdicts{Verdicts: []types.IssueVerdict{{ID: "3f2a9c1e", Status: types.IssueResolved, Note: "guarded now"}}})
+
+	output := stdout.String()
+	if !strings.Contains(output, "ROUND 2") {
+		t.Errorf("RoundStart event was not logged: %s", output)
+	}
+	if !strings.Contains(output, "CODEX found 1 issue(s)") || !strings.Contains(output, "Unchecked error") {
+		t.Errorf("IssuesFound event was not logged with its source: %s", output)
+	}
+	if !strings.Contains(output, "./lint.sh") {
+		t.Errorf("Hooks event was not logged: %s", output)
+	}
+	if !strings.Contains(output, "#3f2a9c1e resolved - guarded now") {
+		t.Errorf("issue verdict was not logged: %s", output)
+	}
+	if !strings.Contains(stderr.String(), "checkpoint not saved") {
+		t.Errorf("Warning event was not logged as an error: %s", stderr.String())
+	}
+}
+
+func TestHandleEvent_SessionComplete(t *testing.T) {
+	tempDir := t.TempDir()
+	l, err := New(tempDir, false)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer l.Close()
+
+	var stdout bytes.Buffer
+	l.SetOutputWriters(&stdout, &bytes.Buffer{})
+
+	// The winner of a tournament already celebrated as a contender
+	l.HandleEvent(events.SessionComplete{Result: &types.SessionResult{Tournament: &types.TournamentResult{}}, Success: true})
+	if strings.Contains(stdout.String(), "FLAWLESS VICTORY") {
+		t.Errorf("tournament result was celebrated: %s", stdout.String())
+	}
+
+	l.HandleEvent(events.SessionComplete{Result: &types.SessionResult{TotalRounds: 3, TotalDuration: time.Minute}, Success: true})
+	if !strings.Contains(stdout.String(), "FLAWLESS VICTORY") {
+		t.Errorf("successful session was not celebrated: %s", stdout.String())
+	}
+}
+
+func TestHandleEvent_ReviewChunk(t *testing.T) {
+	chunk := events.ReviewChunk{Chunk: types.ChunkReview{
+		Index: 2, Total: 3, Files: []string{"a.go", "b.go"}, Bytes: 4096, HasIssues: true,
+		Findings: []types.Issue{types.ParseIssue("[high] a.go:3 - Unchecked error")},
+	}}
+
+	for _, verbose := range []bool{false, true} {
+		l, err := New(t.TempDir(), verbose)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
+		var stdout, stderr bytes.Buffer
+		l.SetOutputWriters(&stdout, &stderr)
+
+		l.HandleEvent(chunk)
+		l.HandleEvent(events.ReviewChunk{Chunk: types.ChunkReview{Total: 3}})
+		l.Close()
+
+		output := stdout.String()
+		logged := strings.Contains(output, "Review part 2/3 (4096 bytes: a.go, b.go): 1 blocking issue(s)") &&
+			strings.Contains(output, "Cross-file review of 3 parts: LGTM")
+		if logged != verbose {
+			t.Errorf("verbose %v: chunk reviews logged = %v:\n%s", verbose, logged, output)
+		}
+	}
+}
@@ -64,7 +64,8 @@ func New(outputDir string, verbose bool) (*Logger, error) {
 	}, nil
 }
 
-// SetOutputWriters allows setting custom writers for testing purposes.
+// SetOutputWriters allows setting custom writers, for testing purposes or to
+// keep stdout free for a machine-readable event stream.
 func (l *Logger) SetOutputWriters(stdout, stderr io.Writer) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -213,14 +214,14 @@ func (l *Logger) FighterFinish(name string, duration time.Duration) {
 	l.writeToFile("%s finished (took %s)", name, formatDuration(duration))
 }
 
-// IssuesFound displays the issues found by the reviewer.
-func (l *Logger) IssuesFound(issues []string) {
+// IssuesFound displays the issues found by the named reviewer.
+func (l *Logger) IssuesFound(reviewer string, issues []string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	l.StopSpinnerInternal()
 
-	msg := fmt.Sprintf("\u26A0\uFE0F  CODEX found %d issue(s)!", len(issues))
+	msg := fmt.Sprintf("\u26A0\uFE0F  %s found %d issue(s)!", reviewer, len(issues))
 	yellow := color.New(color.FgYellow, color.Bold)
 	l.printToTerminal(yellow.Sprint(msg))
 	l.printToTerminal("")
@@ -236,6 +237,96 @@ func (l *Logger) IssuesFound(issues []string) {
 	l.printToTerminal("")
 }
 
+// ReviewerVerdict displays the verdict of a single reviewer on a review panel.
+func (l *Logger) ReviewerVerdict(reviewer string, issueCount int, hasIssues bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.StopSpinnerInternal()
+
+	if hasIssues {
+		l.printToTerminal(color.YellowString("   %s: %d issue(s)", reviewer, issueCount))
+		l.writeToFile("%s verdict: %d issue(s)", reviewer, issueCount)
+		return
+	}
+	l.printToTerminal(color.GreenString("   %s: LGTM", reviewer))
+	l.writeToFile("%s verdict: LGTM", reviewer)
+}
+
+// IssueVerdict displays a reviewer's verdict on an issue of an earlier round.
+func (l *Logger) IssueVerdict(id, status, note string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.StopSpinnerInternal()
+
+	line := fmt.Sprintf("#%s %s", id, status)
+	if note != "" {
+		line += " - " + note
+	}
+	switch status {
+	case "resolved":
+		l.printToTerminal(color.GreenString("   \u2714 %s", line))
+	case "disputed":
+		l.printToTerminal(color.CyanString("   \u2696 %s", line))
+	default:
+		l.printToTerminal(color.YellowString("   \u2718 %s", line))
+	}
+	l.writeToFile("Issue %s", line)
+}
+
+// Verification displays the outcome of a verification command.
+func (l *Logger) Verification(command string, passed bool, exitCode int, duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.StopSpinnerInternal()
+
+	if passed {
+		l.printToTerminal(color.GreenString("   \u2705 %s passed (took %s)", command, formatDuration(duration)))
+		l.writeToFile("Verification passed: %s (took %s)", command, formatDuration(duration))
+		return
+	}
+	l.printToTerminal(color.RedString("   \u274C %s failed with exit code %d (took %s)", command, exitCode, formatDuration(duration)))
+	l.writeToFile("Verification failed: %s (exit code %d, took %s)", command, exitCode, formatDuration(duration))
+}
+
+// Hook displays the outcome of a lifecycle hook.
+func (l *Logger) Hook(point, command string, vetoed bool, exitCode, issues int, duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.StopSpinnerInternal()
+
+	if vetoed {
+		l.printToTerminal(color.RedString("   \u26D4 %s hook %s vetoed with exit code %d (took %s)", point, command, exitCode, formatDuration(duration)))
+		l.writeToFile("Hook %s vetoed: %s (exit code %d, %d issue(s), took %s)", point, command, exitCode, issues, formatDuration(duration))
+		return
+	}
+	if issues > 0 {
+		l.printToTerminal(color.YellowString("   \u2757 %s hook %s raised %d issue(s) (took %s)", point, command, issues, formatDuration(duration)))
+	} else {
+		l.printToTerminal(color.GreenString("   \u2705 %s hook %s passed (took %s)", point, command, formatDuration(duration)))
+	}
+	l.writeToFile("Hook %s passed: %s (%d issue(s), took %s)", point, command, issues, formatDuration(duration))
+}
+
+// Violation displays a guardrail broken by the implementer's changes.
+func (l *Logger) Violation(message string, reverted bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.StopSpinnerInternal()
+
+	if reverted {
+		l.printToTerminal(color.RedString("   \u26D4 Guardrail broken, reverted: %s", message))
+		l.writeToFile("Guardrail broken, reverted: %s", message)
+		return
+	}
+	l.printToTerminal(color.YellowString("   \u2757 Guardrail broken, kept: %s", message))
+	l.writeToFile("Guardrail broken, kept: %s", message)
+}
+
 // NoIssues displays a success message when no issues are found.
 func (l *Logger) NoIssues() {
 	l.mu.Lock()
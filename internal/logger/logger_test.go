@@ -191,9 +191,12 @@ func TestIssuesFound(t *testing.T) {
 		"Unused variable in main.go:12",
 	}
 
-	l.IssuesFound(issues)
+	l.IssuesFound("GEMINI", issues)
 
 	output := stdout.String()
+	if !strings.Contains(output, "GEMINI found 3 issue(s)") {
+		t.Errorf("IssuesFound output does not name the reviewer: %s", output)
+	}
 	if !strings.Contains(output, "3 issue(s)") {
 		t.Errorf("IssuesFound output does not contain issue count: %s", output)
 	}
@@ -205,6 +208,29 @@ func TestIssuesFound(t *testing.T) {
 	}
 }
 
+func TestVerification(t *testing.T) {
+	tempDir := t.TempDir()
+	l, err := New(tempDir, false)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	defer l.Close()
+
+	var stdout bytes.Buffer
+	l.SetOutputWriters(&stdout, &bytes.Buffer{})
+
+	l.Verification("go build ./...", true, 0, 3*time.Second)
+	l.Verification("go test ./...", false, 1, 12*time.Second)
+
+	output := stdout.String()
+	if !strings.Contains(output, "go build ./... passed (took 3s)") {
+		t.Errorf("Verification output does not report the passing command: %s", output)
+	}
+	if !strings.Contains(output, "go test ./... failed with exit code 1") {
+		t.Errorf("Verification output does not report the failing command: %s", output)
+	}
+}
+
 func TestNoIssues(t *testing.T) {
 	tempDir := t.TempDir()
 	l, err := New(tempDir, false)
@@ -354,7 +380,7 @@ func TestFileLogging(t *testing.T) {
 	l.RoundStart(1)
 	l.FighterEnter("CLAUDE CODE")
 	l.FighterFinish("CLAUDE CODE", 30*time.Second)
-	l.IssuesFound([]string{"Test issue"})
+	l.IssuesFound("CODEX", []string{"Test issue"})
 	l.NoIssues()
 	l.Info("Test info")
 	l.Error(errors.New("test error"))
@@ -0,0 +1,101 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/internal/session"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// errDirtyTree is returned when the session refuses to start on uncommitted changes.
+var errDirtyTree = errors.New("the working tree has uncommitted changes: commit or stash them, " +
+	"or run with --on-dirty keep or --on-dirty stash")
+
+// setupBaseline deals with the uncommitted changes in the working tree by the
+// on-dirty policy, then records the tree the session starts from, so that
+// the diffs of the rounds only have the session's own changes. A resumed
+// session keeps the baseline it started with.
+func (o *Orchestrator) setupBaseline() error {
+	if o.baseline != "" {
+		return nil
+	}
+
+	var exclude []string
+	if dir := outputPath(o.git.WorkDir(), o.config.OutputDir); dir != "" {
+		exclude = append(exclude, dir)
+	}
+	dirty, err := o.git.HasUncommittedChanges(exclude...)
+	if err != nil {
+		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+
+	if dirty {
+		action := o.config.OnDirty
+		if action == config.DirtyAsk {
+			o.state = types.StateWaitingConfirmation
+			action = config.DirtyRefuse
+			if o.confirm("The working tree has uncommitted changes. Stash them for the session and restore them after it?") {
+				action = config.DirtyStash
+			}
+			o.state = types.StateRunning
+		}
+
+		switch action {
+		case config.DirtyRefuse:
+			return errDirtyTree
+		case config.DirtyStash:
+			sha, err := o.git.Stash(fmt.Sprintf("mortal-prompter: session %s", o.sessionID))
+			if err != nil {
+				return fmt.Errorf("failed to stash the uncommitted changes: %w", err)
+			}
+			o.stash = sha
+			o.info("Stashed the uncommitted changes, they are restored when the session ends")
+		default:
+			index, err := o.git.IndexTree()
+			if err != nil {
+				return fmt.Errorf("failed to record the staged changes: %w", err)
+			}
+			o.keptChanges = true
+			o.baselineIndex = index
+			o.info("The working tree has uncommitted changes, only the session's own changes are reviewed and committed")
+		}
+	}
+
+	sha, err := o.git.SnapshotWorkTree(session.BaselineRef(o.sessionID),
+		fmt.Sprintf("mortal-prompter: session %s baseline", o.sessionID))
+	if err != nil {
+		o.restoreStash()
+		return fmt.Errorf("failed to snapshot the working tree: %w", err)
+	}
+	o.baseline = sha
+	return nil
+}
+
+// restoreStash puts back the changes stashed when the session started, also
+// when it was resumed after its process died. The stash of a session resumed
+// after an interruption was already restored when the interrupted run ended.
+func (o *Orchestrator) restoreStash() {
+	if o.stash == "" {
+		return
+	}
+	err := o.git.RestoreStash(o.stash)
+	switch {
+	case errors.Is(err, git.ErrStashNotFound):
+	case err != nil:
+		o.warn(fmt.Errorf("failed to restore the stashed changes, run git stash pop to restore them: %w", err))
+	default:
+		o.info("Restored the stashed changes")
+	}
+	o.stash = ""
+}
+
+// stagedDiff returns the staged changes relative to the baseline of the session.
+func (o *Orchestrator) stagedDiff() (string, error) {
+	if o.baseline == "" {
+		return o.git.GetStagedDiff()
+	}
+	return o.git.GetStagedDiffFrom(o.baseline)
+}
@@ -0,0 +1,280 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/internal/session"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// gitOutput runs git in dir and returns its output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+func TestRunDirtyTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		answers []bool
+		wantErr bool
+	}{
+		{"keep", config.DirtyKeep, nil, false},
+		{"stash", config.DirtyStash, nil, false},
+		{"ask and stash", config.DirtyAsk, []bool{true}, false},
+		{"ask and refuse", config.DirtyAsk, []bool{false}, true},
+		{"refuse", config.DirtyRefuse, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("my own work"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = filepath.Join(dir, ".mortal-prompter")
+			cfg.Prompt = "add feature"
+			cfg.AutoCommit = true
+			cfg.OnDirty = tt.policy
+			if err := cfg.EnsureOutputDir(); err != nil {
+				t.Fatal(err)
+			}
+
+			observer := &answerObserver{answers: tt.answers}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			orch.implementer = &fileImplementer{dir: dir}
+			reviewer := &countingReviewer{}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if tt.wantErr {
+				if !errors.Is(err, errDirtyTree) {
+					t.Fatalf("Run() error = %v, want %v", err, errDirtyTree)
+				}
+				if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+					t.Error("the session ran on the dirty tree")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("result = %+v, want success", result)
+			}
+
+			// Only the session's changes are reviewed and committed
+			for name, diff := range map[string]string{"round": result.Rounds[0].GitDiff, "final": result.FinalDiff} {
+				if !strings.Contains(diff, "feature.txt") || strings.Contains(diff, "notes.txt") {
+					t.Errorf("%s diff = %q, want only the session's changes", name, diff)
+				}
+			}
+			committed := gitOutput(t, dir, "show", "--name-only", "--format=", "HEAD")
+			if strings.TrimSpace(committed) != "feature.txt" {
+				t.Errorf("committed files = %q, want feature.txt", committed)
+			}
+
+			// The user's work is still there, uncommitted
+			content, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+			if err != nil || string(content) != "my own work" {
+				t.Errorf("notes.txt = %q, %v, want the user's work", content, err)
+			}
+			if status := gitOutput(t, dir, "status", "--porcelain", "notes.txt"); !strings.Contains(status, "?? notes.txt") {
+				t.Errorf("notes.txt status = %q, want it untracked", status)
+			}
+			if list := gitOutput(t, dir, "stash", "list"); list != "" {
+				t.Errorf("stash list = %q, want the stash restored", list)
+			}
+		})
+	}
+}
+
+func TestRunCleanTreeBaseline(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = filepath.Join(dir, ".mortal-prompter")
+	cfg.OnDirty = config.DirtyRefuse
+	if err := cfg.EnsureOutputDir(); err != nil {
+		t.Fatal(err)
+	}
+	// The session's own files in the output directory do not make the tree dirty
+	if err := os.WriteFile(filepath.Join(cfg.OutputDir, "session.log"), []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.State != types.StateCompleted || orch.baseline == "" {
+		t.Errorf("state = %s, baseline = %q, want a completed session with a baseline", result.State, orch.baseline)
+	}
+}
+
+// fakeCodex puts a codex CLI on PATH that approves everything and records its
+// arguments, and returns a function reading them back.
+func fakeCodex(t *testing.T) func() string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("the fake codex CLI needs a POSIX shell")
+	}
+
+	bin := t.TempDir()
+	log := filepath.Join(bin, "codex.log")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" >> '" + log + "'\necho LGTM\n"
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return func() string {
+		content, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("codex was not run: %v", err)
+		}
+		return string(content)
+	}
+}
+
+func TestRunDirtyTreeCodexReviewer(t *testing.T) {
+	codexArgs := fakeCodex(t)
+	dir := newTestRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("my own work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = filepath.Join(dir, ".mortal-prompter")
+	cfg.Prompt = "add feature"
+	cfg.OnDirty = config.DirtyKeep
+	if err := cfg.EnsureOutputDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, fighters.NewCodex(dir, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("result = %+v, want success", result)
+	}
+
+	// Codex reviews the round's diff, not the kept uncommitted changes
+	args := codexArgs()
+	if strings.Contains(args, "--uncommitted") {
+		t.Errorf("codex args = %q, want the diff sent as a prompt", args)
+	}
+	if !strings.Contains(args, "feature.txt") || strings.Contains(args, "notes.txt") || strings.Contains(args, "my own work") {
+		t.Errorf("codex args = %q, want only the session's changes", args)
+	}
+}
+
+func TestResumeRestoresStash(t *testing.T) {
+	dir := newTestRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("my own work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add feature"
+	cfg.OnDirty = config.DirtyStash
+
+	// The first run stashes the user's work and dies before restoring it
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := orch.setupBaseline(); err != nil {
+		t.Fatalf("setupBaseline() error = %v", err)
+	}
+	orch.state = types.StateRunning
+	orch.saveCheckpoint(nil)
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); !os.IsNotExist(err) {
+		t.Fatal("the user's work was not stashed")
+	}
+
+	cp, err := session.NewStore(cfg.OutputDir).Load(orch.SessionID())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cp.Stash == "" {
+		t.Fatal("the checkpoint does not record the stash")
+	}
+
+	resumed, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	resumed.Resume(cp)
+	resumed.implementer = &fileImplementer{dir: dir}
+	resumed.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := resumed.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success || strings.Contains(result.FinalDiff, "notes.txt") {
+		t.Errorf("result = %+v, want success without the user's work", result)
+	}
+
+	// The resumed session gives the user's work back when it ends
+	content, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	if err != nil || string(content) != "my own work" {
+		t.Errorf("notes.txt = %q, %v, want the user's work restored", content, err)
+	}
+	if list := gitOutput(t, dir, "stash", "list"); list != "" {
+		t.Errorf("stash list = %q, want the stash restored", list)
+	}
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Causes attached to the session and round deadlines, so that running out of
+// time can be told apart from the user interrupting the battle.
+var (
+	errSessionDeadline = errors.New("session time limit reached")
+	errRoundDeadline   = errors.New("round time limit reached")
+)
+
+// withSessionDeadline bounds ctx by the configured session time limit.
+// The limit applies to each run, so time spent between resumes is not counted.
+func (o *Orchestrator) withSessionDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.config.SessionTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, o.config.SessionTimeout, errSessionDeadline)
+}
+
+// withRoundDeadline bounds ctx by the configured round time limit.
+func (o *Orchestrator) withRoundDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.config.RoundTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, o.config.RoundTimeout, errRoundDeadline)
+}
+
+// deadlineReason explains why ctx ended if it was because of a time budget,
+// or returns an empty string if it is still running or was interrupted.
+func (o *Orchestrator) deadlineReason(ctx context.Context) string {
+	switch context.Cause(ctx) {
+	case errSessionDeadline:
+		return fmt.Sprintf("Session time limit of %s reached", o.config.SessionTimeout)
+	case errRoundDeadline:
+		return fmt.Sprintf("Round %d exceeded the round time limit of %s", o.currentRound, o.config.RoundTimeout)
+	default:
+		return ""
+	}
+}
+
+// costBudgetReason explains why the spend budget is exhausted, or returns an
+// empty string if there is no budget or it has not been used up.
+func (o *Orchestrator) costBudgetReason() string {
+	if o.config.MaxCost <= 0 {
+		return ""
+	}
+	spent := o.totalUsage().CostUSD
+	if spent < o.config.MaxCost {
+		return ""
+	}
+	return fmt.Sprintf("Spend budget exhausted: $%.2f of $%.2f", spent, o.config.MaxCost)
+}
+
+// totalUsage returns the usage reported by the fighters during planning and over all rounds.
+func (o *Orchestrator) totalUsage() types.Usage {
+	var total types.Usage
+	if o.plan != nil {
+		total = o.plan.Usage
+	}
+	for _, round := range o.rounds {
+		total = total.Add(round.Usage)
+	}
+	return total
+}
+
+// stopForBudget ends the session because a budget is exhausted.
+func (o *Orchestrator) stopForBudget(reason string, pendingIssues []string) *types.SessionResult {
+	o.state = types.StateBudgetExhausted
+	o.stopReason = reason
+	o.info(reason)
+	o.saveCheckpoint(pendingIssues)
+	result := o.buildResult(false)
+	o.publish(events.SessionComplete{Result: result})
+	return result
+}
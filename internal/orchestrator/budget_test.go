@@ -0,0 +1,153 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// slowImplementer is an Implementer that blocks until its context ends.
+type slowImplementer struct{}
+
+func (s *slowImplementer) Name() string { return "SLOW" }
+
+func (s *slowImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (s *slowImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+// meteredImplementer is a fileImplementer that reports a fixed cost per execution.
+type meteredImplementer struct {
+	fileImplementer
+	cost  float64
+	usage types.Usage
+}
+
+func (m *meteredImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	m.usage = m.usage.Add(types.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: m.cost})
+	return m.fileImplementer.Execute(ctx, prompt+time.Now().String(), imagePath)
+}
+
+func (m *meteredImplementer) Usage() types.Usage { return m.usage }
+
+// nitpickReviewer is a Reviewer that always finds a different issue.
+type nitpickReviewer struct {
+	calls int
+}
+
+func (n *nitpickReviewer) Name() string { return "NITPICK" }
+
+func (n *nitpickReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	n.calls++
+	issue := strings.Repeat("more ", n.calls) + "polish"
+	return &types.ReviewResult{HasIssues: true, Issues: []string{issue}}, nil
+}
+
+// newBudgetOrchestrator creates an orchestrator on a fresh repository with the
+// given implementer and a reviewer that is never satisfied.
+func newBudgetOrchestrator(t *testing.T, cfg *config.Config, implementer func(dir string) fighters.Implementer) *Orchestrator {
+	t.Helper()
+
+	dir := newTestRepo(t)
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.OnStall = config.StallActionStop
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = implementer(dir)
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &nitpickReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orch
+}
+
+func TestRunRoundTimeout(t *testing.T) {
+	cfg := config.New()
+	cfg.RoundTimeout = 50 * time.Millisecond
+	orch := newBudgetOrchestrator(t, cfg, func(string) fighters.Implementer { return &slowImplementer{} })
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.State != types.StateBudgetExhausted {
+		t.Errorf("State = %s, want %s", result.State, types.StateBudgetExhausted)
+	}
+	if !strings.Contains(result.StopReason, "Round 1 exceeded the round time limit") {
+		t.Errorf("StopReason = %q", result.StopReason)
+	}
+}
+
+func TestRunSessionTimeout(t *testing.T) {
+	cfg := config.New()
+	cfg.SessionTimeout = 50 * time.Millisecond
+	orch := newBudgetOrchestrator(t, cfg, func(string) fighters.Implementer { return &slowImplementer{} })
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.State != types.StateBudgetExhausted {
+		t.Errorf("State = %s, want %s", result.State, types.StateBudgetExhausted)
+	}
+	if !strings.Contains(result.StopReason, "Session time limit") {
+		t.Errorf("StopReason = %q", result.StopReason)
+	}
+}
+
+func TestRunCostBudget(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxCost = 1.0
+	orch := newBudgetOrchestrator(t, cfg, func(dir string) fighters.Implementer {
+		return &meteredImplementer{fileImplementer: fileImplementer{dir: dir}, cost: 0.4}
+	})
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.State != types.StateBudgetExhausted {
+		t.Fatalf("State = %s, want %s (reason: %s)", result.State, types.StateBudgetExhausted, result.StopReason)
+	}
+	if result.TotalRounds != 3 {
+		t.Errorf("TotalRounds = %d, want 3", result.TotalRounds)
+	}
+	if result.Usage.InputTokens != 300 || result.Usage.CostUSD < 1.0 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+	if !strings.HasPrefix(result.StopReason, "Spend budget exhausted: $1.20 of $1.00") {
+		t.Errorf("StopReason = %q", result.StopReason)
+	}
+}
+
+func TestDeadlineReason(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if reason := orch.deadlineReason(ctx); reason != "" {
+		t.Errorf("running context has reason %q", reason)
+	}
+	cancel()
+	if reason := orch.deadlineReason(ctx); reason != "" {
+		t.Errorf("user interruption should not be a budget, got %q", reason)
+	}
+}
@@ -0,0 +1,178 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// review runs the review panel on req. A diff larger than the review chunk
+// size is split into parts reviewed on their own, one after the other or
+// concurrently, then a cross-file pass over an outline of the whole diff looks
+// for the issues that span parts. The results are combined as if the panel had
+// reviewed the whole diff.
+func (o *Orchestrator) review(ctx context.Context, req types.ReviewRequest) (*review.Result, error) {
+	size := o.config.ReviewChunkSize
+	if size <= 0 || len(req.Diff) <= size {
+		return o.panel.Review(ctx, req)
+	}
+	chunks := review.SplitDiff(req.Diff, size)
+	if len(chunks) < 2 {
+		return o.panel.Review(ctx, req)
+	}
+
+	var files []string
+	for _, chunk := range chunks {
+		for _, file := range chunk.Files {
+			if !containsString(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+	o.info(fmt.Sprintf("The diff is %d bytes, reviewing it in %d parts", len(req.Diff), len(chunks)))
+
+	// Open issues are checked in the part with their file, the others and
+	// the plan in the cross-file pass
+	requests := make([]types.ReviewRequest, len(chunks))
+	for i, chunk := range chunks {
+		requests[i] = types.ReviewRequest{
+			Prompt: req.Prompt,
+			Round:  req.Round,
+			Diff:   chunk.Diff,
+			Range:  req.Range,
+			Chunk:  &types.ReviewChunk{Index: i + 1, Total: len(chunks), Files: files},
+		}
+	}
+	var crossIssues []types.Issue
+	for _, issue := range req.OpenIssues {
+		if i := chunkOf(chunks, issue.File); i >= 0 {
+			requests[i].OpenIssues = append(requests[i].OpenIssues, issue)
+		} else {
+			crossIssues = append(crossIssues, issue)
+		}
+	}
+
+	results := make([]*review.Result, len(chunks))
+	reviews := make([]types.ChunkReview, len(chunks))
+	reviewPart := func(ctx context.Context, i int) error {
+		o.publish(events.FighterAction{Fighter: o.panel.Name(),
+			Action: fmt.Sprintf("Reviewing part %d/%d (%d file(s))...", i+1, len(chunks), len(chunks[i].Files))})
+		start := time.Now()
+		result, err := o.panel.Review(ctx, requests[i])
+		if err != nil {
+			return fmt.Errorf("review of part %d/%d failed: %w", i+1, len(chunks), err)
+		}
+		results[i] = result
+		reviews[i] = chunkReview(requests[i].Chunk, chunks[i], result, time.Since(start))
+		o.publish(events.ReviewChunk{Chunk: reviews[i]})
+		return nil
+	}
+
+	if o.config.ParallelChunks {
+		// The parts share the reviewers, so their usage is only known as a whole
+		before := o.panel.Usages()
+		if err := runParallel(ctx, len(chunks), reviewPart); err != nil {
+			return nil, err
+		}
+		o.panel.SetBatchUsage(results, before)
+	} else {
+		for i := range chunks {
+			if err := reviewPart(ctx, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var findings []types.Issue
+	for _, result := range results {
+		findings = append(findings, result.Findings...)
+	}
+	cross := types.ReviewRequest{
+		Prompt:     req.Prompt,
+		Round:      req.Round,
+		Diff:       review.Outline(req.Diff, size),
+		Plan:       req.Plan,
+		Range:      req.Range,
+		OpenIssues: crossIssues,
+		Chunk:      &types.ReviewChunk{Total: len(chunks), Files: files, Findings: findings},
+	}
+	o.publish(events.FighterAction{Fighter: o.panel.Name(), Action: "Reviewing across the parts..."})
+	start := time.Now()
+	crossResult, err := o.panel.Review(ctx, cross)
+	if err != nil {
+		return nil, fmt.Errorf("cross-file review failed: %w", err)
+	}
+	crossReview := chunkReview(cross.Chunk, review.Chunk{Files: files, Diff: cross.Diff}, crossResult, time.Since(start))
+	o.publish(events.ReviewChunk{Chunk: crossReview})
+
+	result := o.panel.Combine(append(results, crossResult))
+	result.Chunks = append(reviews, crossReview)
+	return result, nil
+}
+
+// chunkReview records the panel's verdict on a part of a diff.
+func chunkReview(req *types.ReviewChunk, chunk review.Chunk, result *review.Result, duration time.Duration) types.ChunkReview {
+	return types.ChunkReview{
+		Index:     req.Index,
+		Total:     req.Total,
+		Files:     chunk.Files,
+		Bytes:     len(chunk.Diff),
+		HasIssues: result.HasIssues,
+		Findings:  result.Findings,
+		Duration:  duration,
+	}
+}
+
+// chunkOf returns the index of the first chunk changing file, or -1.
+func chunkOf(chunks []review.Chunk, file string) int {
+	if file == "" {
+		return -1
+	}
+	for i, chunk := range chunks {
+		if containsString(chunk.Files, file) {
+			return i
+		}
+	}
+	return -1
+}
+
+// runParallel calls fn for 0..n-1 concurrently. If any call fails, the
+// others are cancelled and the first error to occur is returned, not the
+// errors of the calls cancelled because of it.
+func runParallel(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var first error
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	return first
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// sprawlingImplementer is an Implementer that rewrites several large files
+// on every run.
+type sprawlingImplementer struct {
+	dir  string
+	runs int
+}
+
+func (i *sprawlingImplementer) Name() string { return "SPRAWLER" }
+
+func (i *sprawlingImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	i.runs++
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		content := fmt.Sprintf("run %d\n%s\n", i.runs, strings.Repeat(name, 100))
+		if err := os.WriteFile(filepath.Join(i.dir, name), []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	return "done", nil
+}
+
+func (i *sprawlingImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt + "\n" + strings.Join(previousIssues, "\n")
+}
+
+// chunkReviewer is a Reviewer that records its requests, flags b.txt the
+// first time it reviews it and resolves the open issues it is sent.
+type chunkReviewer struct {
+	mu       sync.Mutex
+	requests []types.ReviewRequest
+	flagged  bool
+}
+
+func (c *chunkReviewer) Name() string { return "CHUNKER" }
+
+func (c *chunkReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if !c.flagged && req.Chunk != nil && !req.Chunk.CrossFile() && strings.Contains(req.Diff, "b/b.txt") {
+		c.flagged = true
+		issue := types.Issue{Severity: types.SeverityHigh, File: "b.txt", StartLine: 2, Description: "Repeated name"}
+		return &types.ReviewResult{HasIssues: true, Issues: []string{issue.String()}, Findings: []types.Issue{issue}, RawOutput: "b.txt:2 - Repeated name"}, nil
+	}
+	result := &types.ReviewResult{RawOutput: "LGTM"}
+	for _, issue := range req.OpenIssues {
+		result.Verdicts = append(result.Verdicts, types.IssueVerdict{ID: issue.ID, Status: types.IssueResolved})
+	}
+	return result, nil
+}
+
+func TestRunChunkedReview(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel %v", parallel), func(t *testing.T) {
+			dir := newTestRepo(t)
+
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.Prompt = "add feature"
+			cfg.MaxIterations = 3
+			cfg.ReviewChunkSize = 700
+			cfg.ParallelChunks = parallel
+
+			observer := &answerObserver{}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			orch.implementer = &sprawlingImplementer{dir: dir}
+			reviewer := &chunkReviewer{}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !result.Success || result.TotalRounds != 2 {
+				t.Fatalf("ran %d round(s), success %v (%s), want 2 successful", result.TotalRounds, result.Success, result.StopReason)
+			}
+
+			first := result.Rounds[0]
+			if len(first.Chunks) != 4 {
+				t.Fatalf("round 1 has %d review parts, want 3 parts and the cross-file pass: %+v", len(first.Chunks), first.Chunks)
+			}
+			if !first.HasIssues || len(first.Findings) != 1 || first.Findings[0].File != "b.txt" {
+				t.Errorf("round 1 = issues %v, findings %+v, want the b.txt issue", first.HasIssues, first.Findings)
+			}
+			if cross := first.Chunks[3]; cross.Index != 0 || cross.Total != 3 || cross.HasIssues {
+				t.Errorf("cross-file pass = %+v", cross)
+			}
+
+			// Round 1 and round 2 each reviewed 3 parts and the whole diff
+			if len(reviewer.requests) != 8 {
+				t.Fatalf("reviewer got %d requests, want 8", len(reviewer.requests))
+			}
+			var crossRequests []types.ReviewRequest
+			for _, req := range reviewer.requests {
+				if req.Chunk == nil {
+					t.Fatal("a request was not part of a chunked review")
+				}
+				if req.Chunk.CrossFile() {
+					crossRequests = append(crossRequests, req)
+				} else if len(req.Diff) > cfg.ReviewChunkSize {
+					t.Errorf("part %d is %d bytes, over the chunk size", req.Chunk.Index, len(req.Diff))
+				}
+			}
+			if len(crossRequests) != 2 {
+				t.Fatalf("got %d cross-file requests, want one a round", len(crossRequests))
+			}
+			if cross := crossRequests[0]; strings.Contains(cross.Diff, "b.txtb.txt") || len(cross.Chunk.Findings) != 1 || len(cross.Chunk.Files) != 3 {
+				t.Errorf("cross-file request = %+v, want the outline and the issue found in the parts", cross)
+			}
+			for _, req := range reviewer.requests[4:] {
+				if len(req.OpenIssues) > 0 && !strings.Contains(req.Diff, "b/b.txt") {
+					t.Errorf("the open b.txt issue was sent to part %d without the file", req.Chunk.Index)
+				}
+			}
+
+			var published int
+			for _, e := range observer.published {
+				if _, ok := e.(events.ReviewChunk); ok {
+					published++
+				}
+			}
+			if published != 8 {
+				t.Errorf("published %d review_chunk events, want 8", published)
+			}
+		})
+	}
+}
+
+func TestRunParallelFirstError(t *testing.T) {
+	failure := fmt.Errorf("review of part 2/2 failed: reviewer crashed")
+	err := runParallel(context.Background(), 2, func(ctx context.Context, i int) error {
+		if i == 1 {
+			return failure
+		}
+		// Like the fighters, the cancelled part does not wrap context.Canceled
+		<-ctx.Done()
+		return fmt.Errorf("review of part 1/2 failed: claude execution was cancelled")
+	})
+	if err != failure {
+		t.Errorf("runParallel() error = %v, want %v", err, failure)
+	}
+}
+
+// meteredChunkReviewer is a Reviewer that reports a fixed cost per review and
+// holds the reviews of the parts until all of them have started, so that
+// they overlap.
+type meteredChunkReviewer struct {
+	mu      sync.Mutex
+	parts   int
+	started int
+	all     chan struct{}
+	usage   types.Usage
+}
+
+func (m *meteredChunkReviewer) Name() string { return "METERED" }
+
+func (m *meteredChunkReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	m.mu.Lock()
+	m.usage = m.usage.Add(types.Usage{InputTokens: 100, CostUSD: 1})
+	if !req.Chunk.CrossFile() {
+		if m.started++; m.started == m.parts {
+			close(m.all)
+		}
+	}
+	m.mu.Unlock()
+
+	if !req.Chunk.CrossFile() {
+		select {
+		case <-m.all:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &types.ReviewResult{RawOutput: "LGTM"}, nil
+}
+
+func (m *meteredChunkReviewer) Usage() types.Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+func TestReviewParallelChunksUsage(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = newTestRepo(t)
+	cfg.OutputDir = t.TempDir()
+	cfg.ReviewChunkSize = 700
+	cfg.ParallelChunks = true
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	reviewer := &meteredChunkReviewer{parts: 3, all: make(chan struct{})}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diff strings.Builder
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		fmt.Fprintf(&diff, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -0,0 +1 @@\n+%s\n", name, name, name, name, strings.Repeat(name, 100))
+	}
+
+	result, err := orch.review(context.Background(), types.ReviewRequest{Round: 1, Diff: diff.String()})
+	if err != nil {
+		t.Fatalf("review() error = %v", err)
+	}
+
+	// 3 parts and the cross-file pass, each counted once
+	want := types.Usage{InputTokens: 400, CostUSD: 4}
+	if len(result.Chunks) != 4 || result.Usage != want || result.Reviews[0].Usage != want {
+		t.Errorf("%d parts reviewed with usage %+v (reviewer %+v), want 4 with %+v",
+			len(result.Chunks), result.Usage, result.Reviews[0].Usage, want)
+	}
+}
@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// guardrailName is the source of the issues raised by the guardrails.
+const guardrailName = "GUARDRAILS"
+
+// checkGuardrails checks the staged changes of the implementer's last run
+// against the guardrails. The offending changes are reverted, unless the
+// on-violation action is ask and the user keeps them.
+func (o *Orchestrator) checkGuardrails() ([]types.Violation, error) {
+	rules := o.config.Guardrails
+	if !rules.Enabled() {
+		return nil, nil
+	}
+
+	base := o.roundBase()
+	var diff string
+	var err error
+	if base == "" {
+		diff, err = o.git.GetStagedDiff()
+	} else {
+		diff, err = o.git.GetStagedDiffFrom(base)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the diff of the round: %w", err)
+	}
+
+	violations := rules.Check(diff)
+	if len(violations) == 0 {
+		return nil, nil
+	}
+
+	if rules.Action() == guard.ActionAsk {
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = "- " + v.Message
+		}
+		o.state = types.StateWaitingConfirmation
+		keep := o.confirm(fmt.Sprintf("Round %d broke the guardrails:\n%s\nKeep the changes anyway? Otherwise they are reverted",
+			o.currentRound, strings.Join(messages, "\n")))
+		o.state = types.StateRunning
+		if keep {
+			o.publish(events.Guardrails{Violations: violations})
+			return violations, nil
+		}
+	}
+
+	// Size limits are broken by the run as a whole, protected paths file by file
+	if base == "" {
+		base = "HEAD"
+	}
+	if guard.Oversized(violations) {
+		err = o.git.RestoreSnapshot(base)
+	} else {
+		err = o.git.RestorePaths(base, guard.Protected(violations)...)
+	}
+	if err != nil {
+		return violations, fmt.Errorf("failed to revert the changes breaking the guardrails: %w", err)
+	}
+
+	for i := range violations {
+		violations[i].Reverted = true
+	}
+	o.publish(events.Guardrails{Violations: violations})
+	return violations, nil
+}
+
+// roundBase returns the commit the implementer's last run started from: the
+// snapshot of the previous round, or else the baseline of the session. It
+// returns an empty string if there is neither, when the run started from HEAD.
+func (o *Orchestrator) roundBase() string {
+	for i := len(o.rounds) - 1; i >= 0; i-- {
+		if o.rounds[i].SnapshotSHA != "" {
+			return o.rounds[i].SnapshotSHA
+		}
+	}
+	return o.baseline
+}
@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// trespassingImplementer is an Implementer that writes a feature file on
+// every run and rewrites a migration on its first run.
+type trespassingImplementer struct {
+	dir     string
+	runs    int
+	prompts []string
+}
+
+func (i *trespassingImplementer) Name() string { return "TRESPASSER" }
+
+func (i *trespassingImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	i.runs++
+	i.prompts = append(i.prompts, prompt)
+	if err := os.WriteFile(filepath.Join(i.dir, "feature.txt"), []byte(fmt.Sprintf("run %d\n", i.runs)), 0644); err != nil {
+		return "", err
+	}
+	if i.runs == 1 {
+		if err := os.WriteFile(filepath.Join(i.dir, "migrations", "001.sql"), []byte("DROP TABLE users;\n"), 0644); err != nil {
+			return "", err
+		}
+	}
+	return "done", nil
+}
+
+func (i *trespassingImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt + "\n" + strings.Join(previousIssues, "\n")
+}
+
+func TestRunGuardrails(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        guard.Rules
+		answers      []bool
+		wantRounds   int
+		wantReverted bool
+		wantFeature  bool // whether feature.txt survives the first round
+		wantMigrated bool // whether the migration keeps the implementer's change
+	}{
+		{"protected path reverted", guard.Rules{Protected: []string{"migrations"}}, nil, 2, true, true, false},
+		{"size limit reverted", guard.Rules{MaxFilesChanged: 1}, nil, 2, true, false, false},
+		{"kept by the user", guard.Rules{Protected: []string{"migrations/*.sql"}, OnViolation: guard.ActionAsk}, []bool{true}, 1, false, true, true},
+		{"declined by the user", guard.Rules{Protected: []string{"migrations/*.sql"}, OnViolation: guard.ActionAsk}, []bool{false}, 2, true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			if err := os.MkdirAll(filepath.Join(dir, "migrations"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "migrations", "001.sql"), []byte("CREATE TABLE users;\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			gitOutput(t, dir, "add", "-A")
+			gitOutput(t, dir, "commit", "-q", "-m", "add migration")
+
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.Prompt = "add feature"
+			cfg.MaxIterations = 3
+			cfg.Guardrails = tt.rules
+
+			observer := &answerObserver{answers: tt.answers}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			implementer := &trespassingImplementer{dir: dir}
+			orch.implementer = implementer
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !result.Success || result.TotalRounds != tt.wantRounds {
+				t.Fatalf("ran %d round(s), success %v (%s), want %d successful", result.TotalRounds, result.Success, result.StopReason, tt.wantRounds)
+			}
+
+			first := result.Rounds[0]
+			if len(first.Violations) == 0 {
+				t.Fatal("the first round has no violations")
+			}
+			if got := guard.Reverted(first.Violations); got != tt.wantReverted {
+				t.Errorf("violations reverted = %v, want %v", got, tt.wantReverted)
+			}
+			if strings.Contains(first.GitDiff, "feature.txt") != tt.wantFeature {
+				t.Errorf("round 1 diff = %q, want feature.txt kept: %v", first.GitDiff, tt.wantFeature)
+			}
+			if strings.Contains(first.GitDiff, "migrations/001.sql") != tt.wantMigrated {
+				t.Errorf("round 1 diff = %q, want the migration changed: %v", first.GitDiff, tt.wantMigrated)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, "migrations", "001.sql"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if migrated := string(content) != "CREATE TABLE users;\n"; migrated != tt.wantMigrated {
+				t.Errorf("migration = %q, want changed: %v", content, tt.wantMigrated)
+			}
+
+			if tt.wantReverted {
+				if first.Outcome != types.OutcomeRejected || !first.HasIssues || first.ReviewerOutput != "" {
+					t.Errorf("round 1 = %s with issues %v, want it rejected without a review", first.Outcome, first.HasIssues)
+				}
+				if len(implementer.prompts) < 2 || !strings.Contains(implementer.prompts[1], "Guardrail broken") {
+					t.Errorf("the violations were not handed to the implementer: %q", implementer.prompts)
+				}
+			}
+
+			var published bool
+			for _, e := range observer.published {
+				if g, ok := e.(events.Guardrails); ok && len(g.Violations) > 0 {
+					published = true
+				}
+			}
+			if !published {
+				t.Error("no guardrails event was published")
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// hookName labels the issues raised by the lifecycle hooks.
+const hookName = "HOOK"
+
+// runHooks runs the hooks at point with the data of round, which is nil
+// outside the rounds, and of the session result, set once the session ended.
+// The results are recorded on round, or on the session without one.
+func (o *Orchestrator) runHooks(ctx context.Context, point hooks.Point, round *types.Round, result *types.SessionResult) ([]types.HookResult, error) {
+	if o.hooks == nil || !o.hooks.Enabled(point) {
+		return nil, nil
+	}
+
+	o.publish(events.FighterAction{Fighter: o.ImplementerName(), Action: fmt.Sprintf("Running %s hooks...", point)})
+	results, err := o.hooks.Run(ctx, hooks.Payload{
+		Hook:        point,
+		SessionID:   o.sessionID,
+		Prompt:      o.config.Prompt,
+		Implementer: o.ImplementerName(),
+		Reviewer:    o.ReviewerName(),
+		Round:       hooks.RoundData(round),
+		Session:     hooks.SessionData(result),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s hooks interrupted: %w", point, err)
+	}
+	o.publish(events.Hooks{Point: string(point), Results: results})
+
+	switch {
+	case round != nil:
+		round.Hooks = append(round.Hooks, results...)
+	case result != nil:
+		result.Hooks = append(result.Hooks, results...)
+	default:
+		o.sessionHooks = append(o.sessionHooks, results...)
+	}
+	return results, nil
+}
+
+// stopForVeto ends the session after a hook vetoed it.
+func (o *Orchestrator) stopForVeto(veto *hooks.VetoError, pendingIssues []string) *types.SessionResult {
+	o.state = types.StateAborted
+	o.stopReason = fmt.Sprintf("Vetoed by the %s hook `%s` (exit code %d)", veto.Result.Hook, veto.Result.Command, veto.Result.ExitCode)
+	o.info(o.stopReason)
+	o.saveCheckpoint(pendingIssues)
+	result := o.buildResult(false)
+	o.publish(events.SessionComplete{Result: result})
+	return result
+}
+
+// successVetoed runs the on_success hooks and reports whether they vetoed
+// committing the approved changes, which an interrupted hook does too.
+func (o *Orchestrator) successVetoed(ctx context.Context, result *types.SessionResult) bool {
+	results, err := o.runHooks(ctx, hooks.OnSuccess, nil, result)
+	if err != nil {
+		o.warn(err)
+	} else if !hooks.Vetoed(results) {
+		return false
+	}
+
+	if o.config.Worktree {
+		o.info(fmt.Sprintf("Commit vetoed by the on_success hooks, keeping branch %s (worktree: %s)", o.branch, o.worktreePath))
+	} else if o.config.AutoCommit {
+		o.info("Commit vetoed by the on_success hooks, the changes are left staged")
+	}
+	return true
+}
+
+// hookFindings returns the issues raised by the hooks in results, labelled as such.
+func hookFindings(results []types.HookResult) []types.Issue {
+	findings := hooks.Findings(results)
+	for i := range findings {
+		findings[i].Reviewer = hookName
+	}
+	return findings
+}
+
+// addHookIssues adds the issues raised by the hooks in results to round.
+// Like the reviewers' issues, only those at or above the fail-on threshold
+// fail the round; a veto always does.
+func (o *Orchestrator) addHookIssues(round *types.Round, results []types.HookResult) {
+	for _, issue := range hookFindings(results) {
+		round.Findings = append(round.Findings, issue)
+		if issue.Severity.AtLeast(o.panel.FailOn()) {
+			round.HasIssues = true
+			round.Issues = append(round.Issues, issue.String())
+		}
+	}
+}
+
+// hookIssues returns the issues raised by the hooks in results, for the implementer.
+func hookIssues(results []types.HookResult) []string {
+	var issues []string
+	for _, issue := range hookFindings(results) {
+		issues = append(issues, issue.String())
+	}
+	return issues
+}
@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// newHookOrchestrator creates an orchestrator on a fresh repository with the
+// given hooks, a file implementer and a reviewer that approves everything.
+func newHookOrchestrator(t *testing.T, cfg *config.Config, hookCmds map[hooks.Point][]string) (*Orchestrator, *countingReviewer) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("hooks need a POSIX shell")
+	}
+
+	dir := newTestRepo(t)
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Hooks = hookCmds
+
+	orch, err := NewWithObserver(cfg, nil, &answerObserver{})
+	if err != nil {
+		t.Fatalf("NewWithObserver() error = %v", err)
+	}
+	reviewer := &countingReviewer{}
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orch, reviewer
+}
+
+func TestExecuteRoundHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		hooks       map[hooks.Point][]string
+		wantIssues  bool
+		wantReviews int
+	}{
+		{"after_diff veto skips the review", map[hooks.Point][]string{hooks.AfterDiff: {"echo 'lint failed' >&2; exit 1"}}, true, 0},
+		{"after_implementer veto skips the review", map[hooks.Point][]string{hooks.AfterImplementer: {"exit 3"}}, true, 0},
+		{"after_review issues fail the round", map[hooks.Point][]string{hooks.AfterReview: {"echo '[high] feature.txt:1 - Missing license header'"}}, true, 1},
+		{"passing hooks go to review", map[hooks.Point][]string{hooks.AfterDiff: {"true"}, hooks.AfterReview: {"true"}}, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch, reviewer := newHookOrchestrator(t, config.New(), tt.hooks)
+
+			round, err := orch.executeRound(context.Background(), 1, "add feature", nil)
+			if err != nil {
+				t.Fatalf("executeRound() error = %v", err)
+			}
+
+			if round.HasIssues != tt.wantIssues {
+				t.Errorf("HasIssues = %v, want %v (issues: %v)", round.HasIssues, tt.wantIssues, round.Issues)
+			}
+			if reviewer.calls != tt.wantReviews {
+				t.Errorf("reviewer called %d times, want %d", reviewer.calls, tt.wantReviews)
+			}
+			if len(round.Hooks) == 0 {
+				t.Error("Hooks = none, want the hook results recorded on the round")
+			}
+			for _, f := range round.Findings {
+				if f.Reviewer != hookName {
+					t.Errorf("finding %+v is not attributed to the hooks", f)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteRoundBeforeImplementerVeto(t *testing.T) {
+	orch, reviewer := newHookOrchestrator(t, config.New(), map[hooks.Point][]string{
+		hooks.BeforeImplementer: {"exit 1"},
+	})
+
+	_, err := orch.executeRound(context.Background(), 1, "add feature", nil)
+	var veto *hooks.VetoError
+	if err == nil || !errors.As(err, &veto) {
+		t.Fatalf("executeRound() error = %v, want a veto", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(orch.config.WorkDir, "feature.txt")); statErr == nil {
+		t.Error("the implementer ran despite the veto")
+	}
+	if reviewer.calls != 0 {
+		t.Errorf("reviewer called %d times, want 0", reviewer.calls)
+	}
+}
+
+func TestRunBeforeSessionVeto(t *testing.T) {
+	orch, _ := newHookOrchestrator(t, config.New(), map[hooks.Point][]string{
+		hooks.BeforeSession: {"echo 'tree is dirty' >&2; exit 1"},
+		hooks.OnAbort:       {`echo "$MORTAL_PROMPTER_STATE" > aborted.txt`},
+	})
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Success || result.State != types.StateAborted || result.TotalRounds != 0 {
+		t.Errorf("result = %+v, want an aborted session without rounds", result)
+	}
+	if !strings.Contains(result.StopReason, "before_session") {
+		t.Errorf("StopReason = %q, want the vetoing hook", result.StopReason)
+	}
+
+	data, err := os.ReadFile(filepath.Join(orch.config.WorkDir, "aborted.txt"))
+	if err != nil {
+		t.Fatalf("on_abort hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != string(types.StateAborted) {
+		t.Errorf("on_abort state = %q, want %q", got, types.StateAborted)
+	}
+}
+
+func TestRunOnSuccessVeto(t *testing.T) {
+	cfg := config.New()
+	cfg.AutoCommit = true
+	orch, _ := newHookOrchestrator(t, cfg, map[hooks.Point][]string{
+		hooks.OnSuccess: {"exit 1"},
+	})
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Success = false, want the approved session to succeed")
+	}
+	if len(result.Hooks) != 1 || !result.Hooks[0].Vetoed {
+		t.Errorf("Hooks = %+v, want the on_success veto", result.Hooks)
+	}
+
+	cmd := exec.Command("git", "rev-list", "--count", "HEAD")
+	cmd.Dir = orch.config.WorkDir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "1" {
+		t.Errorf("commit count = %s, want the commit skipped", got)
+	}
+}
@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// maxJudgedDiffSize is the maximum number of bytes of each contender's diff
+// shown to the judge, so that the prompt stays within command-line limits.
+const maxJudgedDiffSize = 30 * 1024
+
+// Patterns matching the judge's "WINNER: N" and "REASONING:" labels, also when
+// the judge formats them as markdown.
+var (
+	winnerPattern    = regexp.MustCompile(`(?i)winner\**\s*:\s*[*#\s]*(\d+)`)
+	reasoningPattern = regexp.MustCompile(`(?i)reasoning\**\s*:\**`)
+)
+
+// buildJudgePrompt asks the judge to compare the final diffs of the candidates
+// and pick the one that best solves the task.
+func buildJudgePrompt(t *prompts.Templates, task string, candidates []types.ContenderResult) string {
+	data := prompts.Data{Prompt: task, Contenders: make([]prompts.Contender, 0, len(candidates))}
+	for _, c := range candidates {
+		contender := prompts.Contender{
+			Number:      c.Number,
+			Implementer: c.Implementer,
+			Status:      contenderStatus(c),
+			Issues:      remainingIssues(c),
+		}
+		if c.Result != nil {
+			contender.Approved = c.Result.Success
+			contender.Rounds = c.Result.TotalRounds
+			contender.StopReason = c.Result.StopReason
+			contender.Diff = c.Result.FinalDiff
+		}
+		if len(contender.Diff) > maxJudgedDiffSize {
+			contender.Diff = contender.Diff[:maxJudgedDiffSize] + "\n... (diff truncated)"
+		}
+		data.Contenders = append(data.Contenders, contender)
+	}
+	return t.Render(prompts.Judge, data)
+}
+
+// parseJudgeVerdict extracts the winning contender and the reasoning from the
+// judge's output. The winner must be one of the candidates.
+func parseJudgeVerdict(output string, candidates []types.ContenderResult) (int, string, error) {
+	match := winnerPattern.FindStringSubmatch(output)
+	if match == nil {
+		return 0, "", errors.New("judge did not name a winner")
+	}
+	winner, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid winner %q: %w", match[1], err)
+	}
+
+	valid := false
+	for _, c := range candidates {
+		if c.Number == winner {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return 0, "", fmt.Errorf("judge picked contender #%d, which is not a candidate", winner)
+	}
+
+	var reasoning string
+	if loc := reasoningPattern.FindStringIndex(output); loc != nil {
+		reasoning = output[loc[1]:]
+	} else {
+		reasoning = strings.Replace(output, match[0], "", 1)
+	}
+	return winner, strings.TrimSpace(reasoning), nil
+}
+
+// contenderStatus summarizes how a contender's session ended.
+func contenderStatus(c types.ContenderResult) string {
+	if c.Result == nil {
+		return "failed: " + c.Error
+	}
+	if c.Result.Success {
+		return fmt.Sprintf("approved by the reviewer after %d round(s)", c.Result.TotalRounds)
+	}
+	status := fmt.Sprintf("not approved after %d round(s)", c.Result.TotalRounds)
+	if c.Result.StopReason != "" {
+		status += " (" + c.Result.StopReason + ")"
+	}
+	return status
+}
+
+// remainingIssues returns the issues still open when the contender stopped.
+func remainingIssues(c types.ContenderResult) []string {
+	if c.Result == nil || c.Result.Success || len(c.Result.Rounds) == 0 {
+		return nil
+	}
+	return c.Result.Rounds[len(c.Result.Rounds)-1].Issues
+}
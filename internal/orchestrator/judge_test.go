@@ -0,0 +1,130 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func testCandidates() []types.ContenderResult {
+	return []types.ContenderResult{
+		{Number: 1, Implementer: "CLAUDE", Result: &types.SessionResult{
+			Success:     true,
+			TotalRounds: 2,
+			FinalDiff:   "diff --git a/cache.go b/cache.go\n+claude",
+		}},
+		{Number: 3, Implementer: "GEMINI", Result: &types.SessionResult{
+			TotalRounds: 4,
+			StopReason:  "Aborted by user after reaching the maximum of 4 iterations",
+			FinalDiff:   "diff --git a/cache.go b/cache.go\n+gemini",
+			Rounds: []types.Round{
+				{Number: 4, HasIssues: true, Issues: []string{"cache is never invalidated"}},
+			},
+		}},
+	}
+}
+
+func TestBuildJudgePrompt(t *testing.T) {
+	prompt := buildJudgePrompt(prompts.Default(), "add caching", testCandidates())
+
+	expected := []string{
+		"TASK:\nadd caching",
+		"Do not modify any files",
+		"=== CONTENDER #1 (CLAUDE) ===",
+		"Status: approved by the reviewer after 2 round(s)",
+		"+claude",
+		"=== CONTENDER #3 (GEMINI) ===",
+		"Status: not approved after 4 round(s) (Aborted by user",
+		"- cache is never invalidated",
+		"+gemini",
+		"WINNER: <number>",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(prompt, exp) {
+			t.Errorf("prompt should contain %q", exp)
+		}
+	}
+}
+
+func TestBuildJudgePrompt_TruncatesDiffs(t *testing.T) {
+	candidates := testCandidates()
+	candidates[0].Result.FinalDiff = strings.Repeat("+line\n", maxJudgedDiffSize)
+
+	prompt := buildJudgePrompt(prompts.Default(), "add caching", candidates)
+	if !strings.Contains(prompt, "... (diff truncated)") {
+		t.Error("long diffs should be truncated")
+	}
+	if len(prompt) > 3*maxJudgedDiffSize {
+		t.Errorf("prompt is %d bytes, want the diff capped at %d", len(prompt), maxJudgedDiffSize)
+	}
+}
+
+func TestParseJudgeVerdict(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		wantWinner    int
+		wantReasoning string
+		wantErr       bool
+	}{
+		{
+			name:          "winner and reasoning",
+			output:        "WINNER: 3\nREASONING: It handles invalidation.",
+			wantWinner:    3,
+			wantReasoning: "It handles invalidation.",
+		},
+		{
+			name:          "markdown and hash",
+			output:        "After comparing both:\n\n**Winner:** #1\n\n**Reasoning:** simpler and approved.",
+			wantWinner:    1,
+			wantReasoning: "simpler and approved.",
+		},
+		{
+			name:          "no reasoning label",
+			output:        "WINNER: 1\nThe first one is cleaner.",
+			wantWinner:    1,
+			wantReasoning: "The first one is cleaner.",
+		},
+		{
+			name:    "no winner",
+			output:  "Both are fine.",
+			wantErr: true,
+		},
+		{
+			name:    "not a candidate",
+			output:  "WINNER: 2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, reasoning, err := parseJudgeVerdict(tt.output, testCandidates())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJudgeVerdict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if winner != tt.wantWinner {
+				t.Errorf("winner = %d, want %d", winner, tt.wantWinner)
+			}
+			if reasoning != tt.wantReasoning {
+				t.Errorf("reasoning = %q, want %q", reasoning, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestRemainingIssues(t *testing.T) {
+	candidates := testCandidates()
+
+	if issues := remainingIssues(candidates[0]); issues != nil {
+		t.Errorf("approved contender has open issues %v", issues)
+	}
+	if issues := remainingIssues(candidates[1]); len(issues) != 1 {
+		t.Errorf("remainingIssues() = %v, want the last round's issue", issues)
+	}
+	if issues := remainingIssues(types.ContenderResult{Error: "boom"}); issues != nil {
+		t.Errorf("failed contender has open issues %v", issues)
+	}
+}
@@ -0,0 +1,23 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// carryOpenIssues records the review of round in ledger and adds to the round
+// the issues of earlier rounds that are still open and that its reviewers did
+// not raise again, so that none is dropped while the reviewers still find
+// issues. Like the new issues, only those at or above the fail-on threshold
+// fail the round.
+func (o *Orchestrator) carryOpenIssues(round *types.Round, ledger *types.Ledger) {
+	ledger.Record(round)
+	for _, entry := range ledger.Carried(round) {
+		round.Findings = append(round.Findings, entry.Issue)
+		if entry.Issue.Severity.AtLeast(o.panel.FailOn()) {
+			round.HasIssues = true
+			round.Issues = append(round.Issues, fmt.Sprintf("%s (open since round %d)", entry.Issue, entry.RaisedIn))
+		}
+	}
+}
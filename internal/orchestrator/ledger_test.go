@@ -0,0 +1,88 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// ledgerReviewer is a Reviewer that raises one issue in its first review and
+// then gives a fixed verdict on it, if any.
+type ledgerReviewer struct {
+	issue   types.Issue
+	verdict types.IssueStatus
+	open    [][]types.Issue
+}
+
+func (l *ledgerReviewer) Name() string { return "LEDGER" }
+
+func (l *ledgerReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	l.open = append(l.open, req.OpenIssues)
+	if len(l.open) == 1 {
+		return &types.ReviewResult{HasIssues: true, Issues: []string{l.issue.String()}, Findings: []types.Issue{l.issue}}, nil
+	}
+	result := &types.ReviewResult{RawOutput: "LGTM"}
+	if l.verdict != "" {
+		result.Verdicts = []types.IssueVerdict{{ID: l.issue.ID, Status: l.verdict}}
+	}
+	return result, nil
+}
+
+func TestRunIssueLedger(t *testing.T) {
+	tests := []struct {
+		name        string
+		verdict     types.IssueStatus
+		wantSuccess bool
+		wantStatus  types.IssueStatus
+	}{
+		{"resolved issue is closed", types.IssueResolved, true, types.IssueResolved},
+		{"approval without a verdict resolves the issue", "", true, types.IssueResolved},
+		{"issue confirmed open is carried", types.IssueOpen, false, types.IssueOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.MaxIterations = 2
+			cfg.OnMaxIterations = config.MaxIterationsAbort
+			cfg.OnStall = config.StallActionStop
+
+			orch, err := New(cfg, nil)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			reviewer := &ledgerReviewer{issue: types.ParseIssue("[high] feature.txt:1 - Unchecked error"), verdict: tt.verdict}
+			orch.implementer = &fileImplementer{dir: dir}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if len(reviewer.open) != 2 || len(reviewer.open[1]) != 1 || reviewer.open[1][0].ID != reviewer.issue.ID {
+				t.Fatalf("open issues sent to the reviewer = %+v, want the issue of round 1 in round 2", reviewer.open)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (%s)", result.Success, tt.wantSuccess, result.StopReason)
+			}
+			if len(result.Ledger) != 1 || result.Ledger[0].Status != tt.wantStatus || result.Ledger[0].RaisedIn != 1 {
+				t.Fatalf("Ledger = %+v, want the issue %s", result.Ledger, tt.wantStatus)
+			}
+
+			last := result.Rounds[len(result.Rounds)-1]
+			if !tt.wantSuccess && (len(last.Findings) != 1 || last.Findings[0].ID != reviewer.issue.ID) {
+				t.Errorf("last round Findings = %+v, want the carried issue", last.Findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// noChangesNudge is handed to the implementer along with the pending issues
+// when it retries after a round in which it changed nothing.
+const noChangesNudge = "The previous round made no changes to the files. " +
+	"Make the changes by editing the files in the working directory instead of describing them."
+
+// changedInRound reports whether the implementer's last run changed the staged
+// tree, whose diff against the baseline is diff. From the second round on that
+// diff holds the work of the earlier rounds too, so the tree is compared with
+// the snapshot the run started from.
+func (o *Orchestrator) changedInRound(diff string) (bool, error) {
+	if strings.TrimSpace(diff) == "" {
+		return false, nil
+	}
+	base := o.roundBase()
+	if base == "" {
+		return true, nil
+	}
+	roundDiff, err := o.git.GetStagedDiffFrom(base)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(roundDiff) != "", nil
+}
+
+// retryNoChanges decides, following the on-no-changes policy, whether to run
+// another round after round left the tree unchanged. When it returns false
+// the state and stop reason of the session are set.
+func (o *Orchestrator) retryNoChanges(round *types.Round) bool {
+	reason := fmt.Sprintf("%s made no changes in round %d", round.Implementer, round.Number)
+
+	switch o.config.OnNoChanges {
+	case config.NoChangesFail:
+		o.state = types.StateFailed
+		o.stopReason = reason
+		return false
+	case config.NoChangesAsk:
+		o.state = types.StateWaitingConfirmation
+		if o.confirm(reason + ". Try again?") {
+			o.state = types.StateRunning
+			return true
+		}
+		o.state = types.StateAborted
+		o.stopReason = reason + ", stopped by user"
+		return false
+	default:
+		o.info(reason + ", trying again")
+		return true
+	}
+}
+
+// withNoChangesNudge returns issues with the nudge to make changes added.
+func withNoChangesNudge(issues []string) []string {
+	for _, issue := range issues {
+		if issue == noChangesNudge {
+			return issues
+		}
+	}
+	return append(append([]string(nil), issues...), noChangesNudge)
+}
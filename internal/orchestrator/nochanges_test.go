@@ -0,0 +1,161 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// unchangingImplementer is an Implementer that answers without touching the files
+// and records the prompts it was given.
+type unchangingImplementer struct {
+	prompts []string
+}
+
+func (i *unchangingImplementer) Name() string { return "NOOP" }
+
+func (i *unchangingImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	i.prompts = append(i.prompts, prompt)
+	return "Looks fine to me, nothing to change.", nil
+}
+
+func (i *unchangingImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt + "\n" + strings.Join(previousIssues, "\n")
+}
+
+func TestRunNoChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		answers    []bool
+		wantRounds int
+		wantState  types.SessionState
+		wantReason string
+	}{
+		{"retry", config.NoChangesRetry, nil, 3, types.StateAborted, "maximum of 3 iterations"},
+		{"fail", config.NoChangesFail, nil, 1, types.StateFailed, "NOOP made no changes in round 1"},
+		{"ask until declined", config.NoChangesAsk, []bool{true, false}, 2, types.StateAborted, "NOOP made no changes in round 2, stopped by user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.Prompt = "add feature"
+			cfg.MaxIterations = 3
+			cfg.OnNoChanges = tt.policy
+
+			observer := &answerObserver{answers: tt.answers}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			implementer := &unchangingImplementer{}
+			orch.implementer = implementer
+			reviewer := &countingReviewer{}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result.Success {
+				t.Error("a session without changes succeeded")
+			}
+			if result.TotalRounds != tt.wantRounds || result.State != tt.wantState {
+				t.Fatalf("ran %d round(s) ending %s (%s), want %d ending %s",
+					result.TotalRounds, result.State, result.StopReason, tt.wantRounds, tt.wantState)
+			}
+			if !strings.Contains(result.StopReason, tt.wantReason) {
+				t.Errorf("StopReason = %q, want it to mention %q", result.StopReason, tt.wantReason)
+			}
+			if reviewer.calls != 0 {
+				t.Errorf("reviewer called %d times, want no reviews of empty diffs", reviewer.calls)
+			}
+			for _, round := range result.Rounds {
+				if round.Outcome != types.OutcomeNoChanges {
+					t.Errorf("round %d outcome = %q, want %q", round.Number, round.Outcome, types.OutcomeNoChanges)
+				}
+			}
+
+			// Retries remind the implementer to actually edit the files
+			if strings.Contains(implementer.prompts[0], noChangesNudge) {
+				t.Error("first prompt has the no-changes nudge")
+			}
+			for _, prompt := range implementer.prompts[1:] {
+				if strings.Count(prompt, noChangesNudge) != 1 {
+					t.Errorf("retry prompt = %q, want the nudge once", prompt)
+				}
+			}
+		})
+	}
+}
+
+// onceImplementer is an Implementer that writes a file in its first run only.
+type onceImplementer struct {
+	dir  string
+	runs int
+}
+
+func (i *onceImplementer) Name() string { return "ONCE" }
+
+func (i *onceImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	i.runs++
+	if i.runs > 1 {
+		return "Already done.", nil
+	}
+	return "done", os.WriteFile(filepath.Join(i.dir, "feature.txt"), []byte("feature"), 0644)
+}
+
+func (i *onceImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+func TestRunNoChangesAfterFirstRound(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add feature"
+	cfg.MaxIterations = 3
+	cfg.OnNoChanges = config.NoChangesFail
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = &onceImplementer{dir: dir}
+	reviewer := &ledgerReviewer{issue: types.ParseIssue("[high] feature.txt:1 - Unchecked error")}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Round 2 left round 1's work as it was, so it is not reviewed or approved
+	if result.Success || result.State != types.StateFailed || result.TotalRounds != 2 {
+		t.Fatalf("ran %d round(s) ending %s (%s), want a failure after 2",
+			result.TotalRounds, result.State, result.StopReason)
+	}
+	if outcome := result.Rounds[1].Outcome; outcome != types.OutcomeNoChanges {
+		t.Errorf("round 2 outcome = %q, want %q", outcome, types.OutcomeNoChanges)
+	}
+	if len(reviewer.open) != 1 {
+		t.Errorf("reviewer called %d times, want only round 1 reviewed", len(reviewer.open))
+	}
+}
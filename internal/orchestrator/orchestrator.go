@@ -1,76 +1,307 @@
-// Package orchestrator implements the main battle loop between Claude Code and Codex.
+// Package orchestrator implements the main battle loop between the implementer and reviewer fighters.
 // It manages the iterative development and code review cycle.
 package orchestrator
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
 	"github.com/diegoram/mortal-prompter/internal/fighters"
 	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
 	"github.com/diegoram/mortal-prompter/internal/logger"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/internal/session"
+	"github.com/diegoram/mortal-prompter/internal/verify"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
-// Observer is the interface for receiving orchestrator events
+// Responder answers the questions asked during a session, as the TUI does.
+// Without one they are asked on the terminal.
+type Responder interface {
+	confirm.Confirmer
+	// OnPlanProposed asks for approval of the plan and returns the approved
+	// text, possibly edited, or false if the plan was rejected
+	OnPlanProposed(plan types.Plan) (string, bool)
+}
+
+// Observer follows the events of a session and answers its questions, as the TUI does.
 type Observer interface {
-	OnRoundStart(number int)
-	OnFighterEnter(fighter string)
-	OnFighterAction(fighter, action string)
-	OnFighterFinish(fighter string, duration time.Duration)
-	OnChangesDetected(fileCount int)
-	OnIssuesFound(issues []string)
-	OnNoIssues()
-	OnSessionComplete(result *types.SessionResult, success bool)
-	OnError(err error)
-	OnConfirmationRequired(message string) bool
-}
-
-// Orchestrator manages the code review battle between Claude Code and Codex.
+	events.Subscriber
+	Responder
+}
+
+// AutoResponder answers the questions of a session without asking anyone, so
+// that headless runs never block: it declines every confirmation and approves
+// plans as drafted.
+type AutoResponder struct{}
+
+// Confirm declines.
+func (AutoResponder) Confirm(q confirm.Question) (bool, error) {
+	return false, nil
+}
+
+// OnPlanProposed approves the plan as drafted.
+func (AutoResponder) OnPlanProposed(plan types.Plan) (string, bool) {
+	return plan.Text, true
+}
+
+// verificationName labels the issues raised by the verification gate.
+const verificationName = "VERIFICATION"
+
+// Orchestrator manages the code review battle between the implementer and reviewer.
 type Orchestrator struct {
-	config *config.Config
-	claude *fighters.Claude
-	codex  *fighters.Codex
-	git    *git.Git
-	logger *logger.Logger
+	config      *config.Config
+	implementer fighters.Implementer
+	planner     fighters.Implementer
+	panel       *review.Panel
+	verifier    *verify.Runner
+	hooks       *hooks.Runner
+	git         *git.Git
+	logger      *logger.Logger
+
+	// repo is the user's repository; git points at the session worktree in worktree mode
+	repo         *git.Git
+	branch       string
+	worktreePath string
+
+	// contender is set for the battles of a tournament, which leaves merging to the Tournament
+	contender bool
+
+	// bus delivers the events of the session to the logger, the TUI and any other subscriber
+	bus *events.Bus
+
+	// responder approves the plan (optional)
+	responder Responder
 
-	// Observer for TUI updates (optional)
-	observer Observer
+	// confirmer answers the yes/no questions of the session
+	confirmer confirm.Confirmer
 
 	// Session state
-	rounds       []types.Round
-	currentRound int
-	state        types.SessionState
-	startTime    time.Time
+	sessionID     string
+	store         *session.Store
+	rounds        []types.Round
+	currentRound  int
+	state         types.SessionState
+	startTime     time.Time
+	sessionStart  time.Time
+	pendingIssues []string
+	stopReason    string
+
+	// baseline is the snapshot of the working tree the session started from
+	baseline string
+
+	// keptChanges is set when the session started on uncommitted changes it left in place
+	keptChanges bool
+
+	// baselineIndex is the tree of what was staged when the session started
+	// on uncommitted changes, put back after committing
+	baselineIndex string
+
+	// stash holds the uncommitted changes stashed for the session, restored when it ends
+	stash string
+
+	// sessionHooks are the results of the hooks run before the first round
+	sessionHooks []types.HookResult
+
+	// plan is the plan approved in the planning phase, nil without one
+	plan *types.Plan
 
 	// Image path for multimodal prompts (only used in first round)
 	imagePath string
 }
 
 // New creates a new Orchestrator instance with the provided configuration and logger.
-func New(cfg *config.Config, log *logger.Logger) *Orchestrator {
+// The implementer is built from cfg.Implementer and the review panel from
+// cfg.Reviewers combined with cfg.ReviewPolicy.
+func New(cfg *config.Config, log *logger.Logger) (*Orchestrator, error) {
+	implementer, err := newImplementer(cfg, cfg.Implementer, cfg.WorkDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid implementer: %w", err)
+	}
+
+	panel, err := newPanel(cfg, cfg.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	planner, err := newPlanner(cfg, cfg.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := newGit(cfg, cfg.WorkDir)
+
+	bus := events.NewBus()
+	if log != nil {
+		bus.SubscribeSync(log)
+	}
+
 	return &Orchestrator{
 		config:       cfg,
-		claude:       fighters.NewClaude(cfg.WorkDir, fighters.DefaultTimeout),
-		codex:        fighters.NewCodex(cfg.WorkDir, fighters.DefaultTimeout),
-		git:          git.New(cfg.WorkDir),
+		implementer:  implementer,
+		planner:      planner,
+		panel:        panel,
+		verifier:     verify.New(cfg.WorkDir, cfg.Verify, verify.DefaultTimeout),
+		hooks:        hooks.New(cfg.WorkDir, cfg.Hooks, hooks.DefaultTimeout),
+		git:          repo,
 		logger:       log,
+		bus:          bus,
+		confirmer:    confirm.Stdin(),
+		repo:         repo,
+		sessionID:    session.NewID(),
+		store:        session.NewStore(cfg.OutputDir),
 		rounds:       make([]types.Round, 0),
 		currentRound: 0,
 		state:        types.StateInitializing,
+	}, nil
+}
+
+// newPlanner builds the planner fighter running in workDir, or returns nil if
+// the planning phase is disabled.
+func newPlanner(cfg *config.Config, workDir string) (fighters.Implementer, error) {
+	if !cfg.Plan {
+		return nil, nil
+	}
+	planner, err := newImplementer(cfg, cfg.PlannerType(), workDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid planner: %w", err)
+	}
+	return planner, nil
+}
+
+// newImplementer builds the implementer fighter ft running in workDir, with
+// the prompt templates configured for it.
+func newImplementer(cfg *config.Config, ft fighters.FighterType, workDir string) (fighters.Implementer, error) {
+	implementer, err := fighters.NewImplementer(ft, workDir, cfg.FighterTimeout)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := cfg.PromptTemplates(ft)
+	if err != nil {
+		return nil, err
+	}
+	fighters.SetTemplates(implementer, templates)
+	return implementer, nil
+}
+
+// newReviewer builds the reviewer fighter ft running in workDir, with the
+// prompt templates configured for it.
+func newReviewer(cfg *config.Config, ft fighters.FighterType, workDir string) (fighters.Reviewer, error) {
+	reviewer, err := fighters.NewReviewer(ft, workDir, cfg.FighterTimeout)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := cfg.PromptTemplates(ft)
+	if err != nil {
+		return nil, err
+	}
+	fighters.SetTemplates(reviewer, templates)
+	return reviewer, nil
+}
+
+// newPanel builds the review panel configured in cfg, running in workDir.
+func newPanel(cfg *config.Config, workDir string) (*review.Panel, error) {
+	policy, err := review.ParsePolicy(cfg.ReviewPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]fighters.Reviewer, 0, len(cfg.Reviewers))
+	for _, ft := range cfg.Reviewers {
+		reviewer, err := newReviewer(cfg, ft, workDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	panel, err := review.NewPanel(policy, reviewers...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reviewer: %w", err)
+	}
+	panel.SetFailOn(cfg.FailOn)
+	return panel, nil
+}
+
+// Resume restores the orchestrator from a checkpoint so that Run continues
+// with the next round, feeding the checkpoint's pending issues to the implementer.
+func (o *Orchestrator) Resume(cp *session.Checkpoint) {
+	o.sessionID = cp.ID
+	o.sessionStart = cp.StartedAt
+	o.config.Prompt = cp.Prompt
+	o.imagePath = cp.ImagePath
+	o.rounds = append(make([]types.Round, 0, len(cp.Rounds)), cp.Rounds...)
+	o.currentRound = len(cp.Rounds)
+	o.pendingIssues = cp.PendingIssues
+	o.branch = cp.Branch
+	o.worktreePath = cp.WorktreePath
+	o.plan = cp.Plan
+	o.baseline = cp.Baseline
+	o.keptChanges = cp.KeptChanges
+	o.baselineIndex = cp.BaselineIndex
+	o.stash = cp.Stash
+}
+
+// SessionID returns the identifier under which the session is checkpointed.
+func (o *Orchestrator) SessionID() string {
+	return o.sessionID
+}
+
+// NewWithObserver creates a new Orchestrator instance whose events are delivered
+// to observer, which also answers its questions.
+func NewWithObserver(cfg *config.Config, log *logger.Logger, observer Observer) (*Orchestrator, error) {
+	o, err := New(cfg, log)
+	if err != nil {
+		return nil, err
 	}
+	o.observe(observer)
+	return o, nil
+}
+
+// observe subscribes observer to the events of the session and has it answer the questions.
+func (o *Orchestrator) observe(observer Observer) {
+	if observer == nil {
+		return
+	}
+	o.bus.SubscribeSync(observer)
+	o.SetResponder(observer)
+}
+
+// Events returns the bus on which the events of the session are published.
+// The logger is subscribed to it from the start.
+func (o *Orchestrator) Events() *events.Bus {
+	return o.bus
+}
+
+// SetResponder sets what answers the questions of the session instead of the terminal.
+func (o *Orchestrator) SetResponder(r Responder) {
+	o.responder = r
+	o.confirmer = r
+}
+
+// SetConfirmer sets what answers the yes/no questions of the session,
+// overriding the responder for those.
+func (o *Orchestrator) SetConfirmer(c confirm.Confirmer) {
+	o.confirmer = c
 }
 
-// NewWithObserver creates a new Orchestrator instance with an observer for TUI updates.
-func NewWithObserver(cfg *config.Config, log *logger.Logger, observer Observer) *Orchestrator {
-	o := New(cfg, log)
-	o.observer = observer
-	return o
+// ImplementerName returns the display name of the implementer fighter.
+func (o *Orchestrator) ImplementerName() string {
+	return o.implementer.Name()
+}
+
+// ReviewerName returns the display name of the review panel.
+// With a single reviewer this is the name of that fighter.
+func (o *Orchestrator) ReviewerName() string {
+	return o.panel.Name()
 }
 
 // SetPrompt allows setting the prompt after creation (for TUI mode)
@@ -85,110 +316,272 @@ func (o *Orchestrator) SetImagePath(imagePath string) {
 
 // Run executes the main battle loop and returns the session result.
 // The loop continues until:
-// - Codex finds no issues (LGTM) -> Success
+// - The verification gate passes and the reviewer finds no issues (LGTM) -> Success
 // - Max iterations reached and user declines to continue -> Aborted
+// - A time or spend budget is exhausted -> BudgetExhausted
 // - An error occurs -> Failed
+// - A hook vetoes the session -> Aborted
 func (o *Orchestrator) Run(ctx context.Context) (*types.SessionResult, error) {
+	result, err := o.run(ctx)
+
+	// The on_abort hooks run even when the session was interrupted
+	if result != nil && !result.Success && !o.contender {
+		if _, hookErr := o.runHooks(context.WithoutCancel(ctx), hooks.OnAbort, nil, result); hookErr != nil {
+			o.warn(hookErr)
+		}
+	}
+
+	o.restoreStash()
+	return result, err
+}
+
+// run executes the battle loop of Run.
+func (o *Orchestrator) run(ctx context.Context) (*types.SessionResult, error) {
+	ctx, cancel := o.withSessionDeadline(ctx)
+	defer cancel()
+
 	o.startTime = time.Now()
+	if o.sessionStart.IsZero() {
+		o.sessionStart = o.startTime
+	}
 	o.state = types.StateRunning
 
 	// Verify this is a git repository
 	if !o.git.IsGitRepo() {
 		o.state = types.StateFailed
 		err := fmt.Errorf("working directory is not a git repository: %s", o.config.WorkDir)
-		o.notifyError(err)
+		o.publish(events.Error{Err: err})
 		return nil, err
 	}
 
+	o.publish(events.SessionStart{SessionID: o.sessionID, Implementer: o.ImplementerName(), Reviewer: o.ReviewerName()})
+
+	// Move the battle into its own worktree so the user's working tree is never touched.
+	// A tournament sets up its contenders' worktrees before running them.
+	if o.config.Worktree && !o.contender {
+		if err := o.setupWorktree(); err != nil {
+			o.state = types.StateFailed
+			o.publish(events.Error{Err: err})
+			return nil, err
+		}
+	}
+
+	// Set aside or leave out the user's own uncommitted changes
+	if !o.contender {
+		o.ignoreOutputDir()
+		if err := o.setupBaseline(); err != nil {
+			o.state = types.StateFailed
+			o.publish(events.Error{Err: err})
+			return nil, err
+		}
+	}
+
+	// Hooks may veto the session, or raise issues for the first round. An
+	// interrupted hook is handled like any interruption before the first round.
+	// In a tournament, only the contenders' round hooks run.
+	if !o.contender {
+		results, _ := o.runHooks(ctx, hooks.BeforeSession, nil, nil)
+		if veto := hooks.Veto(results); veto != nil {
+			return o.stopForVeto(veto, o.pendingIssues), nil
+		}
+		o.pendingIssues = append(o.pendingIssues, hookIssues(results)...)
+	}
+
+	// Agree on a plan before round 1; a resumed session keeps its approved plan
+	if o.planner != nil && o.plan == nil {
+		approved, err := o.runPlanning(ctx)
+		if err != nil {
+			if reason := o.deadlineReason(ctx); reason != "" {
+				o.warn(err)
+				return o.stopForBudget(reason, o.pendingIssues), nil
+			}
+			if ctx.Err() != nil {
+				o.state = types.StateInterrupted
+				o.stopReason = "Interrupted during planning"
+			} else {
+				o.state = types.StateFailed
+				o.stopReason = fmt.Sprintf("Planning failed: %v", err)
+			}
+			o.saveCheckpoint(o.pendingIssues)
+			o.publish(events.Error{Err: err})
+			return o.buildResult(false), err
+		}
+		if !approved {
+			o.state = types.StateAborted
+			o.stopReason = "Plan rejected by user"
+			o.info("Plan rejected by user")
+			result := o.buildResult(false)
+			o.publish(events.SessionComplete{Result: result})
+			return result, nil
+		}
+	}
+
 	currentPrompt := o.config.Prompt
-	var previousIssues []string
+	previousIssues := o.pendingIssues
+
+	if len(o.rounds) > 0 {
+		o.info(fmt.Sprintf("Resuming session %s at round %d", o.sessionID, o.currentRound+1))
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			o.state = types.StateAborted
+			if reason := o.deadlineReason(ctx); reason != "" {
+				return o.stopForBudget(reason, previousIssues), nil
+			}
+			o.state = types.StateInterrupted
+			o.stopReason = fmt.Sprintf("Interrupted before round %d", o.currentRound+1)
+			o.saveCheckpoint(previousIssues)
 			result := o.buildResult(false)
-			o.notifySessionComplete(result, false)
+			o.publish(events.SessionComplete{Result: result})
 			return result, ctx.Err()
 		default:
 		}
 
 		o.currentRound++
 
-		// Notify round start
-		o.notifyRoundStart(o.currentRound)
+		o.publish(events.RoundStart{Round: o.currentRound})
 
 		// Check if we've hit max iterations
-		if o.currentRound > o.config.MaxIterations {
-			o.state = types.StateWaitingConfirmation
-			if !o.promptContinue() {
-				o.state = types.StateAborted
-				if o.logger != nil {
-					o.logger.Info("Session aborted by user after max iterations")
-				}
-				result := o.buildResult(false)
-				o.notifySessionComplete(result, false)
-				return result, nil
-			}
-			o.state = types.StateRunning
+		if o.currentRound > o.config.MaxIterations && !o.continuePastMaxIterations() {
+			o.state = types.StateAborted
+			o.info(o.stopReason)
+			o.saveCheckpoint(previousIssues)
+			result := o.buildResult(false)
+			o.publish(events.SessionComplete{Result: result})
+			return result, nil
 		}
 
 		// Execute round
-		round, err := o.executeRound(ctx, o.currentRound, currentPrompt, previousIssues)
+		roundCtx, cancelRound := o.withRoundDeadline(ctx)
+		round, err := o.executeRound(roundCtx, o.currentRound, currentPrompt, previousIssues)
+		cancelRound()
 		if err != nil {
-			o.state = types.StateFailed
-			if o.logger != nil {
-				o.logger.Error(err)
+			var veto *hooks.VetoError
+			if errors.As(err, &veto) {
+				return o.stopForVeto(veto, previousIssues), nil
 			}
-			o.notifyError(err)
+			if reason := o.deadlineReason(roundCtx); reason != "" {
+				o.warn(err)
+				o.recordInterrupted(round)
+				return o.stopForBudget(reason, previousIssues), nil
+			}
+			if ctx.Err() != nil {
+				o.state = types.StateInterrupted
+				o.stopReason = fmt.Sprintf("Interrupted during round %d", o.currentRound)
+				o.recordInterrupted(round)
+			} else {
+				o.state = types.StateFailed
+				o.stopReason = fmt.Sprintf("Failed in round %d: %v", o.currentRound, err)
+				if round != nil && round.Outcome != "" {
+					o.recordRound(round)
+				}
+			}
+			o.saveCheckpoint(previousIssues)
+			o.publish(events.Error{Err: err})
 			return o.buildResult(false), err
 		}
 
-		o.rounds = append(o.rounds, *round)
+		o.recordRound(round)
+		if total := o.totalUsage(); total.CostUSD > 0 {
+			o.info(fmt.Sprintf("Spent $%.2f so far (%d input / %d output tokens)",
+				total.CostUSD, total.InputTokens, total.OutputTokens))
+		}
+
+		// A round without changes has nothing to approve; unless a hook raised
+		// issues, the implementer is nudged to try again or the session stops
+		noChanges := round.Outcome == types.OutcomeNoChanges && !round.HasIssues
+		if noChanges && !o.retryNoChanges(round) {
+			o.info(o.stopReason)
+			o.saveCheckpoint(previousIssues)
+			result := o.buildResult(false)
+			o.publish(events.SessionComplete{Result: result})
+			return result, nil
+		}
 
 		// Check if we're done (no issues found)
-		if !round.HasIssues {
+		if !noChanges && !round.HasIssues {
 			o.state = types.StateCompleted
-			if o.logger != nil {
-				o.logger.NoIssues()
-				o.logger.FinalVictory(len(o.rounds), time.Since(o.startTime))
+			o.stopReason = fmt.Sprintf("%s approved the changes in round %d", round.Reviewer, round.Number)
+			o.saveCheckpoint(nil)
+			o.publish(events.NoIssues{})
+
+			// Capture the final diff before any commit clears the staged changes
+			result := o.buildResult(true)
+
+			// A hook may veto committing the approved changes
+			if !o.contender && o.successVetoed(ctx, result) {
+				result.Branch = o.branch
+				o.publish(events.SessionComplete{Result: result, Success: true})
+				return result, nil
 			}
-			o.notifyNoIssues()
 
-			// Auto-commit if enabled
-			if o.config.AutoCommit {
+			if o.config.Worktree {
+				// In a tournament, committing and merging wait until every contender has finished
+				if !o.contender {
+					o.finishWorktree()
+				}
+			} else if o.config.AutoCommit {
+				// Auto-commit if enabled
 				if err := o.autoCommit(); err != nil {
-					if o.logger != nil {
-						o.logger.Error(fmt.Errorf("auto-commit failed: %w", err))
-					}
+					o.warn(fmt.Errorf("auto-commit failed: %w", err))
 				}
 			}
 
-			result := o.buildResult(true)
-			o.notifySessionComplete(result, true)
+			result.Branch = o.branch
+			o.publish(events.SessionComplete{Result: result, Success: true})
+			return result, nil
+		}
+
+		if noChanges {
+			previousIssues = withNoChangesNudge(previousIssues)
+		} else {
+			source := o.panel.Name()
+			if guard.Reverted(round.Violations) {
+				source = guardrailName
+			} else if !verify.Passed(round.Verification) {
+				source = verificationName
+			} else if hooks.Vetoed(round.Hooks) {
+				source = hookName
+			}
+			o.publish(events.IssuesFound{Source: source, Issues: round.Findings})
+
+			previousIssues = round.Issues
+		}
+		currentPrompt = o.config.Prompt // Base prompt stays the same, issues are added by BuildPromptWithIssues
+
+		// Persist progress so the session can be resumed from the next round
+		o.saveCheckpoint(previousIssues)
+
+		// Stop early when the rounds go in circles instead of burning iterations
+		if o.checkStall() {
+			o.info(o.stopReason)
+			o.saveCheckpoint(previousIssues)
+			result := o.buildResult(false)
+			o.publish(events.SessionComplete{Result: result})
 			return result, nil
 		}
 
+		// Stop before starting a round the spend budget cannot pay for
+		if reason := o.costBudgetReason(); reason != "" {
+			return o.stopForBudget(reason, previousIssues), nil
+		}
+
 		// Prepare for next round
 		if o.logger != nil {
-			o.logger.IssuesFound(round.Issues)
 			o.logger.PreparingNextRound()
 		}
-		o.notifyIssuesFound(round.Issues)
-
-		previousIssues = round.Issues
-		currentPrompt = o.config.Prompt // Base prompt stays the same, issues are added by BuildPromptWithIssues
 
 		// Interactive mode: ask before each round
 		if o.config.Interactive && o.currentRound < o.config.MaxIterations {
 			o.state = types.StateWaitingConfirmation
 			if !o.promptNextRound() {
 				o.state = types.StateAborted
-				if o.logger != nil {
-					o.logger.Info("Session aborted by user")
-				}
+				o.stopReason = fmt.Sprintf("Aborted by user after round %d", o.currentRound)
+				o.info("Session aborted by user")
+				o.saveCheckpoint(previousIssues)
 				result := o.buildResult(false)
-				o.notifySessionComplete(result, false)
+				o.publish(events.SessionComplete{Result: result})
 				return result, nil
 			}
 			o.state = types.StateRunning
@@ -196,145 +589,317 @@ func (o *Orchestrator) Run(ctx context.Context) (*types.SessionResult, error) {
 	}
 }
 
-// executeRound runs a single round of the battle.
+// executeRound runs a single round of the battle. When it fails, the round
+// returned holds what it got done before the failure.
 func (o *Orchestrator) executeRound(ctx context.Context, number int, basePrompt string, previousIssues []string) (*types.Round, error) {
 	roundStart := time.Now()
 
+	implementerName := o.implementer.Name()
+	reviewerName := o.panel.Name()
+
 	round := &types.Round{
-		Number:    number,
-		Timestamp: roundStart,
+		Number:      number,
+		Implementer: implementerName,
+		Reviewer:    reviewerName,
+		Timestamp:   roundStart,
 	}
 
-	if o.logger != nil {
-		o.logger.RoundStart(number)
+	// Hooks may veto the round, or raise issues for the implementer to address
+	beforeResults, err := o.runHooks(ctx, hooks.BeforeImplementer, round, nil)
+	if err != nil {
+		return round, err
+	}
+	if veto := hooks.Veto(beforeResults); veto != nil {
+		return round, veto
+	}
+	if issues := hookIssues(beforeResults); len(issues) > 0 {
+		previousIssues = append(append([]string(nil), previousIssues...), issues...)
 	}
 
 	// Build the prompt (includes issues if any)
-	prompt := o.claude.BuildPromptWithIssues(basePrompt, previousIssues)
-	round.ClaudePrompt = prompt
+	prompt := o.implementerPrompt(number, basePrompt, previousIssues)
+	round.ImplementerPrompt = prompt
 
-	// Execute Claude
-	if o.logger != nil {
-		o.logger.FighterEnter(o.claude.Name())
-		o.logger.FighterAction("Claude Code implementing changes...")
-		o.logger.CLIInput(o.claude.Name(), prompt)
-	}
-	o.notifyFighterEnter(o.claude.Name())
-	o.notifyFighterAction("Claude Code", "Implementing changes...")
+	// Execute implementer
+	o.publish(events.FighterEnter{Fighter: implementerName})
+	o.publish(events.FighterAction{Fighter: implementerName, Action: "Implementing changes..."})
+	o.publish(events.FighterInput{Fighter: implementerName, Input: prompt})
 
 	// Only pass image path on first round (subsequent rounds focus on issues)
 	imagePath := ""
 	if number == 1 && o.imagePath != "" {
 		imagePath = o.imagePath
-		if o.logger != nil {
-			o.logger.Info(fmt.Sprintf("Including image: %s", imagePath))
-		}
+		o.info(fmt.Sprintf("Including image: %s", imagePath))
 	}
 
-	claudeStart := time.Now()
-	claudeOutput, err := o.claude.Execute(ctx, prompt, imagePath)
-	claudeDuration := time.Since(claudeStart)
+	implementerStart := time.Now()
+	usageBefore := fighters.UsageOf(o.implementer)
+	implementerOutput, err := o.implementer.Execute(ctx, prompt, imagePath)
+	implementerDuration := time.Since(implementerStart)
+	round.Usage = fighters.UsageOf(o.implementer).Sub(usageBefore)
+	o.publish(events.UsageUpdated{Total: o.totalUsage().Add(round.Usage)})
+	o.publish(events.FighterOutput{Fighter: implementerName, Output: implementerOutput})
+	round.ImplementerOutput = implementerOutput
 
 	if err != nil {
-		if o.logger != nil {
-			o.logger.CLIOutput(o.claude.Name(), claudeOutput)
-		}
-		return nil, fmt.Errorf("claude execution failed: %w", err)
+		err = fmt.Errorf("%s execution failed: %w", implementerName, err)
+		round.Outcome = types.OutcomeImplementerError
+		round.Error = err.Error()
+		round.Duration = time.Since(roundStart)
+		return round, err
 	}
 
-	round.ClaudeOutput = claudeOutput
-	if o.logger != nil {
-		o.logger.CLIOutput(o.claude.Name(), claudeOutput)
-		o.logger.FighterFinish(o.claude.Name(), claudeDuration)
+	o.publish(events.FighterFinish{Fighter: implementerName, Duration: implementerDuration})
+
+	// Hooks may reject the changes, or raise issues with them
+	hookResults, err := o.runHooks(ctx, hooks.AfterImplementer, round, nil)
+	if err != nil {
+		return round, err
 	}
-	o.notifyFighterFinish(o.claude.Name(), claudeDuration)
 
 	// Get git diff
-	if o.logger != nil {
-		o.logger.FighterAction("Capturing git diff...")
-	}
-	o.notifyFighterAction("Claude Code", "Capturing git diff...")
+	o.publish(events.FighterAction{Fighter: implementerName, Action: "Capturing git diff..."})
 
 	// Stage all changes first to capture everything
 	if err := o.git.StageAll(); err != nil {
-		return nil, fmt.Errorf("failed to stage changes: %w", err)
+		return round, fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	diff, err := o.stagedDiff()
+	if err != nil {
+		return round, fmt.Errorf("failed to get git diff: %w", err)
 	}
 
-	diff, err := o.git.GetStagedDiff()
+	// Changes breaking the guardrails are reverted, unless the user keeps them
+	violations, err := o.checkGuardrails()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get git diff: %w", err)
+		return round, err
+	}
+	round.Violations = violations
+	reverted := guard.Reverted(violations)
+	if reverted {
+		if diff, err = o.stagedDiff(); err != nil {
+			return round, fmt.Errorf("failed to get git diff: %w", err)
+		}
 	}
 
 	round.GitDiff = diff
 
-	// Log the git diff
-	if o.logger != nil {
-		o.logger.GitDiff(diff)
+	// Record the tree after this round so the session can be rolled back to it
+	sha, err := o.git.Snapshot(session.SnapshotRef(o.sessionID, number),
+		fmt.Sprintf("mortal-prompter: session %s round %d", o.sessionID, number))
+	if err != nil {
+		o.warn(fmt.Errorf("failed to snapshot round %d: %w", number, err))
+	} else {
+		round.SnapshotSHA = sha
 	}
 
-	// Check if there are any changes
-	if strings.TrimSpace(diff) == "" {
-		if o.logger != nil {
-			o.logger.Info("No changes detected in this round")
+	o.publish(events.DiffCaptured{Diff: diff})
+
+	// Changes reverted by the guardrails go straight back to the implementer without a review
+	if reverted {
+		round.Outcome = types.OutcomeRejected
+		round.HasIssues = true
+		round.Issues = guard.Issues(violations)
+		round.Findings = guard.Findings(violations)
+		for i := range round.Findings {
+			round.Findings[i].Reviewer = guardrailName
 		}
-		// If no changes, we consider it as no issues (nothing to review)
-		round.HasIssues = false
+		o.addHookIssues(round, hookResults)
 		round.Duration = time.Since(roundStart)
 		return round, nil
 	}
 
-	// Log changes detected
-	fileCount := countFilesInDiff(diff)
-	if o.logger != nil {
-		o.logger.ChangesDetected(fileCount)
+	if !hooks.Vetoed(hookResults) {
+		diffResults, err := o.runHooks(ctx, hooks.AfterDiff, round, nil)
+		if err != nil {
+			return round, err
+		}
+		hookResults = append(hookResults, diffResults...)
 	}
-	o.notifyChangesDetected(fileCount)
 
-	// Execute Codex review
-	if o.logger != nil {
-		o.logger.FighterEnter(o.codex.Name())
-		o.logger.FighterAction("Codex reviewing changes...")
-		o.logger.CLIInput(o.codex.Name(), "codex review --uncommitted")
+	// Changes rejected by a hook go straight back to the implementer without a review
+	if hooks.Vetoed(hookResults) {
+		round.Outcome = types.OutcomeRejected
+		o.addHookIssues(round, hookResults)
+		round.Duration = time.Since(roundStart)
+		return round, nil
 	}
-	o.notifyFighterEnter(o.codex.Name())
-	o.notifyFighterAction("Codex", "Reviewing changes...")
 
-	codexStart := time.Now()
-	reviewResult, err := o.codex.Review(ctx, diff)
-	codexDuration := time.Since(codexStart)
+	// Failing builds or tests go straight back to the implementer without a review
+	if o.verifier.Enabled() {
+		results, err := o.runVerification(ctx, implementerName)
+		if err != nil {
+			return round, err
+		}
+		round.Verification = results
+		if !verify.Passed(results) {
+			round.Outcome = types.OutcomeRejected
+			round.HasIssues = true
+			round.Issues = verify.Issues(results)
+			round.Findings = verify.Findings(results)
+			for i := range round.Findings {
+				round.Findings[i].Reviewer = verificationName
+			}
+			o.addHookIssues(round, hookResults)
+			round.Duration = time.Since(roundStart)
+			return round, nil
+		}
+	}
 
+	// Check if there are any changes
+	changed, err := o.changedInRound(diff)
 	if err != nil {
-		return nil, fmt.Errorf("codex review failed: %w", err)
+		return round, fmt.Errorf("failed to get the diff of the round: %w", err)
+	}
+	if !changed {
+		o.info("No changes detected in this round")
+		round.Outcome = types.OutcomeNoChanges
+		o.addHookIssues(round, hookResults)
+		round.Duration = time.Since(roundStart)
+		return round, nil
 	}
 
-	if o.logger != nil {
-		o.logger.CLIOutput(o.codex.Name(), reviewResult.RawOutput)
-		o.logger.FighterFinish(o.codex.Name(), codexDuration)
+	fileCount := countFilesInDiff(diff)
+	o.publish(events.ChangesDetected{Files: fileCount})
+
+	// Execute review
+	o.publish(events.FighterEnter{Fighter: reviewerName})
+	o.publish(events.FighterAction{Fighter: reviewerName, Action: "Reviewing changes..."})
+	o.publish(events.FighterInput{Fighter: reviewerName, Input: fmt.Sprintf("review of staged changes (%d file(s))", fileCount)})
+
+	// The reviewers check the issues of earlier rounds along with the diff
+	ledger := types.NewLedger(o.rounds)
+
+	reviewerStart := time.Now()
+	panelResult, err := o.review(ctx, types.ReviewRequest{
+		Prompt:     o.config.Prompt,
+		Round:      number,
+		Diff:       diff,
+		Plan:       o.planText(),
+		OpenIssues: ledger.Open(),
+	})
+	reviewerDuration := time.Since(reviewerStart)
+
+	if err != nil {
+		round.Outcome = types.OutcomeReviewerError
+		round.Error = err.Error()
+		round.Duration = time.Since(roundStart)
+		return round, err
 	}
-	o.notifyFighterFinish(o.codex.Name(), codexDuration)
 
-	round.CodexReview = reviewResult.RawOutput
-	round.HasIssues = reviewResult.HasIssues
-	round.Issues = reviewResult.Issues
+	for _, r := range panelResult.Reviews {
+		o.publish(events.FighterOutput{Fighter: r.Reviewer, Output: r.RawOutput})
+	}
+	o.publish(events.FighterFinish{Fighter: reviewerName, Duration: reviewerDuration})
+	o.publish(events.ReviewVerdicts{Reviews: panelResult.Reviews, Verdicts: panelResult.Verdicts})
+	o.publish(events.UsageUpdated{Total: o.totalUsage().Add(round.Usage).Add(panelResult.Usage)})
+
+	round.ReviewerOutput = combinedReviewOutput(panelResult.Reviews)
+	round.HasIssues = panelResult.HasIssues
+	round.Issues = panelResult.Issues
+	round.Findings = panelResult.Findings
+	round.Reviews = panelResult.Reviews
+	round.Verdicts = panelResult.Verdicts
+	round.Chunks = panelResult.Chunks
+	round.Usage = round.Usage.Add(panelResult.Usage)
+	round.Outcome = types.OutcomeReviewed
+	o.carryOpenIssues(round, ledger)
+
+	// Hooks may fail the round, or raise issues the reviewers missed
+	reviewResults, err := o.runHooks(ctx, hooks.AfterReview, round, nil)
+	if err != nil {
+		return round, err
+	}
+	o.addHookIssues(round, append(hookResults, reviewResults...))
 	round.Duration = time.Since(roundStart)
 
 	return round, nil
 }
 
+// runVerification runs the verification gate on the implementer's changes.
+func (o *Orchestrator) runVerification(ctx context.Context, implementerName string) ([]types.VerificationResult, error) {
+	o.publish(events.FighterAction{Fighter: implementerName, Action: "Running verification..."})
+
+	results, err := o.verifier.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("verification interrupted: %w", err)
+	}
+
+	for _, r := range results {
+		o.publish(events.FighterOutput{Fighter: verificationName + ": " + r.Command, Output: r.Output})
+	}
+	o.publish(events.Verification{Results: results})
+
+	return results, nil
+}
+
+// recordInterrupted keeps what an interrupted round got done: its prompt, the
+// output the implementer produced so far and the changes left in the tree.
+func (o *Orchestrator) recordInterrupted(round *types.Round) {
+	if round == nil {
+		return
+	}
+
+	if round.GitDiff == "" {
+		if err := o.git.StageAll(); err != nil {
+			o.warn(fmt.Errorf("failed to stage the changes of round %d: %w", round.Number, err))
+		} else if diff, err := o.stagedDiff(); err == nil {
+			round.GitDiff = diff
+		}
+	}
+
+	round.Outcome = types.OutcomeInterrupted
+	round.Duration = time.Since(round.Timestamp)
+	o.recordRound(round)
+}
+
+// recordRound adds round to the rounds of the session.
+func (o *Orchestrator) recordRound(round *types.Round) {
+	o.rounds = append(o.rounds, *round)
+	o.publish(events.RoundComplete{Round: round.Number, Outcome: round.Outcome, Error: round.Error})
+}
+
 // buildResult constructs the final SessionResult.
 func (o *Orchestrator) buildResult(success bool) *types.SessionResult {
 	result := &types.SessionResult{
+		SessionID:     o.sessionID,
 		Success:       success,
+		State:         o.state,
+		StopReason:    o.stopReason,
+		Branch:        o.branch,
 		TotalRounds:   len(o.rounds),
 		TotalDuration: time.Since(o.startTime),
+		Usage:         o.totalUsage(),
 		Rounds:        o.rounds,
+		Plan:          o.plan,
+		Ledger:        types.NewLedger(o.rounds).Entries(),
+		Hooks:         append([]types.HookResult(nil), o.sessionHooks...),
+	}
+
+	if o.implementer != nil {
+		result.Implementer = o.implementer.Name()
+	}
+	if o.config != nil {
+		result.PathRules = o.config.Paths
+	}
+	if o.panel != nil {
+		result.Reviewer = o.panel.Name()
+		result.FailOn = o.panel.FailOn()
+		if len(o.panel.Reviewers()) > 1 {
+			result.ReviewPolicy = o.panel.Policy().String()
+		}
 	}
 
 	// Get final diff (all changes combined)
-	if diff, err := o.git.GetStagedDiff(); err == nil {
+	if diff, err := o.stagedDiff(); err == nil {
 		result.FinalDiff = diff
 	}
 
+	if excluded, err := o.git.ExcludedChanges(); err == nil {
+		result.ExcludedPaths = excluded
+	}
+
 	// Extract modified files from rounds
 	filesMap := make(map[string]bool)
 	for _, round := range o.rounds {
@@ -351,9 +916,39 @@ func (o *Orchestrator) buildResult(success bool) *types.SessionResult {
 	return result
 }
 
+// saveCheckpoint persists the completed rounds and the issues pending for the next round.
+// Failures are logged but never interrupt the battle.
+func (o *Orchestrator) saveCheckpoint(pendingIssues []string) {
+	if o.store == nil {
+		return
+	}
+
+	cp := &session.Checkpoint{
+		ID:            o.sessionID,
+		State:         o.state,
+		Prompt:        o.config.Prompt,
+		ImagePath:     o.imagePath,
+		Settings:      session.SettingsFromConfig(o.config),
+		Plan:          o.plan,
+		Rounds:        o.rounds,
+		PendingIssues: pendingIssues,
+		StartedAt:     o.sessionStart,
+		Branch:        o.branch,
+		WorktreePath:  o.worktreePath,
+		Baseline:      o.baseline,
+		KeptChanges:   o.keptChanges,
+		BaselineIndex: o.baselineIndex,
+		Stash:         o.stash,
+	}
+
+	if err := o.store.Save(cp); err != nil {
+		o.warn(fmt.Errorf("failed to save checkpoint: %w", err))
+	}
+}
+
 // autoCommit creates a git commit with the configured message.
 func (o *Orchestrator) autoCommit() error {
-	o.logger.Info("Auto-committing changes...")
+	o.info("Auto-committing changes...")
 
 	message := fmt.Sprintf("%s\n\nMortal Prompter session:\n- Rounds: %d\n- Duration: %s",
 		o.config.CommitMessage,
@@ -361,64 +956,105 @@ func (o *Orchestrator) autoCommit() error {
 		time.Since(o.startTime).Round(time.Second),
 	)
 
-	if err := o.git.Commit(message); err != nil {
+	// Leave the changes the session started on out of the commit
+	commit := o.git.Commit
+	if o.keptChanges {
+		commit = func(message string) error { return o.git.CommitSince(o.baseline, o.baselineIndex, message) }
+	}
+
+	if err := commit(message); err != nil {
 		if err == git.ErrNoChanges {
-			o.logger.Info("No changes to commit")
+			o.info("No changes to commit")
 			return nil
 		}
 		return err
 	}
 
-	o.logger.Info("Changes committed successfully")
+	o.info("Changes committed successfully")
 	return nil
 }
 
-// promptContinue asks the user if they want to continue after max iterations.
-func (o *Orchestrator) promptContinue() bool {
-	// If observer is set, use it for confirmation
-	if o.observer != nil {
-		msg := fmt.Sprintf("Maximum iterations (%d) reached. Continue?", o.config.MaxIterations)
-		return o.observer.OnConfirmationRequired(msg)
+// continuePastMaxIterations decides by the --on-max-iterations policy whether
+// to run another round past the maximum, setting the stop reason if not.
+func (o *Orchestrator) continuePastMaxIterations() bool {
+	policy, err := config.ParseMaxIterationsPolicy(o.config.OnMaxIterations)
+	if err != nil {
+		o.warn(err)
+		policy = config.MaxIterationsPolicy{Action: config.MaxIterationsAbort}
+	}
+
+	switch policy.Action {
+	case config.MaxIterationsContinue:
+		if o.currentRound <= o.config.MaxIterations+policy.Rounds {
+			return true
+		}
+		o.stopReason = fmt.Sprintf("Reached the maximum of %d iterations and %d extra round(s)", o.config.MaxIterations, policy.Rounds)
+		return false
+	case config.MaxIterationsAsk:
+		o.state = types.StateWaitingConfirmation
+		if o.promptContinue() {
+			o.state = types.StateRunning
+			return true
+		}
+		o.stopReason = fmt.Sprintf("Aborted by user after reaching the maximum of %d iterations", o.config.MaxIterations)
+		return false
+	default:
+		o.stopReason = fmt.Sprintf("Reached the maximum of %d iterations", o.config.MaxIterations)
+		return false
 	}
+}
 
-	// Otherwise use terminal prompt
-	fmt.Printf("\n⚠️  Maximum iterations (%d) reached.\n", o.config.MaxIterations)
-	fmt.Print("Continue for another round? [y/N]: ")
+// promptContinue asks the user if they want to continue after max iterations.
+func (o *Orchestrator) promptContinue() bool {
+	return o.confirm(fmt.Sprintf("Maximum iterations (%d) reached. Continue for another round?", o.config.MaxIterations))
+}
 
-	return readYesNo()
+// confirm asks the user a yes/no question (default: no).
+func (o *Orchestrator) confirm(message string) bool {
+	return ask(o.confirmer, o.bus, confirm.Question{Message: message, SessionID: o.sessionID})
 }
 
 // promptNextRound asks the user if they want to proceed with the next round (interactive mode).
 func (o *Orchestrator) promptNextRound() bool {
-	// If observer is set, use it for confirmation
-	if o.observer != nil {
-		return o.observer.OnConfirmationRequired("Proceed to next round?")
-	}
-
-	// Otherwise use terminal prompt
-	fmt.Print("\nProceed to next round? [Y/n]: ")
-	return readYesNoDefault(true)
+	return ask(o.confirmer, o.bus, confirm.Question{Message: "Proceed to next round?", Default: true, SessionID: o.sessionID})
 }
 
-// readYesNo reads a yes/no response from stdin (default: no).
-func readYesNo() bool {
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes"
+// ask asks confirmer a yes/no question and publishes the answer on bus. The
+// question's default applies when confirmer cannot answer.
+func ask(confirmer confirm.Confirmer, bus *events.Bus, q confirm.Question) bool {
+	confirmed, err := confirmer.Confirm(q)
+	if err != nil {
+		confirmed = q.Default
+		bus.Publish(events.Warning{Err: fmt.Errorf("no answer to %q, assuming %s: %w", q.Message, yesNo(confirmed), err)})
+	}
+	bus.Publish(events.ConfirmationRequired{Message: q.Message, Confirmed: confirmed})
+	return confirmed
 }
 
-// readYesNoDefault reads a yes/no response with a configurable default.
-func readYesNoDefault(defaultYes bool) bool {
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
+// yesNo returns "yes" or "no" for answer.
+func yesNo(answer bool) string {
+	if answer {
+		return "yes"
+	}
+	return "no"
+}
 
-	if response == "" {
-		return defaultYes
+// combinedReviewOutput joins the raw output of every review, labelling each
+// one with its reviewer when there is more than one.
+func combinedReviewOutput(reviews []types.ReviewResult) string {
+	if len(reviews) == 1 {
+		return reviews[0].RawOutput
 	}
 
-	return response == "y" || response == "yes"
+	var sb strings.Builder
+	for i, r := range reviews {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("=== %s ===\n", r.Reviewer))
+		sb.WriteString(r.RawOutput)
+	}
+	return sb.String()
 }
 
 // countFilesInDiff counts the number of files in a git diff.
@@ -465,58 +1101,17 @@ func (o *Orchestrator) GetRounds() []types.Round {
 	return o.rounds
 }
 
-// Observer notification methods
-
-func (o *Orchestrator) notifyRoundStart(number int) {
-	if o.observer != nil {
-		o.observer.OnRoundStart(number)
-	}
+// publish delivers e to the subscribers of the session's events.
+func (o *Orchestrator) publish(e events.Event) {
+	o.bus.Publish(e)
 }
 
-func (o *Orchestrator) notifyFighterEnter(fighter string) {
-	if o.observer != nil {
-		o.observer.OnFighterEnter(fighter)
-	}
+// info publishes a progress message.
+func (o *Orchestrator) info(message string) {
+	o.publish(events.Info{Message: message})
 }
 
-func (o *Orchestrator) notifyFighterAction(fighter, action string) {
-	if o.observer != nil {
-		o.observer.OnFighterAction(fighter, action)
-	}
-}
-
-func (o *Orchestrator) notifyFighterFinish(fighter string, duration time.Duration) {
-	if o.observer != nil {
-		o.observer.OnFighterFinish(fighter, duration)
-	}
-}
-
-func (o *Orchestrator) notifyChangesDetected(fileCount int) {
-	if o.observer != nil {
-		o.observer.OnChangesDetected(fileCount)
-	}
-}
-
-func (o *Orchestrator) notifyIssuesFound(issues []string) {
-	if o.observer != nil {
-		o.observer.OnIssuesFound(issues)
-	}
-}
-
-func (o *Orchestrator) notifyNoIssues() {
-	if o.observer != nil {
-		o.observer.OnNoIssues()
-	}
-}
-
-func (o *Orchestrator) notifySessionComplete(result *types.SessionResult, success bool) {
-	if o.observer != nil {
-		o.observer.OnSessionComplete(result, success)
-	}
-}
-
-func (o *Orchestrator) notifyError(err error) {
-	if o.observer != nil {
-		o.observer.OnError(err)
-	}
+// warn publishes an error that does not end the session.
+func (o *Orchestrator) warn(err error) {
+	o.publish(events.Warning{Err: err})
 }
@@ -1,9 +1,21 @@
 package orchestrator
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
 	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/internal/session"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
@@ -236,3 +248,491 @@ func TestBuildResultFailure(t *testing.T) {
 		t.Errorf("buildResult() TotalRounds = %d, want 1", result.TotalRounds)
 	}
 }
+
+func TestNewUsesSelectedFighters(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.Implementer = fighters.FighterTypeGemini
+	cfg.Reviewers = []fighters.FighterType{fighters.FighterTypeClaude}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if orch.ImplementerName() != "GEMINI" {
+		t.Errorf("ImplementerName() = %q, want GEMINI", orch.ImplementerName())
+	}
+	if orch.ReviewerName() != "CLAUDE CODE" {
+		t.Errorf("ReviewerName() = %q, want CLAUDE CODE", orch.ReviewerName())
+	}
+
+	result := orch.buildResult(false)
+	if result.Implementer != "GEMINI" || result.Reviewer != "CLAUDE CODE" {
+		t.Errorf("buildResult() fighters = %q vs %q, want GEMINI vs CLAUDE CODE", result.Implementer, result.Reviewer)
+	}
+}
+
+func TestNewUnknownFighter(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.Reviewers = []fighters.FighterType{fighters.FighterType("skynet")}
+
+	if _, err := New(cfg, nil); err == nil {
+		t.Error("New() with unknown reviewer should return an error")
+	}
+}
+
+func TestNewReviewPanel(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.Reviewers = []fighters.FighterType{fighters.FighterTypeCodex, fighters.FighterTypeGemini}
+	cfg.ReviewPolicy = "quorum:2"
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if orch.ReviewerName() != "CODEX + GEMINI" {
+		t.Errorf("ReviewerName() = %q, want CODEX + GEMINI", orch.ReviewerName())
+	}
+
+	result := orch.buildResult(false)
+	if result.ReviewPolicy != "quorum:2" {
+		t.Errorf("buildResult() ReviewPolicy = %q, want quorum:2", result.ReviewPolicy)
+	}
+}
+
+func TestNewReviewPanelQuorumTooLarge(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.Reviewers = []fighters.FighterType{fighters.FighterTypeCodex}
+	cfg.ReviewPolicy = "quorum:2"
+
+	if _, err := New(cfg, nil); err == nil {
+		t.Error("New() with a quorum larger than the panel should return an error")
+	}
+}
+
+func TestCombinedReviewOutput(t *testing.T) {
+	single := []types.ReviewResult{{Reviewer: "CODEX", RawOutput: "LGTM"}}
+	if got := combinedReviewOutput(single); got != "LGTM" {
+		t.Errorf("combinedReviewOutput() single = %q, want LGTM", got)
+	}
+
+	panel := []types.ReviewResult{
+		{Reviewer: "CODEX", RawOutput: "LGTM"},
+		{Reviewer: "GEMINI", RawOutput: "ISSUES_FOUND"},
+	}
+	got := combinedReviewOutput(panel)
+	if !strings.Contains(got, "=== CODEX ===\nLGTM") || !strings.Contains(got, "=== GEMINI ===\nISSUES_FOUND") {
+		t.Errorf("combinedReviewOutput() panel = %q", got)
+	}
+}
+
+func TestResumeRestoresCheckpoint(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cp := &session.Checkpoint{
+		ID:            "resumed-session",
+		Prompt:        "implement feature X",
+		Rounds:        []types.Round{{Number: 1, HasIssues: true}, {Number: 2, HasIssues: true}},
+		PendingIssues: []string{"still broken"},
+	}
+	orch.Resume(cp)
+
+	if orch.SessionID() != "resumed-session" {
+		t.Errorf("SessionID() = %q, want resumed-session", orch.SessionID())
+	}
+	if orch.GetCurrentRound() != 2 {
+		t.Errorf("GetCurrentRound() = %d, want 2", orch.GetCurrentRound())
+	}
+	if len(orch.GetRounds()) != 2 {
+		t.Errorf("GetRounds() returned %d rounds, want 2", len(orch.GetRounds()))
+	}
+	if cfg.Prompt != "implement feature X" {
+		t.Errorf("config prompt = %q, want implement feature X", cfg.Prompt)
+	}
+	if len(orch.pendingIssues) != 1 || orch.pendingIssues[0] != "still broken" {
+		t.Errorf("pendingIssues = %v, want [still broken]", orch.pendingIssues)
+	}
+}
+
+func TestSaveCheckpoint(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add tests"
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.state = types.StateRunning
+	orch.rounds = []types.Round{{Number: 1, HasIssues: true, Issues: []string{"no tests"}}}
+
+	orch.saveCheckpoint([]string{"no tests"})
+
+	cp, err := session.NewStore(cfg.OutputDir).Load(orch.SessionID())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cp.Prompt != "add tests" || cp.State != types.StateRunning {
+		t.Errorf("checkpoint prompt/state = %q/%s", cp.Prompt, cp.State)
+	}
+	if cp.NextRound() != 2 || len(cp.PendingIssues) != 1 {
+		t.Errorf("checkpoint next round = %d, pending = %v", cp.NextRound(), cp.PendingIssues)
+	}
+}
+
+// newTestRepo creates a git repository with one commit and returns its path.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@mortal-prompter.local"},
+		{"config", "user.name", "Test User"},
+		{"commit", "--allow-empty", "-m", "initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+// fileImplementer is an Implementer that writes a file on every execution.
+type fileImplementer struct {
+	dir string
+}
+
+func (f *fileImplementer) Name() string { return "STUB" }
+
+func (f *fileImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	return "done", os.WriteFile(filepath.Join(f.dir, "feature.txt"), []byte(prompt), 0644)
+}
+
+func (f *fileImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt + strings.Join(previousIssues, "\n")
+}
+
+// countingReviewer is a Reviewer that approves everything and counts its calls.
+type countingReviewer struct {
+	calls int
+}
+
+func (c *countingReviewer) Name() string { return "COUNTER" }
+
+func (c *countingReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	c.calls++
+	return &types.ReviewResult{RawOutput: "LGTM"}, nil
+}
+
+func TestExecuteRoundVerificationGate(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("verification commands need a POSIX shell")
+	}
+
+	tests := []struct {
+		name        string
+		command     string
+		wantIssues  bool
+		wantReviews int
+	}{
+		{"failing gate skips the review", "echo 'build broken' >&2; exit 2", true, 0},
+		{"passing gate goes to review", "true", false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.Verify = []string{tt.command}
+
+			orch, err := New(cfg, nil)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			reviewer := &countingReviewer{}
+			orch.implementer = &fileImplementer{dir: dir}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			round, err := orch.executeRound(context.Background(), 1, "add feature", nil)
+			if err != nil {
+				t.Fatalf("executeRound() error = %v", err)
+			}
+
+			if round.HasIssues != tt.wantIssues {
+				t.Errorf("HasIssues = %v, want %v (issues: %v)", round.HasIssues, tt.wantIssues, round.Issues)
+			}
+			if reviewer.calls != tt.wantReviews {
+				t.Errorf("reviewer called %d times, want %d", reviewer.calls, tt.wantReviews)
+			}
+			if len(round.Verification) != 1 || round.Verification[0].Command != tt.command {
+				t.Fatalf("Verification = %+v, want one result for %q", round.Verification, tt.command)
+			}
+			if tt.wantIssues {
+				if round.Verification[0].ExitCode != 2 {
+					t.Errorf("ExitCode = %d, want 2", round.Verification[0].ExitCode)
+				}
+				if len(round.Issues) != 1 || !strings.Contains(round.Issues[0], "build broken") {
+					t.Errorf("Issues = %v, want the command output", round.Issues)
+				}
+				if len(round.Findings) != 1 || round.Findings[0].Severity != types.SeverityCritical {
+					t.Errorf("Findings = %+v, want one critical issue", round.Findings)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteRoundFailOn(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.FailOn = types.SeverityHigh
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if orch.panel.FailOn() != types.SeverityHigh {
+		t.Errorf("panel fail-on = %q, want high", orch.panel.FailOn())
+	}
+
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &nitpickReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orch.panel.SetFailOn(cfg.FailOn)
+
+	round, err := orch.executeRound(context.Background(), 1, "add feature", nil)
+	if err != nil {
+		t.Fatalf("executeRound() error = %v", err)
+	}
+	if round.HasIssues {
+		t.Errorf("HasIssues = true, want the round to pass with only medium issues (issues: %v)", round.Issues)
+	}
+	if len(round.Findings) != 1 || round.Findings[0].Severity != types.SeverityMedium {
+		t.Errorf("Findings = %+v, want the accepted medium issue", round.Findings)
+	}
+}
+
+func TestRunPublishesEvents(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+
+	observer := &answerObserver{}
+	orch, err := NewWithObserver(cfg, nil, observer)
+	if err != nil {
+		t.Fatalf("NewWithObserver() error = %v", err)
+	}
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Any number of subscribers get the same events
+	var async []string
+	orch.Events().Subscribe(events.SubscriberFunc(func(e events.Event) {
+		async = append(async, e.Type())
+	}))
+
+	if _, err := orch.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	orch.Events().Close()
+
+	var got []string
+	for _, e := range observer.published {
+		got = append(got, e.Type())
+	}
+	want := []string{
+		events.TypeSessionStart, events.TypeRoundStart,
+		events.TypeFighterEnter, events.TypeFighterAction, events.TypeFighterInput, events.TypeUsage,
+		events.TypeFighterOutput, events.TypeFighterFinish,
+		events.TypeFighterAction, events.TypeDiffCaptured, events.TypeChangesDetected,
+		events.TypeFighterEnter, events.TypeFighterAction, events.TypeFighterInput,
+		events.TypeFighterOutput, events.TypeFighterFinish, events.TypeReviewVerdicts, events.TypeUsage, events.TypeRoundComplete,
+		events.TypeNoIssues, events.TypeSessionComplete,
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("events = %v\nwant %v", got, want)
+	}
+	if strings.Join(async, " ") != strings.Join(got, " ") {
+		t.Errorf("asynchronous subscriber got %v, want %v", async, got)
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if orch.verifier.Enabled() {
+		t.Error("verification should be disabled without --verify commands")
+	}
+
+	cfg.Verify = []string{"make test"}
+	orch, err = New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := orch.verifier.Commands(); len(got) != 1 || got[0] != "make test" {
+		t.Errorf("verifier commands = %v, want [make test]", got)
+	}
+}
+
+func TestRunMaxIterationsPolicy(t *testing.T) {
+	failing := confirm.Func(func(q confirm.Question) (bool, error) {
+		return true, errors.New("approval endpoint unreachable")
+	})
+
+	tests := []struct {
+		name       string
+		policy     string
+		answers    []bool
+		confirmer  confirm.Confirmer
+		wantRounds int
+		wantAsked  int
+	}{
+		{"abort", config.MaxIterationsAbort, nil, nil, 2, 0},
+		{"continue for extra rounds", "continue:2", nil, nil, 4, 0},
+		{"ask until declined", config.MaxIterationsAsk, []bool{true, false}, nil, 3, 2},
+		{"unanswered question takes its default", config.MaxIterationsAsk, nil, failing, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			cfg := config.New()
+			cfg.WorkDir = dir
+			cfg.OutputDir = t.TempDir()
+			cfg.MaxIterations = 2
+			cfg.OnMaxIterations = tt.policy
+			cfg.OnStall = config.StallActionStop
+
+			observer := &answerObserver{answers: tt.answers}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			if tt.confirmer != nil {
+				orch.SetConfirmer(tt.confirmer)
+			}
+			orch.implementer = &fileImplementer{dir: dir}
+			orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &nitpickReviewer{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := orch.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result.TotalRounds != tt.wantRounds || result.State != types.StateAborted {
+				t.Errorf("ran %d round(s) ending %s, want %d ending aborted (%s)",
+					result.TotalRounds, result.State, tt.wantRounds, result.StopReason)
+			}
+			if len(observer.messages) != tt.wantAsked {
+				t.Errorf("asked %d times, want %d", len(observer.messages), tt.wantAsked)
+			}
+		})
+	}
+}
+
+// blockingImplementer is an Implementer that writes a file, then works until
+// it is interrupted.
+type blockingImplementer struct {
+	dir     string
+	started chan struct{}
+}
+
+func (b *blockingImplementer) Name() string { return "BLOCKER" }
+
+func (b *blockingImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	if err := os.WriteFile(filepath.Join(b.dir, "half.txt"), []byte("half done"), 0644); err != nil {
+		return "", err
+	}
+	close(b.started)
+	<-ctx.Done()
+	return "wrote half.txt", ctx.Err()
+}
+
+func (b *blockingImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+func TestRunInterruptedRound(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add feature"
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	implementer := &blockingImplementer{dir: dir, started: make(chan struct{})}
+	orch.implementer = implementer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-implementer.started
+		cancel()
+	}()
+
+	result, err := orch.Run(ctx)
+	if err == nil {
+		t.Fatal("Run() error = nil, want the interruption")
+	}
+	if result == nil || result.State != types.StateInterrupted {
+		t.Fatalf("result = %+v, want an interrupted session", result)
+	}
+	if len(result.Rounds) != 1 {
+		t.Fatalf("got %d rounds, want the interrupted round recorded", len(result.Rounds))
+	}
+
+	round := result.Rounds[0]
+	if round.Outcome != types.OutcomeInterrupted || round.ImplementerPrompt != "add feature" || round.ImplementerOutput != "wrote half.txt" {
+		t.Errorf("round = %+v, want the prompt and output so far", round)
+	}
+	if !strings.Contains(round.GitDiff, "half.txt") {
+		t.Errorf("GitDiff = %q, want the changes made so far", round.GitDiff)
+	}
+
+	// The checkpoint keeps it, so a resumed session starts the next round
+	cp, err := session.NewStore(cfg.OutputDir).Load(result.SessionID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cp.Rounds) != 1 || cp.Rounds[0].Outcome != types.OutcomeInterrupted {
+		t.Errorf("checkpoint rounds = %+v, want the interrupted round", cp.Rounds)
+	}
+}
@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/git"
+)
+
+// newGit returns the git operations in workDir, staging and diffing the paths
+// kept by the project's path rules and never the output directory.
+func newGit(cfg *config.Config, workDir string) *git.Git {
+	g := git.New(workDir)
+	exclude := append([]string(nil), cfg.Paths.Exclude...)
+	if dir := outputPath(workDir, cfg.OutputDir); dir != "" {
+		exclude = append(exclude, dir)
+	}
+	g.SetPathRules(cfg.Paths.Include, exclude)
+	return g
+}
+
+// ignoreOutputDir has git ignore the logs, reports and images written to the
+// output directory, so that they do not show as changes in the repository.
+func (o *Orchestrator) ignoreOutputDir() {
+	dir := outputPath(o.repo.WorkDir(), o.config.OutputDir)
+	if dir == "" {
+		return
+	}
+	if err := o.repo.ExcludeLocally(dir); err != nil {
+		o.warn(fmt.Errorf("failed to exclude %s from git: %w", dir, err))
+	}
+}
+
+// outputPath returns the output directory relative to workDir, or an empty
+// string if it is not inside it.
+func outputPath(workDir, outputDir string) string {
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return ""
+	}
+	outputDir, err = filepath.Abs(outputDir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(workDir, outputDir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
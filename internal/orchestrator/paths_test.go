@@ -0,0 +1,173 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// generatingImplementer is an Implementer that writes a source file and
+// regenerates a lock file.
+type generatingImplementer struct {
+	dir string
+}
+
+func (g *generatingImplementer) Name() string { return "GENERATOR" }
+
+func (g *generatingImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	for name, content := range map[string]string{"feature.go": "package feature\n", "web/package-lock.json": "{}\n"} {
+		path := filepath.Join(g.dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	return "done", nil
+}
+
+func (g *generatingImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+func TestRunPathRules(t *testing.T) {
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = filepath.Join(dir, ".mortal-prompter")
+	cfg.Prompt = "add feature"
+	cfg.AutoCommit = true
+	cfg.Paths = types.PathRules{Exclude: []string{"**/package-lock.json"}}
+	if err := cfg.EnsureOutputDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.OutputDir, "session.log"), []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = &generatingImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	diff := result.Rounds[0].GitDiff
+	if !strings.Contains(diff, "feature.go") || strings.Contains(diff, "package-lock.json") || strings.Contains(diff, "session.log") {
+		t.Errorf("round diff = %q, want only feature.go", diff)
+	}
+	if want := []string{"web/package-lock.json"}; !reflect.DeepEqual(result.ExcludedPaths, want) {
+		t.Errorf("ExcludedPaths = %v, want %v", result.ExcludedPaths, want)
+	}
+	if !reflect.DeepEqual(result.PathRules, cfg.Paths) {
+		t.Errorf("PathRules = %+v, want %+v", result.PathRules, cfg.Paths)
+	}
+
+	committed := gitOutput(t, dir, "show", "--name-only", "--format=", "HEAD")
+	if strings.TrimSpace(committed) != "feature.go" {
+		t.Errorf("committed files = %q, want feature.go", committed)
+	}
+
+	// The output directory is ignored by git from now on
+	if status := gitOutput(t, dir, "status", "--porcelain"); strings.Contains(status, ".mortal-prompter") {
+		t.Errorf("status = %q, want the output directory ignored", status)
+	}
+}
+
+func TestRunPathRulesCodexReviewer(t *testing.T) {
+	codexArgs := fakeCodex(t)
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = filepath.Join(dir, ".mortal-prompter")
+	cfg.Prompt = "add feature"
+	cfg.Paths = types.PathRules{Exclude: []string{"**/package-lock.json"}}
+	if err := cfg.EnsureOutputDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orch.implementer = &generatingImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, fighters.NewCodex(dir, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := orch.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The excluded lock file never reaches the reviewer
+	args := codexArgs()
+	if !strings.Contains(args, "feature.go") || strings.Contains(args, "package-lock.json") {
+		t.Errorf("codex args = %q, want only feature.go reviewed", args)
+	}
+}
+
+func TestReviewChangesPathRulesCodexReviewer(t *testing.T) {
+	codexArgs := fakeCodex(t)
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Paths = types.PathRules{Exclude: []string{"**/package-lock.json"}}
+
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := (&generatingImplementer{dir: dir}).Execute(context.Background(), "", ""); err != nil {
+		t.Fatal(err)
+	}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, fighters.NewCodex(dir, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := orch.ReviewChanges(context.Background(), ReviewTarget{}); err != nil {
+		t.Fatalf("ReviewChanges() error = %v", err)
+	}
+
+	// Codex reviews the filtered diff, not the whole working tree
+	args := codexArgs()
+	if strings.Contains(args, "--uncommitted") || !strings.Contains(args, "feature.go") || strings.Contains(args, "package-lock.json") {
+		t.Errorf("codex args = %q, want only feature.go reviewed", args)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	tests := []struct {
+		workDir, outputDir, want string
+	}{
+		{"/repo", "/repo/.mortal-prompter", ".mortal-prompter"},
+		{"/repo", "/repo/build/logs", "build/logs"},
+		{"/repo", "/tmp/logs", ""},
+		{"/repo", "/repo", ""},
+		{"/repo/sub", "/repo/.mortal-prompter", ""},
+	}
+	for _, tt := range tests {
+		if got := outputPath(tt.workDir, tt.outputDir); got != tt.want {
+			t.Errorf("outputPath(%q, %q) = %q, want %q", tt.workDir, tt.outputDir, got, tt.want)
+		}
+	}
+}
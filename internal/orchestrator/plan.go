@@ -0,0 +1,182 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/prompts"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// planLabel matches the "PLAN:" label planners tend to put before the plan.
+const planLabel = "PLAN:"
+
+// buildPlanPrompt asks the planner to turn the task into a numbered plan.
+func buildPlanPrompt(t *prompts.Templates, task string) string {
+	return t.Render(prompts.Plan, prompts.Data{Prompt: task})
+}
+
+// buildPlanRevisionPrompt asks the planner to revise its plan with the reviewers' critique.
+func buildPlanRevisionPrompt(t *prompts.Templates, task, plan string, critique []string) string {
+	return t.Render(prompts.PlanRevision, prompts.Data{Prompt: task, Plan: plan, Issues: critique})
+}
+
+// extractPlan returns the plan in the planner's output, dropping anything
+// before the "PLAN:" label.
+func extractPlan(output string) string {
+	if i := strings.Index(strings.ToUpper(output), planLabel); i >= 0 {
+		output = output[i+len(planLabel):]
+	}
+	return strings.TrimSpace(output)
+}
+
+// implementerPrompt builds the implementer's prompt for round number. An
+// implementer that renders its prompt from the templates gets the plan and the
+// size of the changes reviewed in the previous round too; for any other the
+// plan is appended to its prompt.
+func (o *Orchestrator) implementerPrompt(number int, basePrompt string, issues []string) string {
+	builder, ok := o.implementer.(fighters.PromptBuilder)
+	if !ok {
+		return withPlan(o.implementer.BuildPromptWithIssues(basePrompt, issues), o.plan)
+	}
+	data := prompts.Data{Prompt: basePrompt, Round: number, Issues: issues, Plan: o.planText()}
+	if len(o.rounds) > 0 {
+		data.Stats = prompts.Stats(o.rounds[len(o.rounds)-1].GitDiff)
+	}
+	return builder.BuildPrompt(data)
+}
+
+// withPlan appends the approved plan to an implementer prompt.
+func withPlan(prompt string, plan *types.Plan) string {
+	if plan == nil || plan.Text == "" {
+		return prompt
+	}
+	return prompt + "\n\nAPPROVED PLAN (follow it step by step):\n" + plan.Text + "\n"
+}
+
+// planText returns the approved plan, or an empty string without a planning phase.
+func (o *Orchestrator) planText() string {
+	if o.plan == nil {
+		return ""
+	}
+	return o.plan.Text
+}
+
+// runPlanning drafts a plan with the planner, has the review panel critique it,
+// revises it once if the reviewers raised issues, and asks the user to approve
+// or edit it. It returns false if the user rejected the plan.
+func (o *Orchestrator) runPlanning(ctx context.Context) (bool, error) {
+	o.info("Planning phase")
+
+	plan := &types.Plan{Planner: o.planner.Name()}
+	templates, err := o.config.PromptTemplates(o.config.PlannerType())
+	if err != nil {
+		return false, err
+	}
+
+	text, err := o.executePlanner(ctx, buildPlanPrompt(templates, o.config.Prompt), plan, "Drafting the plan...")
+	if err != nil {
+		return false, err
+	}
+
+	reviewerName := o.panel.Name()
+	o.publish(events.FighterEnter{Fighter: reviewerName})
+	o.publish(events.FighterAction{Fighter: reviewerName, Action: "Critiquing the plan..."})
+
+	critiqueStart := time.Now()
+	critique, err := o.panel.Review(ctx, types.ReviewRequest{Prompt: o.config.Prompt, Plan: text})
+	if err != nil {
+		return false, fmt.Errorf("plan critique failed: %w", err)
+	}
+	plan.Usage = plan.Usage.Add(critique.Usage)
+	o.publish(events.UsageUpdated{Total: o.totalUsage().Add(plan.Usage)})
+	o.publish(events.FighterFinish{Fighter: reviewerName, Duration: time.Since(critiqueStart)})
+
+	if critique.HasIssues {
+		plan.Critique = critique.Issues
+		if o.logger != nil {
+			o.logger.IssuesFound(reviewerName, critique.Issues)
+		}
+		text, err = o.executePlanner(ctx, buildPlanRevisionPrompt(templates, o.config.Prompt, text, critique.Issues), plan, "Revising the plan...")
+		if err != nil {
+			return false, err
+		}
+	}
+	plan.Text = text
+
+	o.state = types.StateWaitingConfirmation
+	approved, ok := o.approvePlan(*plan)
+	o.state = types.StateRunning
+	// An empty plan cannot guide the rounds, so clearing it rejects it
+	approved = strings.TrimSpace(approved)
+	if !ok || approved == "" {
+		return false, nil
+	}
+	if approved != plan.Text {
+		plan.Text = approved
+		plan.Edited = true
+	}
+
+	o.plan = plan
+	o.info("Approved plan:\n" + plan.Text)
+	return true, nil
+}
+
+// executePlanner runs the planner with prompt and returns the plan in its output.
+func (o *Orchestrator) executePlanner(ctx context.Context, prompt string, plan *types.Plan, action string) (string, error) {
+	name := o.planner.Name()
+	o.publish(events.FighterEnter{Fighter: name})
+	o.publish(events.FighterAction{Fighter: name, Action: action})
+	o.publish(events.FighterInput{Fighter: name, Input: prompt})
+
+	start := time.Now()
+	before := fighters.UsageOf(o.planner)
+	output, err := o.planner.Execute(ctx, prompt, "")
+	plan.Usage = plan.Usage.Add(fighters.UsageOf(o.planner).Sub(before))
+	o.publish(events.UsageUpdated{Total: o.totalUsage().Add(plan.Usage)})
+	o.publish(events.FighterOutput{Fighter: name, Output: output})
+	if err != nil {
+		return "", fmt.Errorf("%s planning failed: %w", name, err)
+	}
+
+	text := extractPlan(output)
+	if text == "" {
+		return "", fmt.Errorf("%s returned an empty plan", name)
+	}
+	o.publish(events.FighterFinish{Fighter: name, Duration: time.Since(start)})
+	return text, nil
+}
+
+// approvePlan asks the user to approve the plan, returning the approved text,
+// which may have been edited, or false if the plan was rejected.
+func (o *Orchestrator) approvePlan(plan types.Plan) (string, bool) {
+	text, ok := o.askPlan(plan)
+	o.publish(events.PlanProposed{Plan: plan, Text: text, Approved: ok})
+	return text, ok
+}
+
+// askPlan asks the responder, or the terminal without one, to approve the plan.
+func (o *Orchestrator) askPlan(plan types.Plan) (string, bool) {
+	if o.responder != nil {
+		return o.responder.OnPlanProposed(plan)
+	}
+
+	fmt.Printf("\nPlan drafted by %s:\n\n%s\n", plan.Planner, plan.Text)
+	if len(plan.Critique) > 0 {
+		fmt.Println("\nThe reviewers' critique, addressed in this revision:")
+		for _, issue := range plan.Critique {
+			fmt.Printf("  - %s\n", issue)
+		}
+	}
+	approved, err := o.confirmer.Confirm(confirm.Question{Message: "Approve this plan?", Default: true, SessionID: o.sessionID})
+	if err != nil {
+		o.warn(fmt.Errorf("no answer to the plan, approving it as drafted: %w", err))
+		approved = true
+	}
+	return plan.Text, approved
+}
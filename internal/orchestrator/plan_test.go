@@ -0,0 +1,194 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// planStub is a planner that returns a fixed draft, then a fixed revision.
+type planStub struct {
+	prompts []string
+}
+
+func (p *planStub) Name() string { return "PLANNER" }
+
+func (p *planStub) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	if len(p.prompts) == 1 {
+		return "Sure, here it is.\nPLAN:\n1. Write feature.txt", nil
+	}
+	return "PLAN:\n1. Write feature.txt\n2. Document it", nil
+}
+
+func (p *planStub) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+// planCritic is a Reviewer that objects to every plan, approves every diff
+// and records the requests it receives.
+type planCritic struct {
+	requests []types.ReviewRequest
+}
+
+func (p *planCritic) Name() string { return "CRITIC" }
+
+func (p *planCritic) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	p.requests = append(p.requests, req)
+	if req.IsPlanReview() {
+		return &types.ReviewResult{HasIssues: true, Issues: []string{"the plan forgets documentation"}}, nil
+	}
+	return &types.ReviewResult{RawOutput: "LGTM"}, nil
+}
+
+// planObserver is an answerObserver that answers plan proposals with a fixed decision.
+type planObserver struct {
+	answerObserver
+	proposed []types.Plan
+	text     string
+	approve  bool
+}
+
+func (p *planObserver) OnPlanProposed(plan types.Plan) (string, bool) {
+	p.proposed = append(p.proposed, plan)
+	return p.text, p.approve
+}
+
+// newPlanOrchestrator creates an orchestrator with the planning phase enabled
+// on a fresh repository, with stub planner, implementer and reviewer.
+func newPlanOrchestrator(t *testing.T, observer Observer) (*Orchestrator, *planStub, *planCritic, string) {
+	t.Helper()
+
+	dir := newTestRepo(t)
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add a feature"
+	cfg.Plan = true
+
+	orch, err := NewWithObserver(cfg, nil, observer)
+	if err != nil {
+		t.Fatalf("NewWithObserver() error = %v", err)
+	}
+	planner := &planStub{}
+	critic := &planCritic{}
+	orch.planner = planner
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, critic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orch, planner, critic, dir
+}
+
+func TestRunPlanning(t *testing.T) {
+	observer := &planObserver{text: "1. Write feature.txt\n2. Document it\n3. Keep it short", approve: true}
+	orch, planner, critic, dir := newPlanOrchestrator(t, observer)
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Success = false, StopReason = %q", result.StopReason)
+	}
+
+	// The draft is critiqued, revised with the critique and proposed to the user
+	if len(planner.prompts) != 2 || !strings.Contains(planner.prompts[1], "- the plan forgets documentation") {
+		t.Fatalf("planner prompts = %q, want a draft and a revision with the critique", planner.prompts)
+	}
+	if len(observer.proposed) != 1 || observer.proposed[0].Text != "1. Write feature.txt\n2. Document it" {
+		t.Fatalf("proposed plans = %+v, want the revision", observer.proposed)
+	}
+
+	plan := result.Plan
+	if plan == nil || !plan.Edited || plan.Text != observer.text || plan.Planner != "PLANNER" {
+		t.Fatalf("Plan = %+v, want the user's edit", plan)
+	}
+	if len(plan.Critique) != 1 {
+		t.Errorf("Critique = %v", plan.Critique)
+	}
+
+	// The approved plan goes to the implementer and is the reviewer's yardstick
+	prompt, err := os.ReadFile(filepath.Join(dir, "feature.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prompt), "APPROVED PLAN") || !strings.Contains(string(prompt), "3. Keep it short") {
+		t.Errorf("implementer prompt = %q, want the approved plan", prompt)
+	}
+	last := critic.requests[len(critic.requests)-1]
+	if last.IsPlanReview() || last.Plan != observer.text {
+		t.Errorf("review request = %+v, want the diff checked against the approved plan", last)
+	}
+}
+
+func TestRunPlanning_Rejected(t *testing.T) {
+	observer := &planObserver{approve: false}
+	orch, _, _, _ := newPlanOrchestrator(t, observer)
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.State != types.StateAborted || result.TotalRounds != 0 {
+		t.Errorf("State = %s after %d round(s), want aborted before round 1", result.State, result.TotalRounds)
+	}
+	if result.StopReason != "Plan rejected by user" {
+		t.Errorf("StopReason = %q", result.StopReason)
+	}
+	if result.Plan != nil {
+		t.Errorf("Plan = %+v, want none", result.Plan)
+	}
+}
+
+func TestRunPlanning_ResumeKeepsPlan(t *testing.T) {
+	observer := &planObserver{approve: false}
+	orch, planner, _, _ := newPlanOrchestrator(t, observer)
+	orch.plan = &types.Plan{Planner: "PLANNER", Text: "1. Write feature.txt"}
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(planner.prompts) != 0 || len(observer.proposed) != 0 {
+		t.Error("a session with an approved plan should not plan again")
+	}
+	if !result.Success {
+		t.Errorf("Success = false, StopReason = %q", result.StopReason)
+	}
+}
+
+func TestExtractPlan(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"PLAN:\n1. step", "1. step"},
+		{"Let me look.\n\nPlan:\n1. step\n2. other", "1. step\n2. other"},
+		{"1. step without label", "1. step without label"},
+		{"  \n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractPlan(tt.output); got != tt.want {
+			t.Errorf("extractPlan(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestWithPlan(t *testing.T) {
+	if got := withPlan("fix it", nil); got != "fix it" {
+		t.Errorf("withPlan() without a plan = %q", got)
+	}
+	got := withPlan("fix it", &types.Plan{Text: "1. step"})
+	if !strings.HasPrefix(got, "fix it") || !strings.Contains(got, "APPROVED PLAN (follow it step by step):\n1. step") {
+		t.Errorf("withPlan() = %q", got)
+	}
+}
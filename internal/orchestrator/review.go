@@ -0,0 +1,150 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// ReviewTarget selects the changes reviewed in review-only mode: the
+// uncommitted changes of the working tree by default, a range of commits, or
+// the changes of the current branch since it forked from a base branch.
+type ReviewTarget struct {
+	// Commits is a revision range such as HEAD~3..HEAD, or a single commit
+	Commits string
+
+	// Base is the branch the current branch is compared against
+	Base string
+}
+
+// revisionRange returns the git revision range of the target, the commit if
+// it is a single one, or an empty string for the uncommitted changes.
+func (t ReviewTarget) revisionRange() string {
+	switch {
+	case t.Commits != "":
+		return t.Commits
+	case t.Base != "":
+		return t.Base + "...HEAD"
+	default:
+		return ""
+	}
+}
+
+// Uncommitted reports whether the target is the uncommitted changes of the working tree.
+func (t ReviewTarget) Uncommitted() bool {
+	return t.Commits == "" && t.Base == ""
+}
+
+// String describes the target for messages and prompts.
+func (t ReviewTarget) String() string {
+	switch {
+	case t.Commits != "":
+		return "commits " + t.Commits
+	case t.Base != "":
+		return "the changes since " + t.Base
+	default:
+		return "the uncommitted changes"
+	}
+}
+
+// FixPrompt returns the implementer prompt used to fix the issues of a review of target.
+func (t ReviewTarget) FixPrompt() string {
+	return fmt.Sprintf("Fix the issues the code review found in %s.", t)
+}
+
+// ReviewChanges runs the review panel once on target, without an implementer,
+// and returns the review as a round numbered 0. Uncommitted changes are staged
+// first, as in a battle round, and become part of the session's changes.
+func (o *Orchestrator) ReviewChanges(ctx context.Context, target ReviewTarget) (*types.Round, error) {
+	if target.Commits != "" && target.Base != "" {
+		return nil, errors.New("review either a commit range or a base branch, not both")
+	}
+	if !o.repo.IsGitRepo() {
+		return nil, git.ErrNotGitRepo
+	}
+
+	revisions := target.revisionRange()
+	var diff string
+	var err error
+	switch {
+	case target.Commits != "" && !strings.Contains(target.Commits, ".."):
+		diff, err = o.git.GetCommitDiff(target.Commits)
+	case revisions != "":
+		diff, err = o.git.GetRangeDiff(revisions)
+	default:
+		o.ignoreOutputDir()
+		if err := o.git.StageAll(); err != nil {
+			return nil, fmt.Errorf("failed to stage changes: %w", err)
+		}
+		diff, err = o.git.GetStagedDiff()
+		// A battle fixing the issues starts from HEAD, so that the reviewed
+		// changes are reviewed and committed with the fixes instead of being
+		// set aside as the user's own
+		if err == nil {
+			o.baseline, err = o.git.ResolveRef("HEAD")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff of %s: %w", target, err)
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil, fmt.Errorf("no changes to review in %s", target)
+	}
+
+	reviewerName := o.panel.Name()
+	round := &types.Round{
+		Reviewer:  reviewerName,
+		GitDiff:   diff,
+		Timestamp: time.Now(),
+	}
+
+	o.publish(events.SessionStart{SessionID: o.sessionID, Implementer: o.ImplementerName(), Reviewer: reviewerName})
+
+	fileCount := countFilesInDiff(diff)
+	o.publish(events.DiffCaptured{Diff: diff})
+	o.publish(events.ChangesDetected{Files: fileCount})
+	o.publish(events.FighterEnter{Fighter: reviewerName})
+	o.publish(events.FighterAction{Fighter: reviewerName, Action: fmt.Sprintf("Reviewing %s...", target)})
+	o.publish(events.FighterInput{Fighter: reviewerName, Input: fmt.Sprintf("review of %s (%d file(s))", target, fileCount)})
+
+	start := time.Now()
+	result, err := o.review(ctx, types.ReviewRequest{Diff: diff, Range: revisions})
+	if err != nil {
+		o.publish(events.Error{Err: err})
+		return nil, err
+	}
+
+	for _, r := range result.Reviews {
+		o.publish(events.FighterOutput{Fighter: r.Reviewer, Output: r.RawOutput})
+	}
+	o.publish(events.FighterFinish{Fighter: reviewerName, Duration: time.Since(start)})
+	o.publish(events.ReviewVerdicts{Reviews: result.Reviews})
+	if result.HasIssues {
+		o.publish(events.IssuesFound{Source: reviewerName, Issues: result.Findings})
+	} else {
+		o.publish(events.NoIssues{})
+	}
+
+	round.ReviewerOutput = combinedReviewOutput(result.Reviews)
+	round.HasIssues = result.HasIssues
+	round.Issues = result.Issues
+	round.Findings = result.Findings
+	round.Reviews = result.Reviews
+	round.Chunks = result.Chunks
+	round.Usage = result.Usage
+	round.Outcome = types.OutcomeReviewed
+	round.Duration = time.Since(start)
+	return round, nil
+}
+
+// SetPendingIssues makes Run hand issues to the implementer in the first
+// round, as if a previous round had found them.
+func (o *Orchestrator) SetPendingIssues(issues []string) {
+	o.pendingIssues = issues
+}
@@ -0,0 +1,189 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// recordingReviewer is a Reviewer that reports one issue and records the request.
+type recordingReviewer struct {
+	req types.ReviewRequest
+}
+
+func (r *recordingReviewer) Name() string { return "RECORDER" }
+
+func (r *recordingReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	r.req = req
+	return &types.ReviewResult{HasIssues: true, Issues: []string{"[high] feature.txt:1 - Missing tests"}}, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// newReviewOrchestrator creates an orchestrator on a repository with a feature
+// branch holding one commit on top of the base branch, and an uncommitted file.
+func newReviewOrchestrator(t *testing.T) (*Orchestrator, *recordingReviewer, string) {
+	t.Helper()
+
+	dir := newTestRepo(t)
+	base := runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "add feature")
+	if err := os.WriteFile(filepath.Join(dir, "wip.txt"), []byte("work in progress\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	orch, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	reviewer := &recordingReviewer{}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, reviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orch, reviewer, base
+}
+
+func TestReviewChanges_Targets(t *testing.T) {
+	orch, reviewer, base := newReviewOrchestrator(t)
+
+	tests := []struct {
+		name      string
+		target    ReviewTarget
+		wantFile  string
+		wantRange string
+	}{
+		{"single commit", ReviewTarget{Commits: "HEAD"}, "feature.txt", "HEAD"},
+		{"commit range", ReviewTarget{Commits: "HEAD~1..HEAD"}, "feature.txt", "HEAD~1..HEAD"},
+		{"base branch", ReviewTarget{Base: base}, "feature.txt", base + "...HEAD"},
+		{"uncommitted changes", ReviewTarget{}, "wip.txt", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			round, err := orch.ReviewChanges(context.Background(), tt.target)
+			if err != nil {
+				t.Fatalf("ReviewChanges() error = %v", err)
+			}
+
+			if !strings.Contains(round.GitDiff, tt.wantFile) {
+				t.Errorf("GitDiff = %q, want changes to %s", round.GitDiff, tt.wantFile)
+			}
+			if tt.wantFile == "feature.txt" && strings.Contains(round.GitDiff, "wip.txt") {
+				t.Errorf("GitDiff = %q, want the uncommitted changes left out", round.GitDiff)
+			}
+			if reviewer.req.Range != tt.wantRange {
+				t.Errorf("ReviewRequest.Range = %q, want %q", reviewer.req.Range, tt.wantRange)
+			}
+			if !round.HasIssues || len(round.Findings) != 1 || round.Findings[0].Severity != types.SeverityHigh {
+				t.Errorf("round = %+v, want the reviewer's high-severity issue", round)
+			}
+		})
+	}
+}
+
+func TestReviewChanges_InvalidTargets(t *testing.T) {
+	orch, _, base := newReviewOrchestrator(t)
+
+	if _, err := orch.ReviewChanges(context.Background(), ReviewTarget{Commits: "HEAD", Base: base}); err == nil {
+		t.Error("ReviewChanges() should reject a commit range together with a base branch")
+	}
+	if _, err := orch.ReviewChanges(context.Background(), ReviewTarget{Base: "HEAD"}); err == nil {
+		t.Error("ReviewChanges() should report that there is nothing to review")
+	}
+}
+
+func TestReviewChanges_Fix(t *testing.T) {
+	orch, _, _ := newReviewOrchestrator(t)
+
+	round, err := orch.ReviewChanges(context.Background(), ReviewTarget{Commits: "HEAD"})
+	if err != nil {
+		t.Fatalf("ReviewChanges() error = %v", err)
+	}
+
+	orch.config.Prompt = ReviewTarget{Commits: "HEAD"}.FixPrompt()
+	orch.config.MaxIterations = 1
+	orch.implementer = &fileImplementer{dir: orch.config.WorkDir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orch.SetPendingIssues(round.Issues)
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Run() Success = false, stop reason %q", result.StopReason)
+	}
+	prompt := result.Rounds[0].ImplementerPrompt
+	if !strings.Contains(prompt, "Fix the issues the code review found in commits HEAD") || !strings.Contains(prompt, "Missing tests") {
+		t.Errorf("first round prompt = %q, want the fix prompt with the review's issues", prompt)
+	}
+}
+
+func TestReviewChanges_FixUncommitted(t *testing.T) {
+	orch, _, _ := newReviewOrchestrator(t)
+	dir := orch.config.WorkDir
+
+	round, err := orch.ReviewChanges(context.Background(), ReviewTarget{})
+	if err != nil {
+		t.Fatalf("ReviewChanges() error = %v", err)
+	}
+
+	orch.config.Prompt = ReviewTarget{}.FixPrompt()
+	orch.config.MaxIterations = 1
+	orch.config.AutoCommit = true
+	orch.implementer = &fileImplementer{dir: dir}
+	orch.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orch.SetPendingIssues(round.Issues)
+
+	result, err := orch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Run() Success = false, stop reason %q", result.StopReason)
+	}
+
+	// The reviewed changes are reviewed again and committed with the fixes
+	if diff := result.Rounds[0].GitDiff; !strings.Contains(diff, "wip.txt") || !strings.Contains(diff, "feature.txt") {
+		t.Errorf("round diff = %q, want the reviewed changes and the fixes", diff)
+	}
+	committed := runGit(t, dir, "show", "--name-only", "--format=", "HEAD")
+	if committed != "feature.txt\nwip.txt" {
+		t.Errorf("committed files = %q, want feature.txt and wip.txt", committed)
+	}
+	if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("status = %q, want a clean tree", status)
+	}
+}
@@ -0,0 +1,96 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// detectStall inspects the rounds played so far and explains why the battle is
+// no longer making progress, or returns an empty string if it is.
+// It looks for, in order:
+//   - the implementer leaving the tree exactly as it was after the previous round
+//   - the implementer reverting the tree to the state of an earlier round
+//   - the reviewer raising the same set of issues as in an earlier round
+//
+// Rounds that did not complete are left out, since their changes may be incomplete.
+func detectStall(rounds []types.Round) string {
+	rounds = completedRounds(rounds)
+	if len(rounds) < 2 {
+		return ""
+	}
+
+	last := rounds[len(rounds)-1]
+	previous := rounds[len(rounds)-2]
+
+	if last.GitDiff != "" && last.GitDiff == previous.GitDiff {
+		return fmt.Sprintf("round %d made no changes to the result of round %d", last.Number, previous.Number)
+	}
+
+	for i := len(rounds) - 3; i >= 0; i-- {
+		if last.GitDiff != "" && last.GitDiff == rounds[i].GitDiff {
+			return fmt.Sprintf("round %d reverted the changes back to the state of round %d", last.Number, rounds[i].Number)
+		}
+	}
+
+	if !last.HasIssues {
+		return ""
+	}
+	key := issueSetKey(last.Issues)
+	for i := len(rounds) - 2; i >= 0; i-- {
+		if rounds[i].HasIssues && issueSetKey(rounds[i].Issues) == key {
+			return fmt.Sprintf("the reviewer raised the same %d issue(s) in rounds %d and %d",
+				len(last.Issues), rounds[i].Number, last.Number)
+		}
+	}
+
+	return ""
+}
+
+// completedRounds returns the rounds that ran to the end.
+func completedRounds(rounds []types.Round) []types.Round {
+	completed := make([]types.Round, 0, len(rounds))
+	for _, round := range rounds {
+		if round.Outcome.Completed() {
+			completed = append(completed, round)
+		}
+	}
+	return completed
+}
+
+// issueSetKey returns a key identifying a set of issues regardless of order,
+// case and whitespace.
+func issueSetKey(issues []string) string {
+	normalized := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		normalized = append(normalized, strings.ToLower(strings.Join(strings.Fields(issue), " ")))
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, "\n")
+}
+
+// checkStall stops or escalates to the user when the battle is no longer making
+// progress. It returns true if the session should stop as stalled.
+func (o *Orchestrator) checkStall() bool {
+	reason := detectStall(o.rounds)
+	if reason == "" {
+		return false
+	}
+
+	o.info(fmt.Sprintf("Stall detected: %s", reason))
+
+	if o.config.OnStall == config.StallActionAsk {
+		o.state = types.StateWaitingConfirmation
+		if o.confirm(fmt.Sprintf("The battle looks stalled: %s. Continue anyway?", reason)) {
+			o.state = types.StateRunning
+			return false
+		}
+	}
+
+	o.state = types.StateStalled
+	o.stopReason = "Stalled: " + reason
+	return true
+}
@@ -0,0 +1,145 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func TestDetectStall(t *testing.T) {
+	tests := []struct {
+		name   string
+		rounds []types.Round
+		want   string
+	}{
+		{
+			name:   "single round",
+			rounds: []types.Round{{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}}},
+		},
+		{
+			name: "progress",
+			rounds: []types.Round{
+				{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+				{Number: 2, GitDiff: "b", HasIssues: true, Issues: []string{"y"}},
+			},
+		},
+		{
+			name: "identical diff",
+			rounds: []types.Round{
+				{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+				{Number: 2, GitDiff: "a", HasIssues: true, Issues: []string{"y"}},
+			},
+			want: "round 2 made no changes",
+		},
+		{
+			name: "oscillation",
+			rounds: []types.Round{
+				{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+				{Number: 2, GitDiff: "b", HasIssues: true, Issues: []string{"y"}},
+				{Number: 3, GitDiff: "a", HasIssues: true, Issues: []string{"z"}},
+			},
+			want: "round 3 reverted the changes back to the state of round 1",
+		},
+		{
+			name: "repeated issues",
+			rounds: []types.Round{
+				{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"Missing test", "Unused var"}},
+				{Number: 2, GitDiff: "b", HasIssues: true, Issues: []string{"y"}},
+				{Number: 3, GitDiff: "c", HasIssues: true, Issues: []string{"unused  var", "missing test"}},
+			},
+			want: "same 2 issue(s) in rounds 1 and 3",
+		},
+		{
+			name: "empty diffs are not a stall",
+			rounds: []types.Round{
+				{Number: 1, HasIssues: true, Issues: []string{"x"}},
+				{Number: 2, HasIssues: true, Issues: []string{"y"}},
+			},
+		},
+		{
+			name: "interrupted rounds are left out",
+			rounds: []types.Round{
+				{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+				{Number: 2, GitDiff: "b", Outcome: types.OutcomeInterrupted},
+				{Number: 3, GitDiff: "b", HasIssues: true, Issues: []string{"y"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectStall(tt.rounds)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("detectStall() = %q, want no stall", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("detectStall() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueSetKey(t *testing.T) {
+	a := issueSetKey([]string{"B issue", "a  issue"})
+	b := issueSetKey([]string{"A issue", "b issue"})
+	if a != b {
+		t.Errorf("issueSetKey should ignore order, case and whitespace: %q != %q", a, b)
+	}
+	if issueSetKey([]string{"a"}) == issueSetKey([]string{"b"}) {
+		t.Error("different issues should have different keys")
+	}
+}
+
+func TestCheckStall(t *testing.T) {
+	stalled := []types.Round{
+		{Number: 1, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+		{Number: 2, GitDiff: "a", HasIssues: true, Issues: []string{"x"}},
+	}
+
+	tests := []struct {
+		name      string
+		onStall   string
+		answers   []bool
+		wantStop  bool
+		wantState types.SessionState
+		wantAsked int
+	}{
+		{"stop", config.StallActionStop, nil, true, types.StateStalled, 0},
+		{"ask and continue", config.StallActionAsk, []bool{true}, false, types.StateRunning, 1},
+		{"ask and stop", config.StallActionAsk, []bool{false}, true, types.StateStalled, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New()
+			cfg.WorkDir = t.TempDir()
+			cfg.OnStall = tt.onStall
+
+			observer := &answerObserver{answers: tt.answers}
+			orch, err := NewWithObserver(cfg, nil, observer)
+			if err != nil {
+				t.Fatalf("NewWithObserver() error = %v", err)
+			}
+			orch.rounds = stalled
+			orch.state = types.StateRunning
+
+			if got := orch.checkStall(); got != tt.wantStop {
+				t.Errorf("checkStall() = %v, want %v", got, tt.wantStop)
+			}
+			if orch.state != tt.wantState {
+				t.Errorf("state = %s, want %s", orch.state, tt.wantState)
+			}
+			if len(observer.messages) != tt.wantAsked {
+				t.Errorf("asked %d times, want %d", len(observer.messages), tt.wantAsked)
+			}
+			if tt.wantStop && !strings.HasPrefix(orch.stopReason, "Stalled: ") {
+				t.Errorf("stopReason = %q, want a stall explanation", orch.stopReason)
+			}
+		})
+	}
+}
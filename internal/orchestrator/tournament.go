@@ -0,0 +1,466 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/internal/logger"
+	"github.com/diegoram/mortal-prompter/internal/session"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Tournament runs several implementers on the same prompt in parallel, each in
+// its own worktree and review loop, and lets a judge fighter pick the winner.
+// Only the winner's changes are merged into the user's branch.
+type Tournament struct {
+	config    *config.Config
+	bus       *events.Bus
+	confirmer confirm.Confirmer
+	repo      *git.Git
+
+	id         string
+	contenders []*Orchestrator
+	committed  []bool
+	judgeName  string
+
+	// newJudge builds the judge fighter in the given directory
+	newJudge func(workDir string) (fighters.Implementer, error)
+}
+
+// NewTournament creates a tournament between the implementers in cfg.Contenders,
+// each reviewed by the panel configured in cfg and judged by cfg.Judge.
+func NewTournament(cfg *config.Config, log *logger.Logger, observer Observer) (*Tournament, error) {
+	if len(cfg.Contenders) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least two contenders, got %d", len(cfg.Contenders))
+	}
+
+	t := &Tournament{
+		config:    cfg,
+		bus:       events.NewBus(),
+		confirmer: confirm.Stdin(),
+		repo:      git.New(cfg.WorkDir),
+		id:        session.NewID(),
+		judgeName: fighters.DisplayName(cfg.Judge),
+		newJudge: func(workDir string) (fighters.Implementer, error) {
+			return newImplementer(cfg, cfg.Judge, workDir)
+		},
+	}
+	if log != nil {
+		t.bus.SubscribeSync(log)
+	}
+	if observer != nil {
+		t.bus.SubscribeSync(observer)
+		t.confirmer = observer
+	}
+
+	// Progress of every contender is reported under the tournament's implementer name
+	names := make([]string, len(cfg.Contenders))
+	for i, ft := range cfg.Contenders {
+		names[i] = fighters.DisplayName(ft)
+	}
+	implementerName := strings.Join(names, " | ")
+
+	for i, ft := range cfg.Contenders {
+		contenderCfg := *cfg
+		contenderCfg.Implementer = ft
+		contenderCfg.Contenders = nil
+		contenderCfg.Worktree = true
+		contenderCfg.Interactive = false
+		contenderCfg.AutoCommit = false
+
+		o, err := New(&contenderCfg, log)
+		if err != nil {
+			return nil, fmt.Errorf("contender #%d: %w", i+1, err)
+		}
+		o.contender = true
+		// Session IDs only differ by a short random suffix within the same second
+		for t.hasSession(o.sessionID) {
+			o.sessionID = session.NewID()
+		}
+		progress := &contenderObserver{
+			parent: t.bus,
+			name:   implementerName,
+			label:  fmt.Sprintf("#%d %s", i+1, o.ImplementerName()),
+		}
+		o.bus.SubscribeSync(progress)
+		o.SetResponder(progress)
+		t.contenders = append(t.contenders, o)
+	}
+
+	return t, nil
+}
+
+// hasSession reports whether a contender or the tournament itself already uses id.
+func (t *Tournament) hasSession(id string) bool {
+	if id == t.id {
+		return true
+	}
+	for _, c := range t.contenders {
+		if c.sessionID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Events returns the bus on which the events of the tournament are published.
+// The contenders publish their own events on buses of their own, and only
+// report their progress on this one.
+func (t *Tournament) Events() *events.Bus {
+	return t.bus
+}
+
+// SetResponder sets what answers the questions of the tournament instead of the terminal.
+func (t *Tournament) SetResponder(r Responder) {
+	t.confirmer = r
+}
+
+// SetConfirmer sets what answers the yes/no questions of the tournament. The
+// contenders keep declining theirs.
+func (t *Tournament) SetConfirmer(c confirm.Confirmer) {
+	t.confirmer = c
+}
+
+// SessionID returns the identifier of the tournament.
+func (t *Tournament) SessionID() string {
+	return t.id
+}
+
+// ImplementerName returns the names of the contenders joined with " | ".
+func (t *Tournament) ImplementerName() string {
+	names := make([]string, len(t.contenders))
+	for i, c := range t.contenders {
+		names[i] = c.ImplementerName()
+	}
+	return strings.Join(names, " | ")
+}
+
+// ReviewerName returns the display name of the review panel shared by the contenders.
+func (t *Tournament) ReviewerName() string {
+	return t.contenders[0].ReviewerName()
+}
+
+// SetImagePath sets the image attached to the first round of every contender.
+func (t *Tournament) SetImagePath(imagePath string) {
+	for _, c := range t.contenders {
+		c.SetImagePath(imagePath)
+	}
+}
+
+// Run plays the tournament and returns the winner's session result with the
+// tournament details attached.
+func (t *Tournament) Run(ctx context.Context) (*types.SessionResult, error) {
+	start := time.Now()
+
+	if !t.repo.IsGitRepo() {
+		err := fmt.Errorf("working directory is not a git repository: %s", t.config.WorkDir)
+		t.publish(events.Error{Err: err})
+		return nil, err
+	}
+	t.publish(events.SessionStart{SessionID: t.id, Implementer: t.ImplementerName(), Reviewer: t.ReviewerName()})
+
+	if err := t.setupWorktrees(); err != nil {
+		t.publish(events.Error{Err: err})
+		return nil, err
+	}
+
+	t.info(fmt.Sprintf("Tournament %s: %s", t.id, t.ImplementerName()))
+
+	tournament := &types.TournamentResult{Judge: t.judgeName}
+	tournament.Contenders = t.runContenders(ctx)
+
+	if ctx.Err() != nil {
+		result := &types.SessionResult{
+			SessionID:     t.id,
+			State:         types.StateInterrupted,
+			StopReason:    "Tournament interrupted",
+			TotalDuration: time.Since(start),
+			Tournament:    tournament,
+		}
+		t.keepBranches(tournament)
+		t.publish(events.SessionComplete{Result: result})
+		return result, ctx.Err()
+	}
+
+	t.pickWinner(ctx, tournament)
+	t.applyWinner(tournament)
+
+	result := t.buildResult(tournament, time.Since(start))
+	t.publish(events.SessionComplete{Result: result, Success: result.Success})
+	return result, nil
+}
+
+// setupWorktrees creates the worktree of every contender that does not have one yet.
+// They are created one at a time since git locks the repository while adding one.
+func (t *Tournament) setupWorktrees() error {
+	for i, c := range t.contenders {
+		if c.worktreePath != "" {
+			continue
+		}
+		if err := c.setupWorktree(); err != nil {
+			return fmt.Errorf("contender #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// runContenders runs every contender's battle concurrently and commits each
+// contender's changes to its branch.
+func (t *Tournament) runContenders(ctx context.Context) []types.ContenderResult {
+	results := make([]types.ContenderResult, len(t.contenders))
+
+	var wg sync.WaitGroup
+	for i, c := range t.contenders {
+		wg.Add(1)
+		go func(i int, c *Orchestrator) {
+			defer wg.Done()
+
+			result, err := c.Run(ctx)
+			results[i] = types.ContenderResult{
+				Number:      i + 1,
+				Implementer: c.ImplementerName(),
+				Branch:      c.branch,
+				Result:      result,
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	t.committed = make([]bool, len(t.contenders))
+	for i, c := range t.contenders {
+		if err := c.autoCommit(); err != nil {
+			results[i].Error = fmt.Sprintf("failed to commit to %s: %v", c.branch, err)
+		} else {
+			t.committed[i] = true
+		}
+		t.info(fmt.Sprintf("Contender #%d (%s): %s", i+1, results[i].Implementer, contenderStatus(results[i])))
+	}
+
+	return results
+}
+
+// pickWinner decides the winner among the contenders that produced changes,
+// asking the judge when there is more than one.
+func (t *Tournament) pickWinner(ctx context.Context, tournament *types.TournamentResult) {
+	var candidates []types.ContenderResult
+	for _, c := range tournament.Contenders {
+		if c.Result != nil && strings.TrimSpace(c.Result.FinalDiff) != "" {
+			candidates = append(candidates, c)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		tournament.Reasoning = "No contender produced any changes"
+		return
+	case 1:
+		tournament.Winner = candidates[0].Number
+		tournament.Reasoning = fmt.Sprintf("Contender #%d was the only one to produce changes", candidates[0].Number)
+		return
+	}
+
+	templates, err := t.config.PromptTemplates(t.config.Judge)
+	if err != nil {
+		tournament.Reasoning = fmt.Sprintf("The judge failed: %v", err)
+		t.warn(err)
+		return
+	}
+	output, err := t.judge(ctx, buildJudgePrompt(templates, t.config.Prompt, candidates))
+	if err != nil {
+		tournament.Reasoning = fmt.Sprintf("The judge failed: %v", err)
+		t.warn(fmt.Errorf("judge failed: %w", err))
+		return
+	}
+
+	winner, reasoning, err := parseJudgeVerdict(output, candidates)
+	if err != nil {
+		tournament.Reasoning = fmt.Sprintf("The judge's verdict could not be read: %v", err)
+		t.warn(err)
+		return
+	}
+	tournament.Winner = winner
+	tournament.Reasoning = reasoning
+}
+
+// judge runs the judge fighter with prompt in an empty directory, so that it
+// cannot touch the user's files.
+func (t *Tournament) judge(ctx context.Context, prompt string) (string, error) {
+	dir, err := os.MkdirTemp("", "mortal-prompter-judge-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	judge, err := t.newJudge(dir)
+	if err != nil {
+		return "", err
+	}
+
+	t.publish(events.FighterEnter{Fighter: t.judgeName})
+	t.publish(events.FighterAction{Fighter: t.judgeName, Action: "Judging the contenders..."})
+	t.publish(events.FighterInput{Fighter: t.judgeName, Input: prompt})
+
+	judgeStart := time.Now()
+	output, err := judge.Execute(ctx, prompt, "")
+	t.publish(events.FighterOutput{Fighter: t.judgeName, Output: output})
+	if err != nil {
+		return "", err
+	}
+	t.publish(events.FighterFinish{Fighter: t.judgeName, Duration: time.Since(judgeStart)})
+	return output, nil
+}
+
+// applyWinner merges the winner's branch into the user's branch. A winner the
+// reviewer did not approve is only merged if the user confirms. The worktrees
+// of the other contenders are removed but their branches are kept.
+func (t *Tournament) applyWinner(tournament *types.TournamentResult) {
+	if tournament.Winner > 0 && t.committed[tournament.Winner-1] {
+		c := t.contenders[tournament.Winner-1]
+		winner := tournament.Contenders[tournament.Winner-1]
+
+		apply := true
+		if !winner.Result.Success {
+			apply = t.confirm(fmt.Sprintf("Contender #%d won with %d open issue(s). Merge branch %s anyway?",
+				winner.Number, len(remainingIssues(winner)), winner.Branch))
+		}
+
+		if apply {
+			if err := t.repo.Merge(c.branch); err != nil {
+				t.warn(err)
+			} else {
+				tournament.Applied = true
+				t.info(fmt.Sprintf("Merged branch %s of contender #%d", c.branch, winner.Number))
+				c.removeWorktree()
+			}
+		}
+	}
+
+	t.keepBranches(tournament)
+}
+
+// keepBranches removes the remaining contender worktrees, keeping the branches
+// with their committed changes for inspection.
+func (t *Tournament) keepBranches(tournament *types.TournamentResult) {
+	for i, c := range t.contenders {
+		if c.worktreePath == "" {
+			tournament.Contenders[i].Branch = c.branch
+			continue
+		}
+		if t.committed == nil || !t.committed[i] {
+			// Uncommitted changes would be lost with the worktree
+			t.info(fmt.Sprintf("Keeping branch %s of contender #%d (worktree: %s)", c.branch, i+1, c.worktreePath))
+			continue
+		}
+		if err := t.repo.RemoveWorktree(c.worktreePath); err != nil {
+			t.warn(fmt.Errorf("failed to remove worktree: %w", err))
+			continue
+		}
+		c.worktreePath = ""
+		t.info(fmt.Sprintf("Kept branch %s of contender #%d", c.branch, i+1))
+	}
+}
+
+// buildResult returns the winner's session result with the tournament attached,
+// or an unsuccessful result if there is no winner.
+func (t *Tournament) buildResult(tournament *types.TournamentResult, duration time.Duration) *types.SessionResult {
+	if tournament.Winner == 0 {
+		return &types.SessionResult{
+			SessionID:     t.id,
+			State:         types.StateFailed,
+			StopReason:    tournament.Reasoning,
+			TotalDuration: duration,
+			Tournament:    tournament,
+		}
+	}
+
+	winner := tournament.Contenders[tournament.Winner-1]
+	result := *winner.Result
+	result.SessionID = t.id
+	result.TotalDuration = duration
+	result.Tournament = tournament
+	result.Branch = winner.Branch
+	result.Success = tournament.Applied && winner.Result.Success
+	if tournament.Applied {
+		result.StopReason = fmt.Sprintf("Contender #%d (%s) won the tournament and was merged", winner.Number, winner.Implementer)
+	} else {
+		result.StopReason = fmt.Sprintf("Contender #%d (%s) won the tournament but was not merged", winner.Number, winner.Implementer)
+	}
+	return &result
+}
+
+// publish publishes e on the tournament's bus.
+func (t *Tournament) publish(e events.Event) {
+	t.bus.Publish(e)
+}
+
+// info publishes a progress message.
+func (t *Tournament) info(message string) {
+	t.publish(events.Info{Message: message})
+}
+
+// warn publishes an error that does not end the tournament.
+func (t *Tournament) warn(err error) {
+	t.publish(events.Warning{Err: err})
+}
+
+// confirm asks the user a yes/no question (default: no).
+func (t *Tournament) confirm(message string) bool {
+	return ask(t.confirmer, t.bus, confirm.Question{Message: message, SessionID: t.id})
+}
+
+// contenderObserver reports a contender's progress on the tournament's bus
+// as messages labelled with the contender, and declines every confirmation so
+// that contenders never block on the user.
+type contenderObserver struct {
+	parent *events.Bus
+	name   string
+	label  string
+}
+
+// HandleEvent reports the events worth following in the tournament's view.
+func (c *contenderObserver) HandleEvent(e events.Event) {
+	switch e := e.(type) {
+	case events.RoundStart:
+		c.progress(fmt.Sprintf("Round %d", e.Round))
+	case events.FighterAction:
+		c.progress(e.Action)
+	case events.IssuesFound:
+		c.progress(fmt.Sprintf("%d issue(s) found", len(e.Issues)))
+	case events.NoIssues:
+		c.progress("LGTM")
+	case events.PlanProposed:
+		c.progress("Plan approved")
+	case events.SessionComplete:
+		c.progress("Finished: " + e.Result.StopReason)
+	case events.Error:
+		c.progress("Error: " + e.Err.Error())
+	}
+}
+
+// Confirm declines.
+func (c *contenderObserver) Confirm(q confirm.Question) (bool, error) {
+	return false, nil
+}
+
+// OnPlanProposed approves every contender's plan as drafted, since the
+// contenders plan concurrently and nobody is there to review each plan.
+func (c *contenderObserver) OnPlanProposed(plan types.Plan) (string, bool) {
+	return plan.Text, true
+}
+
+// progress publishes a progress message on the tournament's bus.
+func (c *contenderObserver) progress(message string) {
+	c.parent.Publish(events.ContenderProgress{Fighter: c.name, Contender: c.label, Message: message})
+}
@@ -0,0 +1,178 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/review"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// idleImplementer is an Implementer that never changes anything.
+type idleImplementer struct{}
+
+func (i *idleImplementer) Name() string { return "IDLE" }
+
+func (i *idleImplementer) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	return "nothing to do", nil
+}
+
+func (i *idleImplementer) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+// judgeStub is an Implementer that answers with a fixed verdict.
+type judgeStub struct {
+	verdict string
+	err     error
+	prompts []string
+}
+
+func (j *judgeStub) Name() string { return "JUDGE" }
+
+func (j *judgeStub) Execute(ctx context.Context, prompt string, imagePath string) (string, error) {
+	j.prompts = append(j.prompts, prompt)
+	return j.verdict, j.err
+}
+
+func (j *judgeStub) BuildPromptWithIssues(basePrompt string, previousIssues []string) string {
+	return basePrompt
+}
+
+// newTestTournament creates a tournament of two contenders on a fresh
+// repository, whose worktrees are set up with the given implementers and an
+// approving reviewer each.
+func newTestTournament(t *testing.T, judge *judgeStub, implementers ...func(dir string) fighters.Implementer) (*Tournament, string) {
+	t.Helper()
+
+	dir := newTestRepo(t)
+
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Prompt = "add a feature"
+	cfg.Contenders = []fighters.FighterType{fighters.FighterTypeClaude, fighters.FighterTypeCodex}
+
+	tournament, err := NewTournament(cfg, nil, &answerObserver{})
+	if err != nil {
+		t.Fatalf("NewTournament() error = %v", err)
+	}
+	if err := tournament.setupWorktrees(); err != nil {
+		t.Fatalf("setupWorktrees() error = %v", err)
+	}
+	for i, c := range tournament.contenders {
+		c.implementer = implementers[i](c.worktreePath)
+		c.panel, err = review.NewPanel(review.Policy{Kind: review.PolicyAny}, &countingReviewer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tournament.newJudge = func(workDir string) (fighters.Implementer, error) {
+		if judge == nil {
+			return nil, errors.New("the judge should not be called")
+		}
+		return judge, nil
+	}
+	return tournament, dir
+}
+
+func writer(dir string) fighters.Implementer { return &fileImplementer{dir: dir} }
+
+func idle(string) fighters.Implementer { return &idleImplementer{} }
+
+func TestTournament_JudgePicksWinner(t *testing.T) {
+	judge := &judgeStub{verdict: "WINNER: 2\nREASONING: Both work, #2 is tidier."}
+	tournament, dir := newTestTournament(t, judge, writer, writer)
+	loser := tournament.contenders[0]
+
+	result, err := tournament.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(judge.prompts) != 1 || !strings.Contains(judge.prompts[0], "=== CONTENDER #2") {
+		t.Fatalf("judge should compare both contenders, got %v", judge.prompts)
+	}
+	tr := result.Tournament
+	if tr == nil || tr.Winner != 2 || !tr.Applied {
+		t.Fatalf("Tournament = %+v, want contender #2 applied", tr)
+	}
+	if tr.Reasoning != "Both work, #2 is tidier." {
+		t.Errorf("Reasoning = %q", tr.Reasoning)
+	}
+	if !result.Success {
+		t.Errorf("Success = false, StopReason = %q", result.StopReason)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+		t.Errorf("the winner's changes should be merged: %v", err)
+	}
+
+	// The loser's branch is kept with its changes, without its worktree
+	if tr.Contenders[0].Branch == "" || !tournament.repo.BranchExists(tr.Contenders[0].Branch) {
+		t.Errorf("loser branch %q should be kept", tr.Contenders[0].Branch)
+	}
+	if loser.worktreePath != "" {
+		t.Errorf("loser worktree %q should be removed", loser.worktreePath)
+	}
+	if tr.Contenders[1].Branch != "" {
+		t.Errorf("winner branch %q should be deleted after merging", tr.Contenders[1].Branch)
+	}
+}
+
+func TestTournament_OnlyContenderWithChanges(t *testing.T) {
+	tournament, dir := newTestTournament(t, nil, idle, writer)
+
+	result, err := tournament.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Tournament.Winner != 2 || !result.Tournament.Applied {
+		t.Fatalf("Tournament = %+v, want contender #2 applied without a judge", result.Tournament)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+		t.Errorf("the winner's changes should be merged: %v", err)
+	}
+}
+
+func TestTournament_JudgeFails(t *testing.T) {
+	judge := &judgeStub{err: errors.New("judge crashed")}
+	tournament, dir := newTestTournament(t, judge, writer, writer)
+
+	result, err := tournament.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Success || result.State != types.StateFailed {
+		t.Errorf("State = %s, Success = %v, want a failed tournament", result.State, result.Success)
+	}
+	if result.Tournament.Winner != 0 || result.Tournament.Applied {
+		t.Errorf("Tournament = %+v, want no winner", result.Tournament)
+	}
+	if !strings.Contains(result.StopReason, "judge crashed") {
+		t.Errorf("StopReason = %q", result.StopReason)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("nothing should be merged without a winner")
+	}
+	for _, c := range result.Tournament.Contenders {
+		if !tournament.repo.BranchExists(c.Branch) {
+			t.Errorf("branch %q of contender #%d should be kept", c.Branch, c.Number)
+		}
+	}
+}
+
+func TestNewTournament_NeedsTwoContenders(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = t.TempDir()
+	cfg.Contenders = []fighters.FighterType{fighters.FighterTypeClaude}
+
+	if _, err := NewTournament(cfg, nil, nil); err == nil {
+		t.Error("NewTournament() should reject a single contender")
+	}
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/diegoram/mortal-prompter/internal/hooks"
+	"github.com/diegoram/mortal-prompter/internal/verify"
+)
+
+// WorktreeBranchPrefix is the prefix of the branches created for sessions in worktree mode.
+const WorktreeBranchPrefix = "mortal-prompter/"
+
+// setupWorktree creates the session branch and worktree, or reuses them when
+// resuming, and points git and every fighter at the worktree.
+func (o *Orchestrator) setupWorktree() error {
+	if o.worktreePath == "" {
+		commonDir, err := o.repo.CommonDir()
+		if err != nil {
+			return fmt.Errorf("failed to locate git directory: %w", err)
+		}
+		o.branch = WorktreeBranchPrefix + o.sessionID
+		o.worktreePath = filepath.Join(commonDir, "mortal-prompter", "worktrees", o.sessionID)
+	}
+
+	if _, err := os.Stat(o.worktreePath); os.IsNotExist(err) {
+		if err := o.repo.AddWorktree(o.worktreePath, o.branch); err != nil {
+			return fmt.Errorf("failed to create worktree: %w", err)
+		}
+		o.info(fmt.Sprintf("Created worktree %s on branch %s", o.worktreePath, o.branch))
+	} else {
+		o.info(fmt.Sprintf("Using worktree %s on branch %s", o.worktreePath, o.branch))
+	}
+
+	return o.useWorkDir(o.worktreePath)
+}
+
+// useWorkDir rebuilds git and the fighters so that they all operate in workDir.
+func (o *Orchestrator) useWorkDir(workDir string) error {
+	implementer, err := newImplementer(o.config, o.config.Implementer, workDir)
+	if err != nil {
+		return fmt.Errorf("invalid implementer: %w", err)
+	}
+
+	planner, err := newPlanner(o.config, workDir)
+	if err != nil {
+		return err
+	}
+
+	panel, err := newPanel(o.config, workDir)
+	if err != nil {
+		return err
+	}
+
+	o.implementer = implementer
+	o.planner = planner
+	o.panel = panel
+	o.verifier = verify.New(workDir, o.config.Verify, verify.DefaultTimeout)
+	o.hooks = hooks.New(workDir, o.config.Hooks, hooks.DefaultTimeout)
+	o.git = newGit(o.config, workDir)
+	return nil
+}
+
+// finishWorktree commits the session's changes to its branch and asks whether
+// to merge the branch into the current branch, delete it, or keep it.
+func (o *Orchestrator) finishWorktree() {
+	if err := o.autoCommit(); err != nil {
+		o.warn(fmt.Errorf("failed to commit to %s: %w", o.branch, err))
+		o.info(fmt.Sprintf("Keeping branch %s (worktree: %s)", o.branch, o.worktreePath))
+		return
+	}
+
+	if o.confirm(fmt.Sprintf("Merge branch %s into the current branch?", o.branch)) {
+		if err := o.repo.Merge(o.branch); err != nil {
+			o.warn(err)
+			o.info(fmt.Sprintf("Keeping branch %s (worktree: %s)", o.branch, o.worktreePath))
+			return
+		}
+		o.info(fmt.Sprintf("Merged branch %s", o.branch))
+		o.removeWorktree()
+		return
+	}
+
+	if o.confirm(fmt.Sprintf("Delete branch %s and its worktree?", o.branch)) {
+		o.removeWorktree()
+		return
+	}
+
+	o.info(fmt.Sprintf("Keeping branch %s (worktree: %s)", o.branch, o.worktreePath))
+}
+
+// removeWorktree deletes the session worktree and branch.
+func (o *Orchestrator) removeWorktree() {
+	if err := o.repo.RemoveWorktree(o.worktreePath); err != nil {
+		o.warn(fmt.Errorf("failed to remove worktree: %w", err))
+		return
+	}
+	if err := o.repo.DeleteBranch(o.branch); err != nil {
+		o.warn(fmt.Errorf("failed to delete branch: %w", err))
+		return
+	}
+
+	o.info(fmt.Sprintf("Deleted branch %s and its worktree", o.branch))
+	o.branch = ""
+	o.worktreePath = ""
+}
@@ -0,0 +1,161 @@
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// answerObserver is an Observer that answers confirmations from a fixed list
+// and records the events published.
+type answerObserver struct {
+	answers  []bool
+	messages []string
+
+	// mu guards published, since tournament contenders publish concurrently
+	mu        sync.Mutex
+	published []events.Event
+}
+
+func (a *answerObserver) HandleEvent(e events.Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.published = append(a.published, e)
+}
+
+func (a *answerObserver) OnPlanProposed(plan types.Plan) (string, bool) {
+	approved, _ := a.Confirm(confirm.Question{Message: "Approve this plan?"})
+	return plan.Text, approved
+}
+
+func (a *answerObserver) Confirm(q confirm.Question) (bool, error) {
+	a.messages = append(a.messages, q.Message)
+	if len(a.answers) == 0 {
+		return false, nil
+	}
+	answer := a.answers[0]
+	a.answers = a.answers[1:]
+	return answer, nil
+}
+
+// newWorktreeOrchestrator creates an orchestrator in worktree mode on a fresh
+// repository with one commit.
+func newWorktreeOrchestrator(t *testing.T, observer Observer) (*Orchestrator, string) {
+	t.Helper()
+
+	dir := newTestRepo(t)
+
+	cfg := config.New()
+	cfg.WorkDir = dir
+	cfg.OutputDir = t.TempDir()
+	cfg.Worktree = true
+
+	orch, err := NewWithObserver(cfg, nil, observer)
+	if err != nil {
+		t.Fatalf("NewWithObserver() error = %v", err)
+	}
+	if err := orch.setupWorktree(); err != nil {
+		t.Fatalf("setupWorktree() error = %v", err)
+	}
+	return orch, dir
+}
+
+func TestSetupWorktree(t *testing.T) {
+	orch, dir := newWorktreeOrchestrator(t, &answerObserver{})
+
+	if !strings.HasPrefix(orch.branch, WorktreeBranchPrefix) {
+		t.Errorf("branch = %q, want prefix %q", orch.branch, WorktreeBranchPrefix)
+	}
+	if orch.git.WorkDir() != orch.worktreePath {
+		t.Errorf("git works in %q, want the worktree %q", orch.git.WorkDir(), orch.worktreePath)
+	}
+	if orch.repo.WorkDir() != dir {
+		t.Errorf("repo works in %q, want %q", orch.repo.WorkDir(), dir)
+	}
+	if !orch.repo.BranchExists(orch.branch) {
+		t.Errorf("branch %s was not created", orch.branch)
+	}
+
+	// Setting up again, as on resume, reuses the same worktree
+	path := orch.worktreePath
+	if err := orch.setupWorktree(); err != nil {
+		t.Fatalf("setupWorktree() again error = %v", err)
+	}
+	if orch.worktreePath != path {
+		t.Errorf("worktreePath changed from %q to %q", path, orch.worktreePath)
+	}
+}
+
+func TestFinishWorktree_Merge(t *testing.T) {
+	observer := &answerObserver{answers: []bool{true}}
+	orch, dir := newWorktreeOrchestrator(t, observer)
+	branch := orch.branch
+
+	if err := os.WriteFile(filepath.Join(orch.worktreePath, "feature.txt"), []byte("feature"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := orch.git.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	orch.finishWorktree()
+
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+		t.Errorf("merged changes should be in the working tree: %v", err)
+	}
+	if orch.repo.BranchExists(branch) {
+		t.Errorf("branch %s should be deleted after merging", branch)
+	}
+	if orch.branch != "" {
+		t.Errorf("branch = %q, want empty after removal", orch.branch)
+	}
+	if len(observer.messages) != 1 {
+		t.Errorf("expected only the merge question, got %v", observer.messages)
+	}
+}
+
+func TestFinishWorktree_Keep(t *testing.T) {
+	observer := &answerObserver{answers: []bool{false, false}}
+	orch, dir := newWorktreeOrchestrator(t, observer)
+
+	if err := os.WriteFile(filepath.Join(orch.worktreePath, "feature.txt"), []byte("feature"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := orch.git.StageAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	orch.finishWorktree()
+
+	if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+		t.Error("changes should not reach the working tree when the branch is kept")
+	}
+	if !orch.repo.BranchExists(orch.branch) {
+		t.Errorf("branch %s should be kept", orch.branch)
+	}
+	if hasChanges, _ := orch.git.HasUncommittedChanges(); hasChanges {
+		t.Error("changes should be committed to the session branch")
+	}
+}
+
+func TestFinishWorktree_Delete(t *testing.T) {
+	observer := &answerObserver{answers: []bool{false, true}}
+	orch, _ := newWorktreeOrchestrator(t, observer)
+	branch, path := orch.branch, orch.worktreePath
+
+	orch.finishWorktree()
+
+	if orch.repo.BranchExists(branch) {
+		t.Errorf("branch %s should be deleted", branch)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+}
@@ -0,0 +1,73 @@
+// Package proc runs the external commands of a session (the fighters'
+// CLIs, verification commands and hooks) in a process group of their own,
+// so that stopping a command also stops every process it started.
+//
+// A command whose context is done is first asked to terminate, and killed
+// along with its whole group if it is still running after GracePeriod.
+package proc
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// GracePeriod is how long a command has to exit after being asked to
+// terminate before its process group is killed.
+const GracePeriod = 5 * time.Second
+
+// waitDelay is how long to wait for the output of a killed group to close,
+// in case a process left the group and kept it open.
+const waitDelay = time.Second
+
+var (
+	mu      sync.Mutex
+	running = make(map[*exec.Cmd]struct{})
+)
+
+// CommandContext is like exec.CommandContext, except that the command runs
+// in its own process group, and that the whole group is terminated, then
+// killed after GracePeriod, when ctx is done.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		// The group outlives its leader, so kill it even if the leader exited
+		time.AfterFunc(GracePeriod, func() { kill(cmd.Process) })
+		return terminate(cmd.Process)
+	}
+	cmd.WaitDelay = GracePeriod + waitDelay
+	return cmd
+}
+
+// Run starts cmd and waits for it to complete, like cmd.Run, keeping track
+// of it so that KillAll can kill it.
+func Run(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	running[cmd] = struct{}{}
+	mu.Unlock()
+
+	defer func() {
+		mu.Lock()
+		delete(running, cmd)
+		mu.Unlock()
+	}()
+
+	return cmd.Wait()
+}
+
+// KillAll kills the process groups of the commands being run, without a
+// grace period, for a forced quit.
+func KillAll() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for cmd := range running {
+		kill(cmd.Process)
+	}
+}
@@ -0,0 +1,113 @@
+//go:build !windows
+
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startTree runs a shell that starts a long-lived child, writes the child's
+// PID to a file and waits for it. It returns the path of the PID file.
+func startTree(t *testing.T, ctx context.Context, script string, errc chan<- error) string {
+	t.Helper()
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	cmd := CommandContext(ctx, "sh", "-c", script+" & echo $! > "+pidFile+"; wait")
+	go func() { errc <- Run(cmd) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(pidFile); err == nil && strings.HasSuffix(string(data), "\n") {
+			return pidFile
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("child process did not start")
+	return ""
+}
+
+// waitGone waits for the process in pidFile to exit.
+func waitGone(t *testing.T, pidFile string, within time.Duration) {
+	t.Helper()
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(within)
+	for time.Now().Before(deadline) {
+		if !alive(pid) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+	t.Errorf("child process %d still running after %v", pid, within)
+}
+
+// alive reports whether the process pid is running. A zombie is not, as the
+// orphans of a killed group may never be reaped in a container.
+func alive(pid int) bool {
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return syscall.Kill(pid, 0) == nil
+	}
+	return !strings.HasPrefix(strings.TrimSpace(string(out)), "Z")
+}
+
+func TestCommandContext_TerminatesGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	pidFile := startTree(t, ctx, "sleep 30", errc)
+
+	cancel()
+	select {
+	case err := <-errc:
+		if err == nil {
+			t.Error("Run() error = nil, want the cancellation")
+		}
+	case <-time.After(GracePeriod):
+		t.Fatal("Run() did not return after the context was cancelled")
+	}
+	waitGone(t, pidFile, time.Second)
+}
+
+func TestCommandContext_KillsAfterGracePeriod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the grace period")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	pidFile := startTree(t, ctx, "trap '' TERM; sleep 30", errc)
+
+	cancel()
+	waitGone(t, pidFile, GracePeriod+2*time.Second)
+	<-errc
+}
+
+func TestKillAll(t *testing.T) {
+	errc := make(chan error, 1)
+	pidFile := startTree(t, context.Background(), "trap '' TERM; sleep 30", errc)
+
+	KillAll()
+	select {
+	case <-errc:
+	case <-time.After(GracePeriod):
+		t.Fatal("Run() did not return after KillAll()")
+	}
+	waitGone(t, pidFile, time.Second)
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup makes cmd the root of a new process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminate asks the process tree rooted at p to exit.
+func terminate(p *os.Process) error {
+	return taskkill(p, false)
+}
+
+// kill kills the process tree rooted at p.
+func kill(p *os.Process) error {
+	return taskkill(p, true)
+}
+
+// taskkill stops the process tree rooted at p with taskkill, forcefully if
+// force is set. Windows has no process group signals to do it with.
+func taskkill(p *os.Process, force bool) error {
+	if p == nil {
+		return nil
+	}
+	args := []string{"/T", "/PID", strconv.Itoa(p.Pid)}
+	if force {
+		args = append([]string{"/F"}, args...)
+	}
+	if err := exec.Command("taskkill", args...).Run(); err != nil && force {
+		// The tree may be gone already; make sure the root is
+		return p.Kill()
+	}
+	return nil
+}
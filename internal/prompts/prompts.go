@@ -0,0 +1,328 @@
+// Package prompts renders the prompts sent to the fighters from text/template
+// templates. Built-in templates cover every prompt; a language set replaces
+// some or all of them, and a project can override any template, for all
+// fighters or for a single one.
+package prompts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Name identifies a prompt template.
+type Name string
+
+const (
+	// Implement is the implementer's prompt for a round: the task, or the
+	// issues to fix after the first round
+	Implement Name = "implement"
+
+	// Review asks a reviewer to review a diff
+	Review Name = "review"
+
+	// PlanReview asks a reviewer to critique a plan before any code is written
+	PlanReview Name = "plan_review"
+
+	// ParseReview asks a fighter to extract the issues from a review
+	ParseReview Name = "parse_review"
+
+	// Plan asks the planner to turn the task into a plan
+	Plan Name = "plan"
+
+	// PlanRevision asks the planner to revise its plan with the reviewers' critique
+	PlanRevision Name = "plan_revision"
+
+	// Judge asks the judge of a tournament to pick the best contender
+	Judge Name = "judge"
+
+	// ReviewRules are the instructions on what to report, shared by the review templates
+	ReviewRules Name = "review_rules"
+)
+
+// Names returns the names of the templates a project can override.
+func Names() []Name {
+	return []Name{Implement, Review, PlanReview, ParseReview, Plan, PlanRevision, Judge, ReviewRules}
+}
+
+// ValidName reports whether name is a template a project can override.
+func ValidName(name Name) bool {
+	for _, n := range Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Data is what the templates are rendered with. Each template only gets the
+// fields that make sense for it.
+type Data struct {
+	// Prompt is the original task of the session
+	Prompt string
+
+	// Round is the number of the round, 0 outside of the battle rounds
+	Round int
+
+	// Issues are the issues the implementer must fix (Implement), or the
+	// reviewers' critique of the plan (PlanRevision)
+	Issues []string
+
+	// Plan is the approved plan, or the plan being reviewed or revised
+	Plan string
+
+	// Stats are the size of the diff under review (Review), or of the diff
+	// reviewed in the previous round (Implement)
+	Stats DiffStats
+
+	// Diff is the git diff under review
+	Diff string
+
+	// Range is the revision range of the diff, empty for the working tree
+	Range string
+
+	// OpenIssues are the issues of earlier rounds the reviewer must check
+	OpenIssues []types.Issue
+
+	// Chunk is the part of a diff reviewed in parts, nil for a whole diff
+	Chunk *types.ReviewChunk
+
+	// Output is the review to extract the issues from (ParseReview)
+	Output string
+
+	// Contenders are the implementations the judge compares (Judge)
+	Contenders []Contender
+}
+
+// Contender is an implementation compared by the judge of a tournament.
+type Contender struct {
+	// Number is the contender's number, which the judge answers with
+	Number int
+
+	// Implementer is the display name of the contender's implementer
+	Implementer string
+
+	// Status describes how the contender's battle ended
+	Status string
+
+	// Approved is whether the reviewers approved the contender's changes
+	Approved bool
+
+	// Rounds is the number of rounds the contender fought
+	Rounds int
+
+	// StopReason is why the contender's battle stopped without an approval
+	StopReason string
+
+	// Issues are the review issues still open when the contender stopped
+	Issues []string
+
+	// Diff is the contender's final diff, possibly truncated
+	Diff string
+}
+
+// DiffStats is the size of a diff.
+type DiffStats struct {
+	Files      int
+	Insertions int
+	Deletions  int
+}
+
+// Stats counts the files, added and deleted lines of diff.
+func Stats(diff string) DiffStats {
+	var stats DiffStats
+	header := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			stats.Files++
+			header = true
+		case strings.HasPrefix(line, "@@"):
+			header = false
+		case header:
+		case strings.HasPrefix(line, "+"):
+			stats.Insertions++
+		case strings.HasPrefix(line, "-"):
+			stats.Deletions++
+		}
+	}
+	return stats
+}
+
+//go:embed templates
+var builtin embed.FS
+
+// defaultSet is the directory of the built-in templates, which every language set starts from.
+const defaultSet = "default"
+
+// Languages returns the languages of the shipped template sets.
+func Languages() []string {
+	entries, err := fs.ReadDir(builtin, "templates")
+	if err != nil {
+		return nil
+	}
+	var languages []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != defaultSet {
+			languages = append(languages, entry.Name())
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// ValidLanguage reports whether language names a shipped template set. The
+// empty language stands for the built-in templates alone.
+func ValidLanguage(language string) bool {
+	if language == "" {
+		return true
+	}
+	for _, l := range Languages() {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Overrides are templates replacing the built-in ones, as template text by name.
+type Overrides map[Name]string
+
+// Templates is a parsed set of prompt templates.
+type Templates struct {
+	set *template.Template
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultTemplates *Templates
+)
+
+// Default returns the built-in templates.
+func Default() *Templates {
+	defaultOnce.Do(func() {
+		t, err := Load("", nil)
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in prompt templates: %v", err))
+		}
+		defaultTemplates = t
+	})
+	return defaultTemplates
+}
+
+// Load parses the built-in templates, then the templates of the language set,
+// if any, then overrides. Every template is rendered once with sample data so
+// that mistakes such as unknown fields show up now rather than mid-session.
+func Load(language string, overrides Overrides) (*Templates, error) {
+	if !ValidLanguage(language) {
+		return nil, fmt.Errorf("unknown prompt language %q (available: %s)", language, strings.Join(Languages(), ", "))
+	}
+
+	set := template.New("prompts").Funcs(funcs)
+	if err := parseDir(set, defaultSet); err != nil {
+		return nil, err
+	}
+	if language != "" {
+		if err := parseDir(set, language); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !ValidName(Name(name)) {
+			return nil, fmt.Errorf("unknown prompt template %q", name)
+		}
+		// Like the shipped files, an override ends with a single newline,
+		// which keeps the layout of the templates including it
+		text := strings.TrimRight(overrides[Name(name)], "\n") + "\n"
+		if _, err := set.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("invalid %s prompt template: %w", name, err)
+		}
+	}
+
+	t := &Templates{set: set}
+	for _, name := range Names() {
+		if _, err := t.render(name, sample); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// parseDir parses the templates of a shipped set into set. Each file defines
+// the template named after it, and may define others.
+func parseDir(set *template.Template, dir string) error {
+	files, err := fs.Glob(builtin, path.Join("templates", dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		text, err := fs.ReadFile(builtin, file)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if _, err := set.New(name).Parse(string(text)); err != nil {
+			return fmt.Errorf("invalid %s prompt template: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// Render renders the template name with data. Trailing newlines are dropped.
+// If an overridden template fails, the built-in one is rendered instead, so
+// that a session never sends an empty prompt.
+func (t *Templates) Render(name Name, data Data) string {
+	if t == nil {
+		t = Default()
+	}
+	out, err := t.render(name, data)
+	if err != nil && t != Default() {
+		out, _ = Default().render(name, data)
+	}
+	return out
+}
+
+// render executes the template name with data.
+func (t *Templates) render(name Name, data Data) (string, error) {
+	var sb strings.Builder
+	if err := t.set.ExecuteTemplate(&sb, string(name), data); err != nil {
+		return "", fmt.Errorf("failed to render the %s prompt template: %w", name, err)
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// funcs are the functions available to the templates besides the built-in ones.
+var funcs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// sample is the data the templates are checked with when they are loaded.
+var sample = Data{
+	Prompt:     "add caching",
+	Round:      2,
+	Issues:     []string{"[high] cache.go:12: Unchecked error"},
+	Plan:       "1. Add the cache",
+	Stats:      DiffStats{Files: 1, Insertions: 1},
+	Diff:       "diff --git a/cache.go b/cache.go\n+cache",
+	Range:      "HEAD~1..HEAD",
+	OpenIssues: []types.Issue{{ID: "3f2a9c1e", Severity: types.SeverityHigh, File: "cache.go", StartLine: 12, Description: "Unchecked error"}},
+	Chunk:      &types.ReviewChunk{Index: 1, Total: 2, Files: []string{"cache.go"}},
+	Output:     "ISSUE: [high] cache.go:12 - Unchecked error",
+	Contenders: []Contender{{Number: 1, Implementer: "CLAUDE CODE", Status: "approved", Approved: true, Rounds: 1, Diff: "+cache"}},
+}
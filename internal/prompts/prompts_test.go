@@ -0,0 +1,148 @@
+package prompts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,2 @@\n-old\n+new\n+more\n" +
+		"diff --git a/b.go b/b.go\nnew file mode 100644\n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+package b\n"
+
+	want := DiffStats{Files: 2, Insertions: 3, Deletions: 1}
+	if got := Stats(diff); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got := Stats(""); got != (DiffStats{}) {
+		t.Errorf("Stats(\"\") = %+v, want zero", got)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	if got := Languages(); !reflect.DeepEqual(got, []string{"en", "es"}) {
+		t.Errorf("Languages() = %v, want [en es]", got)
+	}
+	if !ValidLanguage("") || !ValidLanguage("es") || ValidLanguage("default") || ValidLanguage("fr") {
+		t.Error("ValidLanguage() should accept the empty language and the shipped sets only")
+	}
+}
+
+func TestDefault_Implement(t *testing.T) {
+	if got := Default().Render(Implement, Data{Prompt: "add caching"}); got != "add caching" {
+		t.Errorf("Render(Implement) without issues = %q, want the prompt", got)
+	}
+
+	got := Default().Render(Implement, Data{Prompt: "add caching", Issues: []string{"Unchecked error"}, Plan: "1. Add the cache"})
+	for _, want := range []string{
+		"ISSUES ENCONTRADOS EN LA REVISION ANTERIOR:\n- Unchecked error\n",
+		"TAREA: Corrige los issues mencionados arriba.",
+		"PLAN APROBADO (siguelo paso a paso):\n1. Add the cache",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render(Implement) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestDefault_Review(t *testing.T) {
+	got := Default().Render(Review, Data{Diff: "+cache", OpenIssues: sample.OpenIssues})
+	for _, want := range []string{
+		`If NO issues respond "LGTM: No issues found".`,
+		"- #3f2a9c1e [high] cache.go:12: Unchecked error\n",
+		"Git diff:\n+cache",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render(Review) = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Approved plan") || strings.Contains(got, "parts") {
+		t.Errorf("Render(Review) = %q, want no plan or part instructions", got)
+	}
+}
+
+func TestLoad_Language(t *testing.T) {
+	en, err := Load("en", nil)
+	if err != nil {
+		t.Fatalf("Load(en) error = %v", err)
+	}
+	got := en.Render(Implement, sample)
+	if !strings.Contains(got, "now in round 2") || !strings.Contains(got, "ORIGINAL TASK:\nadd caching") ||
+		!strings.Contains(got, "1 file(s) (+1 -0 lines)") {
+		t.Errorf("Render(Implement) in en = %q", got)
+	}
+	if got := en.Render(Plan, sample); got != Default().Render(Plan, sample) {
+		t.Errorf("Render(Plan) in en = %q, want the built-in prompt", got)
+	}
+
+	es, err := Load("es", nil)
+	if err != nil {
+		t.Fatalf("Load(es) error = %v", err)
+	}
+	got = es.Render(Review, sample)
+	if !strings.HasPrefix(got, "Revisa el siguiente git diff") || !strings.Contains(got, "LGTM: No issues found") ||
+		!strings.Contains(got, "STATUS: #id resolved|open|disputed") {
+		t.Errorf("Render(Review) in es = %q, want a Spanish prompt with the English keywords", got)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	templates, err := Load("es", Overrides{
+		ReviewRules: `Only report security issues. Answer "LGTM" or "ISSUE: [severity] file:line - description".`,
+		Plan:        "Plan {{upper .Prompt}} in {{len .Issues}} steps or fewer.",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := templates.Render(Plan, Data{Prompt: "add caching"}); got != "Plan ADD CACHING in 0 steps or fewer." {
+		t.Errorf("Render(Plan) = %q", got)
+	}
+	got := templates.Render(Review, sample)
+	if !strings.Contains(got, "\nOnly report security issues. Answer \"LGTM\" or \"ISSUE: [severity] file:line - description\".\n\nLos cambios") {
+		t.Errorf("Render(Review) = %q, want the overridden rules in the Spanish review prompt", got)
+	}
+	if got := templates.Render(Judge, sample); !strings.HasPrefix(got, "Se produjeron") {
+		t.Errorf("Render(Judge) = %q, want the Spanish prompt", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		overrides Overrides
+		want      string
+	}{
+		{"unknown language", "fr", nil, "unknown prompt language"},
+		{"unknown template", "", Overrides{"summary": "{{.Prompt}}"}, `unknown prompt template "summary"`},
+		{"syntax error", "", Overrides{Review: "{{.Diff"}, "invalid review prompt template"},
+		{"unknown field", "", Overrides{Plan: "{{.Task}}"}, "failed to render the plan prompt template"},
+		{"unknown function", "", Overrides{Judge: "{{shout .Prompt}}"}, "invalid judge prompt template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.language, tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_FallsBackToDefault(t *testing.T) {
+	templates, err := Load("", Overrides{Implement: "{{index .Issues 0}}"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The override fails without issues, the built-in prompt is used instead
+	if got := templates.Render(Implement, Data{Prompt: "add caching"}); got != "add caching" {
+		t.Errorf("Render() = %q, want the built-in prompt", got)
+	}
+
+	var none *Templates
+	if got := none.Render(Plan, sample); got != Default().Render(Plan, sample) {
+		t.Errorf("nil Render() = %q, want the built-in prompt", got)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diegoram/mortal-prompter/internal/guard"
+	"github.com/diegoram/mortal-prompter/internal/hooks"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
@@ -57,15 +59,33 @@ func (r *Reporter) generateContent(result *types.SessionResult, initialPrompt st
 	// Summary section
 	r.writeSummary(&sb, result, initialPrompt)
 
+	// Plan approved before the first round
+	r.writePlan(&sb, result.Plan)
+
+	// Tournament contenders and the judge's verdict
+	r.writeTournament(&sb, result.Tournament)
+
 	// Round history
 	r.writeRoundHistory(&sb, result.Rounds)
 
+	// Issues raised across the rounds and what became of them
+	r.writeIssueLifecycle(&sb, result.Ledger)
+
+	// Hooks run at the start and the end of the session
+	if len(result.Hooks) > 0 {
+		sb.WriteString("## Session Hooks\n\n")
+		writeHooks(&sb, result.Hooks)
+	}
+
 	// Final changes
 	r.writeFinalChanges(&sb, result)
 
 	// Files modified
 	r.writeFilesModified(&sb, result.FilesModified)
 
+	// Changes left out by the project's path rules
+	r.writeExcludedPaths(&sb, result)
+
 	return sb.String()
 }
 
@@ -73,14 +93,134 @@ func (r *Reporter) generateContent(result *types.SessionResult, initialPrompt st
 func (r *Reporter) writeSummary(sb *strings.Builder, result *types.SessionResult, initialPrompt string) {
 	sb.WriteString("## Summary\n\n")
 	sb.WriteString(fmt.Sprintf("- **Initial Prompt:** %s\n", initialPrompt))
+	if result.Implementer != "" && result.Reviewer != "" {
+		sb.WriteString(fmt.Sprintf("- **Fighters:** %s vs %s\n", result.Implementer, result.Reviewer))
+	}
+	if result.ReviewPolicy != "" {
+		sb.WriteString(fmt.Sprintf("- **Review Policy:** %s\n", result.ReviewPolicy))
+	}
+	if result.FailOn != "" && result.FailOn != types.SeverityLow {
+		sb.WriteString(fmt.Sprintf("- **Fail On:** %s and above\n", result.FailOn))
+	}
+	if result.Branch != "" {
+		sb.WriteString(fmt.Sprintf("- **Branch:** `%s`\n", result.Branch))
+	}
 	sb.WriteString(fmt.Sprintf("- **Total Rounds:** %d\n", result.TotalRounds))
 	sb.WriteString(fmt.Sprintf("- **Total Duration:** %s\n", formatDuration(result.TotalDuration)))
+	if result.Usage != (types.Usage{}) {
+		sb.WriteString(fmt.Sprintf("- **Usage:** %s\n", formatUsage(result.Usage)))
+	}
 
 	if result.Success {
 		sb.WriteString("- **Result:** SUCCESS - FLAWLESS VICTORY\n")
+	} else if result.State == types.StateStalled {
+		sb.WriteString("- **Result:** STALLED\n")
+	} else if result.State == types.StateBudgetExhausted {
+		sb.WriteString("- **Result:** OUT OF BUDGET\n")
+	} else if result.State == types.StateInterrupted {
+		sb.WriteString("- **Result:** INTERRUPTED\n")
+	} else if result.State == types.StateFailed {
+		sb.WriteString("- **Result:** FAILED\n")
 	} else {
 		sb.WriteString("- **Result:** ABORTED\n")
 	}
+	if result.StopReason != "" {
+		sb.WriteString(fmt.Sprintf("- **Stop Reason:** %s\n", result.StopReason))
+	}
+	sb.WriteString("\n")
+}
+
+// writePlan writes the plan approved in the planning phase.
+func (r *Reporter) writePlan(sb *strings.Builder, plan *types.Plan) {
+	if plan == nil {
+		return
+	}
+
+	sb.WriteString("## Plan\n\n")
+	origin := fmt.Sprintf("Drafted by %s", plan.Planner)
+	if len(plan.Critique) > 0 {
+		origin += fmt.Sprintf(", revised after %d critique issue(s)", len(plan.Critique))
+	}
+	if plan.Edited {
+		origin += ", edited by the user"
+	}
+	sb.WriteString(fmt.Sprintf("*%s*\n\n", origin))
+	sb.WriteString(plan.Text)
+	sb.WriteString("\n\n")
+
+	if len(plan.Critique) > 0 {
+		sb.WriteString("**Critique:**\n\n")
+		for i, issue := range plan.Critique {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, issue))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writeTournament writes the contenders of a tournament and the judge's verdict.
+func (r *Reporter) writeTournament(sb *strings.Builder, tournament *types.TournamentResult) {
+	if tournament == nil {
+		return
+	}
+
+	sb.WriteString("## Tournament\n\n")
+	sb.WriteString("| # | Implementer | Rounds | Result | Open Issues | Branch |\n")
+	sb.WriteString("|---|-------------|--------|--------|-------------|--------|\n")
+	for _, c := range tournament.Contenders {
+		rounds, status, issues := 0, "FAILED", 0
+		if c.Result != nil {
+			rounds = c.Result.TotalRounds
+			if c.Result.Success {
+				status = "LGTM"
+			} else if len(c.Result.Rounds) > 0 {
+				status = "NOT APPROVED"
+				issues = len(c.Result.Rounds[len(c.Result.Rounds)-1].Issues)
+			}
+		}
+		number := fmt.Sprintf("%d", c.Number)
+		if c.Number == tournament.Winner {
+			number += " (winner)"
+		}
+		branch := "-"
+		if c.Branch != "" {
+			branch = "`" + c.Branch + "`"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %d | %s |\n", number, c.Implementer, rounds, status, issues, branch))
+	}
+	sb.WriteString("\n")
+
+	for _, c := range tournament.Contenders {
+		if c.Error != "" {
+			sb.WriteString(fmt.Sprintf("- **Contender #%d Error:** %s\n", c.Number, c.Error))
+		}
+		if c.Result != nil && !c.Result.Success && len(c.Result.Rounds) > 0 {
+			last := c.Result.Rounds[len(c.Result.Rounds)-1]
+			if len(last.Issues) > 0 {
+				sb.WriteString(fmt.Sprintf("\n**Contender #%d Open Issues:**\n\n", c.Number))
+				for i, issue := range last.Issues {
+					sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, issue))
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("- **Judge:** %s\n", tournament.Judge))
+	if tournament.Winner > 0 {
+		sb.WriteString(fmt.Sprintf("- **Winner:** #%d\n", tournament.Winner))
+	} else {
+		sb.WriteString("- **Winner:** none\n")
+	}
+	if tournament.Applied {
+		sb.WriteString("- **Applied:** yes, merged into the current branch\n")
+	} else {
+		sb.WriteString("- **Applied:** no\n")
+	}
+	if tournament.Reasoning != "" {
+		sb.WriteString("\n**Judge's Reasoning:**\n\n")
+		sb.WriteString(tournament.Reasoning)
+		sb.WriteString("\n")
+	}
 	sb.WriteString("\n")
 }
 
@@ -93,27 +233,152 @@ func (r *Reporter) writeRoundHistory(sb *strings.Builder, rounds []types.Round)
 	sb.WriteString("## Round History\n\n")
 
 	for _, round := range rounds {
-		sb.WriteString(fmt.Sprintf("### Round %d\n\n", round.Number))
+		if label := outcomeLabel(round.Outcome); label != "" {
+			sb.WriteString(fmt.Sprintf("### Round %d (%s)\n\n", round.Number, label))
+		} else {
+			sb.WriteString(fmt.Sprintf("### Round %d\n\n", round.Number))
+		}
+
+		implementer := fighterLabel(round.Implementer, "Implementer")
+		reviewer := fighterLabel(round.Reviewer, "Reviewer")
 
 		// Task description
 		if round.Number == 1 {
-			sb.WriteString(fmt.Sprintf("**Claude Code Task:** %s\n\n", truncatePrompt(round.ClaudePrompt, 200)))
+			sb.WriteString(fmt.Sprintf("**%s Task:** %s\n\n", implementer, truncatePrompt(round.ImplementerPrompt, 200)))
 		} else {
-			sb.WriteString("**Claude Code Task:** Fix issues from previous review\n\n")
+			sb.WriteString(fmt.Sprintf("**%s Task:** Fix issues from previous review\n\n", implementer))
 		}
 
 		// Duration
 		sb.WriteString(fmt.Sprintf("**Duration:** %s\n\n", formatDuration(round.Duration)))
+		if round.Usage != (types.Usage{}) {
+			sb.WriteString(fmt.Sprintf("**Usage:** %s\n\n", formatUsage(round.Usage)))
+		}
 
 		// Files changed
 		filesChanged := countFilesInDiff(round.GitDiff)
 		sb.WriteString(fmt.Sprintf("**Files Changed:** %d\n\n", filesChanged))
 
+		// Verification commands run before the review
+		verified := true
+		if len(round.Verification) > 0 {
+			sb.WriteString("**Verification:**\n\n")
+			for _, check := range round.Verification {
+				if check.Passed {
+					sb.WriteString(fmt.Sprintf("- `%s`: passed (%s)\n", check.Command, formatDuration(check.Duration)))
+					continue
+				}
+				verified = false
+				sb.WriteString(fmt.Sprintf("- `%s`: failed with exit code %d (%s)\n\n", check.Command, check.ExitCode, formatDuration(check.Duration)))
+				if output := strings.TrimSpace(check.Output); output != "" {
+					sb.WriteString("```\n")
+					sb.WriteString(output)
+					sb.WriteString("\n```\n")
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		// Guardrails broken by the implementer's changes
+		if len(round.Violations) > 0 {
+			sb.WriteString("**Guardrails:**\n\n")
+			for _, v := range round.Violations {
+				action := "kept"
+				if v.Reverted {
+					action = "reverted"
+				}
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", v.Message, action))
+			}
+			sb.WriteString("\n")
+		}
+
+		// Lifecycle hooks run during the round
+		if len(round.Hooks) > 0 {
+			sb.WriteString("**Hooks:**\n\n")
+			writeHooks(sb, round.Hooks)
+		}
+
+		// Per-reviewer verdicts when a panel reviewed the round
+		if len(round.Reviews) > 1 {
+			sb.WriteString("**Verdicts:**\n\n")
+			for _, review := range round.Reviews {
+				if review.HasIssues {
+					sb.WriteString(fmt.Sprintf("- %s: %d issue(s) found\n", review.Reviewer, len(review.Issues)))
+				} else {
+					sb.WriteString(fmt.Sprintf("- %s: LGTM\n", review.Reviewer))
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		// Parts of a diff too large to review at once
+		if len(round.Chunks) > 0 {
+			sb.WriteString("**Review Parts:**\n\n")
+			for _, chunk := range round.Chunks {
+				part := fmt.Sprintf("Part %d/%d (%d bytes): %s", chunk.Index, chunk.Total, chunk.Bytes, codeList(chunk.Files))
+				if chunk.Index == 0 {
+					part = "Cross-file pass"
+				}
+				switch {
+				case chunk.HasIssues:
+					sb.WriteString(fmt.Sprintf("- %s: %d issue(s) found\n", part, len(chunk.Findings)))
+				case len(chunk.Findings) > 0:
+					sb.WriteString(fmt.Sprintf("- %s: LGTM - %d non-blocking issue(s)\n", part, len(chunk.Findings)))
+				default:
+					sb.WriteString(fmt.Sprintf("- %s: LGTM\n", part))
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		// Verdicts on the issues of earlier rounds
+		if len(round.Verdicts) > 0 {
+			sb.WriteString("**Earlier Issues:**\n\n")
+			for _, v := range round.Verdicts {
+				line := fmt.Sprintf("- `#%s`: %s", v.ID, v.Status)
+				if v.Note != "" {
+					line += " - " + v.Note
+				}
+				if len(round.Reviews) > 1 && v.Reviewer != "" {
+					line += fmt.Sprintf(" (%s)", v.Reviewer)
+				}
+				sb.WriteString(line + "\n")
+			}
+			sb.WriteString("\n")
+		}
+
+		// Why the round ended early
+		if round.Error != "" {
+			sb.WriteString(fmt.Sprintf("**Error:** %s\n\n", round.Error))
+		}
+
 		// Review result
-		if !round.HasIssues {
-			sb.WriteString("**Codex Review:** LGTM - No issues found\n\n")
+		if round.Outcome == types.OutcomeInterrupted && round.ReviewerOutput == "" {
+			sb.WriteString(fmt.Sprintf("**%s Review:** none, the round was interrupted\n\n", reviewer))
+		} else if round.Outcome == types.OutcomeImplementerError {
+			sb.WriteString(fmt.Sprintf("**%s Review:** none, %s failed\n\n", reviewer, implementer))
+		} else if round.Outcome == types.OutcomeReviewerError {
+			sb.WriteString(fmt.Sprintf("**%s Review:** failed\n\n", reviewer))
+		} else if round.Outcome == types.OutcomeNoChanges && !round.HasIssues {
+			sb.WriteString(fmt.Sprintf("**%s Review:** skipped, %s made no changes\n\n", reviewer, implementer))
+		} else if guard.Reverted(round.Violations) {
+			sb.WriteString(fmt.Sprintf("**%s Review:** skipped, changes reverted by the guardrails\n\n", reviewer))
+			writeIssues(sb, round.Findings, false)
+		} else if !verified {
+			sb.WriteString(fmt.Sprintf("**%s Review:** skipped, verification failed\n\n", reviewer))
+		} else if rejectedByHook(round.Hooks) {
+			sb.WriteString(fmt.Sprintf("**%s Review:** skipped, changes vetoed by a hook\n\n", reviewer))
+			writeIssues(sb, round.Findings, false)
+		} else if !round.HasIssues && len(round.Findings) == 0 {
+			sb.WriteString(fmt.Sprintf("**%s Review:** LGTM - No issues found\n\n", reviewer))
+		} else if !round.HasIssues {
+			sb.WriteString(fmt.Sprintf("**%s Review:** LGTM - %d non-blocking issue(s)\n\n", reviewer, len(round.Findings)))
+			writeIssues(sb, round.Findings, len(round.Reviews) > 1)
+		} else if len(round.Findings) > 0 {
+			sb.WriteString(fmt.Sprintf("**%s Review:** %d issue(s) found\n\n", reviewer, len(round.Findings)))
+			writeIssues(sb, round.Findings, len(round.Reviews) > 1)
 		} else {
-			sb.WriteString(fmt.Sprintf("**Codex Review:** %d issue(s) found\n\n", len(round.Issues)))
+			sb.WriteString(fmt.Sprintf("**%s Review:** %d issue(s) found\n\n", reviewer, len(round.Issues)))
 			for i, issue := range round.Issues {
 				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, issue))
 			}
@@ -124,6 +389,131 @@ func (r *Reporter) writeRoundHistory(sb *strings.Builder, rounds []types.Round)
 	}
 }
 
+// outcomeLabel describes the outcome of a round that did not end with a
+// review, or returns an empty string.
+func outcomeLabel(outcome types.RoundOutcome) string {
+	switch outcome {
+	case types.OutcomeNoChanges:
+		return "no changes"
+	case types.OutcomeImplementerError:
+		return "implementer failed"
+	case types.OutcomeReviewerError:
+		return "review failed"
+	case types.OutcomeInterrupted:
+		return "interrupted"
+	default:
+		return ""
+	}
+}
+
+// writeIssueLifecycle writes a table following each issue raised by the
+// reviewers from the round it was raised in to the round it was closed in.
+func (r *Reporter) writeIssueLifecycle(sb *strings.Builder, ledger []types.LedgerEntry) {
+	if len(ledger) == 0 {
+		return
+	}
+
+	sb.WriteString("## Issue Lifecycle\n\n")
+	sb.WriteString("| Issue | Severity | Raised | Status | Closed | Note |\n")
+	sb.WriteString("|-------|----------|--------|--------|--------|------|\n")
+	for _, entry := range ledger {
+		closed := "-"
+		if entry.ClosedIn > 0 {
+			closed = fmt.Sprintf("round %d", entry.ClosedIn)
+		}
+		status := string(entry.Status)
+		if entry.Reopened > 0 {
+			status += fmt.Sprintf(" (reopened %dx)", entry.Reopened)
+		}
+		description := entry.Issue.Description
+		if entry.Issue.File != "" {
+			description = fmt.Sprintf("`%s` %s", entry.Issue.File, description)
+		}
+		sb.WriteString(fmt.Sprintf("| `#%s` %s | %s | round %d | %s | %s | %s |\n",
+			entry.Issue.ID, tableCell(description), entry.Issue.Severity, entry.RaisedIn, status, closed, tableCell(entry.Note)))
+	}
+	sb.WriteString("\n")
+}
+
+// tableCell makes text fit in a markdown table cell.
+func tableCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+// writeHooks writes the outcome of each hook, with the output of those that vetoed their step.
+func writeHooks(sb *strings.Builder, results []types.HookResult) {
+	for _, hook := range results {
+		switch {
+		case hook.Vetoed:
+			sb.WriteString(fmt.Sprintf("- %s `%s`: vetoed with exit code %d (%s)\n\n", hook.Hook, hook.Command, hook.ExitCode, formatDuration(hook.Duration)))
+			if output := strings.TrimSpace(hook.Output); output != "" {
+				sb.WriteString("```\n")
+				sb.WriteString(output)
+				sb.WriteString("\n```\n")
+			}
+		case len(hook.Issues) > 0:
+			sb.WriteString(fmt.Sprintf("- %s `%s`: raised %d issue(s) (%s)\n", hook.Hook, hook.Command, len(hook.Issues), formatDuration(hook.Duration)))
+		default:
+			sb.WriteString(fmt.Sprintf("- %s `%s`: passed (%s)\n", hook.Hook, hook.Command, formatDuration(hook.Duration)))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// rejectedByHook reports whether a hook vetoed the changes of a round before
+// they were reviewed.
+func rejectedByHook(results []types.HookResult) bool {
+	for _, hook := range results {
+		if hook.Vetoed && hook.Hook != string(hooks.AfterReview) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeIssues writes structured issues grouped by severity, most serious
+// first, and by file within a severity. When attribute is true each issue
+// names the reviewers that reported it.
+func writeIssues(sb *strings.Builder, findings []types.Issue, attribute bool) {
+	issues := append([]types.Issue(nil), findings...)
+	types.SortIssues(issues)
+
+	for i, issue := range issues {
+		newSeverity := i == 0 || issue.Severity != issues[i-1].Severity
+		if newSeverity {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(fmt.Sprintf("*%s*\n\n", strings.ToUpper(string(issue.Severity))))
+		}
+		if newSeverity || issue.File != issues[i-1].File {
+			if issue.File != "" {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", issue.File))
+			} else {
+				sb.WriteString("- General\n")
+			}
+		}
+
+		line := issue.Description
+		if issue.Category != "" {
+			line = fmt.Sprintf("[%s] %s", issue.Category, line)
+		}
+		if issue.StartLine > 0 {
+			lines := fmt.Sprintf("L%d", issue.StartLine)
+			if issue.EndLine > issue.StartLine {
+				lines += fmt.Sprintf("-%d", issue.EndLine)
+			}
+			line = lines + ": " + line
+		}
+		if attribute && issue.Reviewer != "" {
+			line += fmt.Sprintf(" (%s)", issue.Reviewer)
+		}
+		sb.WriteString("  - " + strings.ReplaceAll(line, "\n", "\n    ") + "\n")
+	}
+	sb.WriteString("\n")
+}
+
 // writeFinalChanges writes the final git diff section.
 func (r *Reporter) writeFinalChanges(sb *strings.Builder, result *types.SessionResult) {
 	sb.WriteString("## Final Changes\n\n")
@@ -162,6 +552,41 @@ func (r *Reporter) writeFilesModified(sb *strings.Builder, files []string) {
 	sb.WriteString("\n")
 }
 
+// writeExcludedPaths writes the path rules of the project and the changed
+// paths they left out of the review and the commit.
+func (r *Reporter) writeExcludedPaths(sb *strings.Builder, result *types.SessionResult) {
+	if result.PathRules.Empty() {
+		return
+	}
+
+	sb.WriteString("## Excluded Paths\n\n")
+	if len(result.PathRules.Include) > 0 {
+		sb.WriteString(fmt.Sprintf("**Include:** %s\n\n", codeList(result.PathRules.Include)))
+	}
+	if len(result.PathRules.Exclude) > 0 {
+		sb.WriteString(fmt.Sprintf("**Exclude:** %s\n\n", codeList(result.PathRules.Exclude)))
+	}
+
+	if len(result.ExcludedPaths) == 0 {
+		sb.WriteString("*No changes left out*\n\n")
+		return
+	}
+	sb.WriteString("Changed but not reviewed or committed:\n\n")
+	for _, path := range result.ExcludedPaths {
+		sb.WriteString(fmt.Sprintf("- `%s`\n", path))
+	}
+	sb.WriteString("\n")
+}
+
+// codeList formats items as a comma-separated list of inline code.
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "`" + item + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
 // formatDuration formats a duration in a human-readable way.
 func formatDuration(d time.Duration) string {
 	if d < time.Second {
@@ -178,6 +603,19 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dm %ds", minutes, seconds)
 }
 
+// formatUsage formats the cost and token counts reported by fighters.
+func formatUsage(u types.Usage) string {
+	return fmt.Sprintf("$%.2f (%d input / %d output tokens)", u.CostUSD, u.InputTokens, u.OutputTokens)
+}
+
+// fighterLabel returns the fighter name, or fallback for rounds recorded without one.
+func fighterLabel(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
 // truncatePrompt truncates a prompt to maxLen characters, adding ellipsis if needed.
 func truncatePrompt(prompt string, maxLen int) string {
 	// Remove newlines for cleaner display
@@ -28,24 +28,30 @@ func TestGenerateReport(t *testing.T) {
 
 	result := &types.SessionResult{
 		Success:       true,
+		Implementer:   "GEMINI",
+		Reviewer:      "CLAUDE CODE",
 		TotalRounds:   2,
 		TotalDuration: 5 * time.Minute,
 		Rounds: []types.Round{
 			{
-				Number:       1,
-				ClaudePrompt: "implement user authentication",
-				GitDiff:      "diff --git a/auth.go b/auth.go\n+++ b/auth.go\n+package auth",
-				HasIssues:    true,
-				Issues:       []string{"Missing error handling", "No input validation"},
-				Duration:     2 * time.Minute,
+				Number:            1,
+				Implementer:       "GEMINI",
+				Reviewer:          "CLAUDE CODE",
+				ImplementerPrompt: "implement user authentication",
+				GitDiff:           "diff --git a/auth.go b/auth.go\n+++ b/auth.go\n+package auth",
+				HasIssues:         true,
+				Issues:            []string{"Missing error handling", "No input validation"},
+				Duration:          2 * time.Minute,
 			},
 			{
-				Number:       2,
-				ClaudePrompt: "fix issues",
-				GitDiff:      "diff --git a/auth.go b/auth.go\n+++ b/auth.go\n+// fixed",
-				HasIssues:    false,
-				Issues:       []string{},
-				Duration:     1 * time.Minute,
+				Number:            2,
+				Implementer:       "GEMINI",
+				Reviewer:          "CLAUDE CODE",
+				ImplementerPrompt: "fix issues",
+				GitDiff:           "diff --git a/auth.go b/auth.go\n+++ b/auth.go\n+// fixed",
+				HasIssues:         false,
+				Issues:            []string{},
+				Duration:          1 * time.Minute,
 			},
 		},
 		FinalDiff:     "diff --git a/auth.go b/auth.go\n+++ b/auth.go\n+complete implementation",
@@ -109,6 +115,15 @@ func TestGenerateReport(t *testing.T) {
 	if !strings.Contains(contentStr, "`auth.go`") {
 		t.Error("Report should list modified files")
 	}
+	if !strings.Contains(contentStr, "GEMINI vs CLAUDE CODE") {
+		t.Error("Report should name the fighters")
+	}
+	if !strings.Contains(contentStr, "**GEMINI Task:**") {
+		t.Error("Report should attribute the task to the implementer")
+	}
+	if !strings.Contains(contentStr, "**CLAUDE CODE Review:**") {
+		t.Error("Report should attribute the review to the reviewer")
+	}
 }
 
 func TestGenerateReportFailure(t *testing.T) {
@@ -295,3 +310,458 @@ func TestGenerateReportEmptyRounds(t *testing.T) {
 		t.Error("Report should indicate no files modified")
 	}
 }
+
+func TestGenerateReportPanelVerdicts(t *testing.T) {
+	r := New(t.TempDir())
+
+	result := &types.SessionResult{
+		Implementer:  "CLAUDE CODE",
+		Reviewer:     "CODEX + GEMINI",
+		ReviewPolicy: "all",
+		TotalRounds:  1,
+		Rounds: []types.Round{
+			{
+				Number:    1,
+				Reviewer:  "CODEX + GEMINI",
+				HasIssues: false,
+				Reviews: []types.ReviewResult{
+					{Reviewer: "CODEX", HasIssues: true, Issues: []string{"Missing tests"}},
+					{Reviewer: "GEMINI", HasIssues: false},
+				},
+			},
+		},
+	}
+
+	content := r.generateContent(result, "add caching")
+
+	expected := []string{
+		"- **Fighters:** CLAUDE CODE vs CODEX + GEMINI",
+		"- **Review Policy:** all",
+		"**Verdicts:**",
+		"- CODEX: 1 issue(s) found",
+		"- GEMINI: LGTM",
+		"**CODEX + GEMINI Review:** LGTM - No issues found",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+}
+
+func TestGenerateReportVerification(t *testing.T) {
+	r := New(t.TempDir())
+
+	result := &types.SessionResult{
+		TotalRounds: 2,
+		Rounds: []types.Round{
+			{
+				Number:    1,
+				Reviewer:  "CODEX",
+				HasIssues: true,
+				Issues:    []string{"Verification command `go test ./...` failed (exit code 1)"},
+				Verification: []types.VerificationResult{
+					{Command: "go build ./...", Passed: true, Duration: 2 * time.Second},
+					{Command: "go test ./...", ExitCode: 1, Output: "--- FAIL: TestParse", Duration: 5 * time.Second},
+				},
+			},
+			{
+				Number:   2,
+				Reviewer: "CODEX",
+				Verification: []types.VerificationResult{
+					{Command: "go build ./...", Passed: true, Duration: 2 * time.Second},
+				},
+			},
+		},
+	}
+
+	content := r.generateContent(result, "add parser")
+
+	expected := []string{
+		"**Verification:**",
+		"- `go build ./...`: passed (2s)",
+		"- `go test ./...`: failed with exit code 1 (5s)",
+		"--- FAIL: TestParse",
+		"**CODEX Review:** skipped, verification failed",
+		"**CODEX Review:** LGTM - No issues found",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+}
+
+func TestGenerateReportHooks(t *testing.T) {
+	r := New(t.TempDir())
+
+	result := &types.SessionResult{
+		TotalRounds: 1,
+		Rounds: []types.Round{
+			{
+				Number:    1,
+				Reviewer:  "CODEX",
+				HasIssues: true,
+				Findings: []types.Issue{
+					{Severity: types.SeverityCritical, Category: "hook", Description: "after_diff hook `./lint.sh` vetoed the changes (exit code 2)", Reviewer: "HOOK"},
+				},
+				Hooks: []types.HookResult{
+					{Hook: "after_implementer", Command: "./check.sh", Issues: []string{"[low] missing changelog"}, Duration: time.Second},
+					{Hook: "after_diff", Command: "./lint.sh", Vetoed: true, ExitCode: 2, Output: "lint: 3 errors", Duration: 2 * time.Second},
+				},
+			},
+		},
+		Hooks: []types.HookResult{
+			{Hook: "on_abort", Command: "notify-send aborted", Duration: time.Second},
+		},
+	}
+
+	content := r.generateContent(result, "add parser")
+
+	expected := []string{
+		"**Hooks:**",
+		"- after_implementer `./check.sh`: raised 1 issue(s) (1s)",
+		"- after_diff `./lint.sh`: vetoed with exit code 2 (2s)",
+		"lint: 3 errors",
+		"**CODEX Review:** skipped, changes vetoed by a hook",
+		"## Session Hooks",
+		"- on_abort `notify-send aborted`: passed (1s)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+}
+
+func TestGenerateReportBranch(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{Branch: "mortal-prompter/2026-01-02_03-04-05-abcd"}, "add caching")
+	if !strings.Contains(content, "- **Branch:** `mortal-prompter/2026-01-02_03-04-05-abcd`") {
+		t.Error("Report should contain the session branch")
+	}
+
+	content = r.generateContent(&types.SessionResult{}, "add caching")
+	if strings.Contains(content, "**Branch:**") {
+		t.Error("Report should not mention a branch outside worktree mode")
+	}
+}
+
+func TestGenerateReportStalled(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		State:      types.StateStalled,
+		StopReason: "Stalled: round 3 reverted the changes back to the state of round 1",
+	}, "add caching")
+
+	if !strings.Contains(content, "- **Result:** STALLED") {
+		t.Error("Report should mark the session as stalled")
+	}
+	if !strings.Contains(content, "- **Stop Reason:** Stalled: round 3 reverted") {
+		t.Error("Report should explain why the session stopped")
+	}
+}
+
+func TestGenerateReportBudget(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		State:      types.StateBudgetExhausted,
+		StopReason: "Spend budget exhausted: $1.20 of $1.00",
+		Usage:      types.Usage{InputTokens: 3000, OutputTokens: 200, CostUSD: 1.2},
+		Rounds: []types.Round{
+			{Number: 1, Usage: types.Usage{InputTokens: 1000, OutputTokens: 50, CostUSD: 0.4}},
+		},
+	}, "add caching")
+
+	expected := []string{
+		"- **Usage:** $1.20 (3000 input / 200 output tokens)",
+		"- **Result:** OUT OF BUDGET",
+		"- **Stop Reason:** Spend budget exhausted: $1.20 of $1.00",
+		"**Usage:** $0.40 (1000 input / 50 output tokens)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+
+	content = r.generateContent(&types.SessionResult{Rounds: []types.Round{{Number: 1}}}, "add caching")
+	if strings.Contains(content, "**Usage:**") {
+		t.Error("Report should not mention usage when no fighter reports it")
+	}
+}
+
+func TestGenerateReportTournament(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		Success:     true,
+		TotalRounds: 1,
+		Tournament: &types.TournamentResult{
+			Contenders: []types.ContenderResult{
+				{Number: 1, Implementer: "CLAUDE", Result: &types.SessionResult{Success: true, TotalRounds: 1}},
+				{Number: 2, Implementer: "CODEX", Branch: "mortal-prompter/b", Result: &types.SessionResult{
+					TotalRounds: 2,
+					Rounds:      []types.Round{{Number: 1}, {Number: 2, HasIssues: true, Issues: []string{"missing tests"}}},
+				}},
+				{Number: 3, Implementer: "GEMINI", Error: "gemini execution failed"},
+			},
+			Judge:     "CLAUDE",
+			Winner:    1,
+			Reasoning: "Contender #1 is simpler and approved.",
+			Applied:   true,
+		},
+	}, "add caching")
+
+	expected := []string{
+		"## Tournament",
+		"| 1 (winner) | CLAUDE | 1 | LGTM | 0 | - |",
+		"| 2 | CODEX | 2 | NOT APPROVED | 1 | `mortal-prompter/b` |",
+		"| 3 | GEMINI | 0 | FAILED | 0 | - |",
+		"- **Contender #3 Error:** gemini execution failed",
+		"**Contender #2 Open Issues:**\n\n1. missing tests",
+		"- **Judge:** CLAUDE",
+		"- **Winner:** #1",
+		"- **Applied:** yes",
+		"Contender #1 is simpler and approved.",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+
+	content = r.generateContent(&types.SessionResult{}, "add caching")
+	if strings.Contains(content, "## Tournament") {
+		t.Error("Report should not have a tournament section for a single battle")
+	}
+}
+
+func TestGenerateReportPlan(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		Plan: &types.Plan{
+			Planner:  "CLAUDE CODE",
+			Text:     "1. Add the cache\n2. Invalidate on write",
+			Critique: []string{"invalidation is missing"},
+			Edited:   true,
+		},
+		Rounds: []types.Round{{Number: 1}},
+	}, "add caching")
+
+	expected := []string{
+		"## Plan",
+		"*Drafted by CLAUDE CODE, revised after 1 critique issue(s), edited by the user*",
+		"1. Add the cache\n2. Invalidate on write",
+		"**Critique:**\n\n1. invalidation is missing",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q", exp)
+		}
+	}
+	if strings.Index(content, "## Plan") > strings.Index(content, "## Round History") {
+		t.Error("the plan should come before the round history")
+	}
+
+	content = r.generateContent(&types.SessionResult{}, "add caching")
+	if strings.Contains(content, "## Plan") {
+		t.Error("Report should not have a plan section without a planning phase")
+	}
+}
+
+func TestGenerateReportGroupsIssues(t *testing.T) {
+	r := New(t.TempDir())
+
+	result := &types.SessionResult{
+		Success:     true,
+		FailOn:      types.SeverityMedium,
+		TotalRounds: 2,
+		Rounds: []types.Round{
+			{
+				Number:    1,
+				Reviewer:  "CODEX",
+				HasIssues: true,
+				Issues:    []string{"[low] b.go:7 - Rename x", "[high] a.go:3-5 - Nil dereference", "[high] Missing tests"},
+				Findings: types.ParseIssues([]string{
+					"[low] b.go:7 - Rename x", "[high] a.go:3-5 - Nil dereference", "[high] [tests] Missing tests",
+				}),
+			},
+			{
+				Number:   2,
+				Reviewer: "CODEX",
+				Issues:   nil,
+				Findings: types.ParseIssues([]string{"[low] b.go:7 - Rename x"}),
+			},
+		},
+	}
+
+	content := r.generateContent(result, "fix it")
+
+	expected := []string{
+		"- **Fail On:** medium and above",
+		"**CODEX Review:** 3 issue(s) found\n\n*HIGH*\n\n- `a.go`\n  - L3-5: Nil dereference\n- General\n  - [tests] Missing tests\n\n*LOW*\n\n- `b.go`\n  - L7: Rename x\n",
+		"**CODEX Review:** LGTM - 1 non-blocking issue(s)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+}
+
+func TestGenerateReportIssueLifecycle(t *testing.T) {
+	r := New(t.TempDir())
+
+	nilDeref := types.ParseIssue("[high] a.go:3 - Nil dereference")
+	naming := types.ParseIssue("[low] Rename x | y")
+	result := &types.SessionResult{
+		Rounds: []types.Round{
+			{Number: 1, Reviewer: "CODEX", HasIssues: true, Findings: []types.Issue{nilDeref, naming}},
+			{
+				Number:   2,
+				Reviewer: "CODEX",
+				Verdicts: []types.IssueVerdict{{ID: nilDeref.ID, Status: types.IssueResolved, Note: "guarded now"}},
+			},
+		},
+		Ledger: []types.LedgerEntry{
+			{Issue: nilDeref, Status: types.IssueResolved, RaisedIn: 1, ClosedIn: 2, Note: "guarded now"},
+			{Issue: naming, Status: types.IssueOpen, RaisedIn: 1},
+		},
+	}
+
+	content := r.generateContent(result, "fix it")
+
+	expected := []string{
+		"**Earlier Issues:**\n\n- `#" + nilDeref.ID + "`: resolved - guarded now\n",
+		"## Issue Lifecycle\n\n| Issue | Severity | Raised | Status | Closed | Note |",
+		"| `#" + nilDeref.ID + "` `a.go` Nil dereference | high | round 1 | resolved | round 2 | guarded now |",
+		"| `#" + naming.ID + "` Rename x \\| y | low | round 1 | open | - |  |",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+
+	content = r.generateContent(&types.SessionResult{Rounds: []types.Round{{Number: 1}}}, "fix it")
+	if strings.Contains(content, "## Issue Lifecycle") {
+		t.Error("Report should not have an issue lifecycle when no issue was raised")
+	}
+}
+
+func TestGenerateReportInterrupted(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		State:      types.StateInterrupted,
+		StopReason: "Interrupted during round 2",
+		Rounds: []types.Round{
+			{Number: 1, Reviewer: "CODEX", HasIssues: true, Issues: []string{"Missing tests"}},
+			{Number: 2, Reviewer: "CODEX", GitDiff: "diff --git a/x.go b/x.go\n", Outcome: types.OutcomeInterrupted},
+		},
+	}, "add caching")
+
+	expected := []string{
+		"- **Result:** INTERRUPTED",
+		"### Round 2 (interrupted)",
+		"**Files Changed:** 1",
+		"**CODEX Review:** none, the round was interrupted",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+}
+
+func TestGenerateReportExcludedPaths(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		State:         types.StateCompleted,
+		PathRules:     types.PathRules{Include: []string{"src/**"}, Exclude: []string{"**/package-lock.json", "vendor"}},
+		ExcludedPaths: []string{"web/package-lock.json"},
+	}, "add caching")
+
+	expected := []string{
+		"## Excluded Paths",
+		"**Include:** `src/**`",
+		"**Exclude:** `**/package-lock.json`, `vendor`",
+		"- `web/package-lock.json`",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+
+	// Without rules there is nothing to list
+	content = r.generateContent(&types.SessionResult{State: types.StateCompleted}, "add caching")
+	if strings.Contains(content, "## Excluded Paths") {
+		t.Errorf("Report without path rules has an Excluded Paths section\n%s", content)
+	}
+}
+
+func TestGenerateReportGuardrails(t *testing.T) {
+	r := New(t.TempDir())
+
+	violations := []types.Violation{
+		{Rule: "protected_path", Path: "migrations/001.sql", Message: "`migrations/001.sql` is protected", Reverted: true},
+		{Rule: "max_lines_deleted", Message: "600 lines were deleted, more than the limit of 500", Reverted: true},
+	}
+	content := r.generateContent(&types.SessionResult{
+		State: types.StateAborted,
+		Rounds: []types.Round{
+			{Number: 1, Reviewer: "CODEX", Outcome: types.OutcomeRejected, HasIssues: true, Violations: violations,
+				Findings: []types.Issue{{Severity: types.SeverityCritical, Category: "guardrail", Description: "Guardrail broken"}}},
+			{Number: 2, Reviewer: "CODEX", Outcome: types.OutcomeReviewed, Violations: []types.Violation{
+				{Rule: "max_files_changed", Message: "30 files were changed, more than the limit of 20"},
+			}},
+		},
+	}, "add caching")
+
+	expected := []string{
+		"**Guardrails:**",
+		"- `migrations/001.sql` is protected (reverted)",
+		"- 600 lines were deleted, more than the limit of 500 (reverted)",
+		"**CODEX Review:** skipped, changes reverted by the guardrails",
+		"- 30 files were changed, more than the limit of 20 (kept)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+}
+
+func TestGenerateReportChunks(t *testing.T) {
+	r := New(t.TempDir())
+
+	content := r.generateContent(&types.SessionResult{
+		State: types.StateCompleted,
+		Rounds: []types.Round{
+			{Number: 1, Reviewer: "CODEX", Outcome: types.OutcomeReviewed, HasIssues: true, Chunks: []types.ChunkReview{
+				{Index: 1, Total: 2, Files: []string{"a.go", "b.go"}, Bytes: 90000},
+				{Index: 2, Total: 2, Files: []string{"c.go"}, Bytes: 40000, HasIssues: true, Findings: []types.Issue{{Description: "Unchecked error"}}},
+				{Total: 2, Files: []string{"a.go", "b.go", "c.go"}, Bytes: 300, Findings: []types.Issue{{Description: "Naming differs"}}},
+			}, Findings: []types.Issue{{Description: "Unchecked error"}, {Description: "Naming differs"}}},
+		},
+	}, "add caching")
+
+	expected := []string{
+		"**Review Parts:**",
+		"- Part 1/2 (90000 bytes): `a.go`, `b.go`: LGTM\n",
+		"- Part 2/2 (40000 bytes): `c.go`: 1 issue(s) found",
+		"- Cross-file pass: LGTM - 1 non-blocking issue(s)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("Report should contain %q\n%s", exp, content)
+		}
+	}
+}
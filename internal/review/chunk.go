@@ -0,0 +1,217 @@
+package review
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Chunk is a part of a diff reviewed on its own.
+type Chunk struct {
+	// Files are the files the part changes
+	Files []string
+
+	// Diff is the diff of the part
+	Diff string
+}
+
+// SplitDiff splits diff into chunks of at most maxBytes. The changes of a
+// file stay together when they fit in a chunk; a larger file is split between
+// its hunks, each of its chunks repeating the file header. A single hunk
+// larger than maxBytes gets a chunk of its own. Diffs that fit, or any diff
+// when maxBytes is not positive, are returned as a single chunk.
+func SplitDiff(diff string, maxBytes int) []Chunk {
+	if maxBytes <= 0 || len(diff) <= maxBytes {
+		return []Chunk{{Files: diffFiles(diff), Diff: diff}}
+	}
+
+	var pieces []Chunk
+	for _, file := range splitFiles(diff) {
+		pieces = append(pieces, splitHunks(file, maxBytes)...)
+	}
+
+	var chunks []Chunk
+	var current Chunk
+	for _, piece := range pieces {
+		if current.Diff != "" && len(current.Diff)+len(piece.Diff) > maxBytes {
+			chunks = append(chunks, current)
+			current = Chunk{}
+		}
+		current.Diff += piece.Diff
+		current.Files = appendFiles(current.Files, piece.Files...)
+	}
+	if current.Diff != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// Outline returns the file and hunk headers of diff without the changed
+// lines, cut to maxBytes when it is longer and maxBytes is positive. It shows
+// the shape of a change too large to review at once.
+func Outline(diff string, maxBytes int) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "),
+			strings.HasPrefix(line, "new file mode"),
+			strings.HasPrefix(line, "deleted file mode"),
+			strings.HasPrefix(line, "rename from "),
+			strings.HasPrefix(line, "rename to "),
+			strings.HasPrefix(line, "Binary files "),
+			strings.HasPrefix(line, "@@"):
+			sb.WriteString(line)
+		}
+	}
+
+	outline := sb.String()
+	if maxBytes > 0 && len(outline) > maxBytes {
+		cut := strings.LastIndex(outline[:maxBytes], "\n") + 1
+		outline = outline[:cut] + "... (outline truncated)\n"
+	}
+	return outline
+}
+
+// Combine merges the panel's results on the parts of a diff into one, as if
+// the whole diff had been reviewed at once: the reviews of each reviewer are
+// joined and the panel's policy decides on them.
+func (p *Panel) Combine(results []*Result) *Result {
+	reviews := make([]types.ReviewResult, len(p.reviewers))
+	for _, result := range results {
+		for i, r := range result.Reviews {
+			merged := &reviews[i]
+			merged.Reviewer = r.Reviewer
+			if r.HasIssues {
+				merged.HasIssues = true
+				merged.Issues = append(merged.Issues, r.Issues...)
+				merged.Findings = append(merged.Findings, r.Findings...)
+			}
+			merged.Verdicts = append(merged.Verdicts, r.Verdicts...)
+			if output := strings.TrimSpace(r.RawOutput); output != "" {
+				if merged.RawOutput != "" {
+					merged.RawOutput += "\n\n"
+				}
+				merged.RawOutput += output
+			}
+			merged.Duration += r.Duration
+			merged.Usage = merged.Usage.Add(r.Usage)
+		}
+	}
+	return p.result(reviews)
+}
+
+// SetBatchUsage replaces the usage of results, reviewed concurrently, with
+// what each reviewer reported over the whole batch since before. The reviews
+// of a batch share the reviewers, so the usage measured around one of them
+// also counts the calls of the others.
+func (p *Panel) SetBatchUsage(results []*Result, before []types.Usage) {
+	for _, result := range results {
+		result.Usage = types.Usage{}
+		for i := range result.Reviews {
+			result.Reviews[i].Usage = types.Usage{}
+		}
+	}
+	if len(results) == 0 {
+		return
+	}
+	first := results[0]
+	for i, usage := range p.Usages() {
+		if i < len(first.Reviews) && i < len(before) {
+			first.Reviews[i].Usage = usage.Sub(before[i])
+			first.Usage = first.Usage.Add(first.Reviews[i].Usage)
+		}
+	}
+}
+
+// splitFiles splits diff into the diffs of its files. Anything before the
+// first file header stays with the first file.
+func splitFiles(diff string) []string {
+	var files []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") && strings.Contains(current.String(), "diff --git ") {
+			files = append(files, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		files = append(files, current.String())
+	}
+	return files
+}
+
+// splitHunks splits the diff of a single file into pieces of at most
+// maxBytes between its hunks, each piece starting with the file header.
+func splitHunks(file string, maxBytes int) []Chunk {
+	files := diffFiles(file)
+	if len(file) <= maxBytes {
+		return []Chunk{{Files: files, Diff: file}}
+	}
+
+	var header string
+	var hunks []string
+	for _, line := range strings.SplitAfter(file, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			hunks = append(hunks, line)
+		case len(hunks) == 0:
+			header += line
+		default:
+			hunks[len(hunks)-1] += line
+		}
+	}
+	if len(hunks) < 2 {
+		return []Chunk{{Files: files, Diff: file}}
+	}
+
+	var pieces []Chunk
+	current := header
+	for i, hunk := range hunks {
+		if i > 0 && len(current)+len(hunk) > maxBytes && current != header {
+			pieces = append(pieces, Chunk{Files: files, Diff: current})
+			current = header
+		}
+		current += hunk
+	}
+	return append(pieces, Chunk{Files: files, Diff: current})
+}
+
+// diffFiles returns the paths of the files changed in diff, in order.
+func diffFiles(diff string) []string {
+	var files []string
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = appendFiles(files, headerPath(strings.TrimPrefix(line, "diff --git ")))
+		}
+	}
+	return files
+}
+
+// headerPath returns the path a file has after the change, from the
+// "a/<path> b/<path>" part of its diff header.
+func headerPath(header string) string {
+	i := strings.LastIndex(header, " b/")
+	if i < 0 {
+		i = strings.LastIndex(header, ` "b/`)
+	}
+	if i < 0 {
+		return header
+	}
+	path := header[i+1:]
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		path = unquoted
+	}
+	return strings.TrimPrefix(path, "b/")
+}
+
+// appendFiles appends the paths not in files yet.
+func appendFiles(files []string, paths ...string) []string {
+	for _, path := range paths {
+		if path != "" && !containsString(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
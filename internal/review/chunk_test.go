@@ -0,0 +1,117 @@
+package review
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// fileDiff returns the diff of a file with the given hunks, each changing
+// size bytes or so.
+func fileDiff(path string, hunks, size int) string {
+	var sb strings.Builder
+	sb.WriteString("diff --git a/" + path + " b/" + path + "\n")
+	sb.WriteString("--- a/" + path + "\n+++ b/" + path + "\n")
+	for i := 0; i < hunks; i++ {
+		sb.WriteString("@@ -1 +1 @@ func f() {\n")
+		sb.WriteString("+" + strings.Repeat("x", size) + "\n")
+	}
+	return sb.String()
+}
+
+func TestSplitDiff(t *testing.T) {
+	diff := fileDiff("a.go", 1, 100) + fileDiff("b.go", 1, 100) + fileDiff("c.go", 1, 100)
+
+	chunks := SplitDiff(diff, 400)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	if !reflect.DeepEqual(chunks[0].Files, []string{"a.go", "b.go"}) || !reflect.DeepEqual(chunks[1].Files, []string{"c.go"}) {
+		t.Errorf("chunk files = %v, %v", chunks[0].Files, chunks[1].Files)
+	}
+	if chunks[0].Diff+chunks[1].Diff != diff {
+		t.Error("the chunks do not add up to the diff")
+	}
+	for i, chunk := range chunks {
+		if len(chunk.Diff) > 400 {
+			t.Errorf("chunk %d is %d bytes, over the limit", i+1, len(chunk.Diff))
+		}
+	}
+
+	if chunks := SplitDiff(diff, 0); len(chunks) != 1 || chunks[0].Diff != diff || len(chunks[0].Files) != 3 {
+		t.Errorf("SplitDiff() without a limit = %+v, want the whole diff", chunks)
+	}
+}
+
+func TestSplitDiff_LargeFile(t *testing.T) {
+	diff := fileDiff("big.go", 4, 100) + fileDiff("small.go", 1, 10)
+
+	chunks := SplitDiff(diff, 420)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2:\n%+v", len(chunks), chunks)
+	}
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk.Diff, "diff --git a/big.go b/big.go\n--- a/big.go\n+++ b/big.go\n@@") {
+			t.Errorf("chunk %d does not start with the file header:\n%s", i+1, chunk.Diff)
+		}
+		if n := strings.Count(chunk.Diff, strings.Repeat("x", 100)); n != 2 {
+			t.Errorf("chunk %d has %d hunks of big.go, want 2", i+1, n)
+		}
+	}
+	if !reflect.DeepEqual(chunks[1].Files, []string{"big.go", "small.go"}) {
+		t.Errorf("last chunk files = %v, want the rest of big.go and small.go", chunks[1].Files)
+	}
+}
+
+func TestOutline(t *testing.T) {
+	diff := "diff --git a/new.go b/new.go\nnew file mode 100644\n--- /dev/null\n+++ b/new.go\n@@ -0,0 +1 @@\n+package main\n" +
+		fileDiff("a.go", 2, 10)
+
+	want := "diff --git a/new.go b/new.go\nnew file mode 100644\n@@ -0,0 +1 @@\n" +
+		"diff --git a/a.go b/a.go\n@@ -1 +1 @@ func f() {\n@@ -1 +1 @@ func f() {\n"
+	if got := Outline(diff, 0); got != want {
+		t.Errorf("Outline() = %q, want %q", got, want)
+	}
+
+	if got := Outline(diff, 60); !strings.HasSuffix(got, "... (outline truncated)\n") || strings.Contains(got, "a.go") {
+		t.Errorf("truncated Outline() = %q", got)
+	}
+}
+
+func TestPanelCombine(t *testing.T) {
+	codex := &stubReviewer{name: "CODEX"}
+	gemini := &stubReviewer{name: "GEMINI"}
+	panel, err := NewPanel(Policy{Kind: PolicyAll}, codex, gemini)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codex.result, gemini.result = issues("a.go:1 - Unchecked error"), issues()
+	first, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "part 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codex.result, gemini.result = issues(), issues("b.go:2 - Race on the cache")
+	second, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "part 2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.HasIssues || second.HasIssues {
+		t.Fatal("neither part should fail the all policy on its own")
+	}
+
+	// Both reviewers found issues in the diff as a whole
+	result := panel.Combine([]*Result{first, second})
+	if !result.HasIssues {
+		t.Error("Combine() should fail the all policy when both reviewers found issues in some part")
+	}
+	if len(result.Reviews) != 2 || len(result.Reviews[0].Issues) != 1 || len(result.Reviews[1].Issues) != 1 {
+		t.Errorf("Reviews = %+v, want one issue each", result.Reviews)
+	}
+	if len(result.Findings) != 2 || len(result.Issues) != 2 {
+		t.Errorf("Findings = %v, Issues = %q, want both issues", result.Findings, result.Issues)
+	}
+}
@@ -0,0 +1,301 @@
+// Package review runs a panel of reviewer fighters over the same diff and
+// combines their verdicts with a consensus policy.
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// Result is the combined outcome of a panel review.
+type Result struct {
+	// HasIssues is the panel verdict according to its policy
+	HasIssues bool
+
+	// Issues are the issues of all reviewers, deduplicated and attributed
+	Issues []string
+
+	// Findings are the structured issues of all reviewers, deduplicated, also
+	// when the verdict passed the round
+	Findings []types.Issue
+
+	// Reviews contains each reviewer's result, in panel order
+	Reviews []types.ReviewResult
+
+	// Verdicts are the reviewers' verdicts on the open issues of the request,
+	// the most cautious one winning when they disagree
+	Verdicts []types.IssueVerdict
+
+	// Usage is the usage reported by all reviewers for this review
+	Usage types.Usage
+
+	// Chunks are the verdicts on the parts of a diff reviewed in parts
+	Chunks []types.ChunkReview
+}
+
+// Panel reviews diffs with several reviewers concurrently.
+type Panel struct {
+	reviewers []fighters.Reviewer
+	policy    Policy
+	failOn    types.Severity
+}
+
+// NewPanel creates a new Panel with the given policy and reviewers.
+// It returns an error if there are no reviewers or the policy cannot be met.
+func NewPanel(policy Policy, reviewers ...fighters.Reviewer) (*Panel, error) {
+	if len(reviewers) == 0 {
+		return nil, errors.New("review panel needs at least one reviewer")
+	}
+	if err := policy.Validate(len(reviewers)); err != nil {
+		return nil, err
+	}
+	return &Panel{
+		reviewers: reviewers,
+		policy:    policy,
+	}, nil
+}
+
+// Name returns the display names of the reviewers joined with " + ".
+func (p *Panel) Name() string {
+	names := make([]string, len(p.reviewers))
+	for i, r := range p.reviewers {
+		names[i] = r.Name()
+	}
+	return strings.Join(names, " + ")
+}
+
+// Reviewers returns the reviewers of the panel.
+func (p *Panel) Reviewers() []fighters.Reviewer {
+	return p.reviewers
+}
+
+// Policy returns the consensus policy of the panel.
+func (p *Panel) Policy() Policy {
+	return p.policy
+}
+
+// SetFailOn sets the lowest severity of the issues that count against a round.
+// A reviewer that only reports less serious issues does not flag the round.
+func (p *Panel) SetFailOn(threshold types.Severity) {
+	p.failOn = threshold
+}
+
+// FailOn returns the lowest severity of the issues that count against a round.
+func (p *Panel) FailOn() types.Severity {
+	return p.failOn
+}
+
+// Usages returns the usage reported so far by each reviewer of the panel, in
+// panel order.
+func (p *Panel) Usages() []types.Usage {
+	usages := make([]types.Usage, len(p.reviewers))
+	for i, reviewer := range p.reviewers {
+		usages[i] = fighters.UsageOf(reviewer)
+	}
+	return usages
+}
+
+// Review runs every reviewer on the request concurrently and combines the results.
+// If any reviewer fails, the remaining reviews are cancelled and the error is returned.
+func (p *Panel) Review(ctx context.Context, req types.ReviewRequest) (*Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reviews := make([]types.ReviewResult, len(p.reviewers))
+	errs := make([]error, len(p.reviewers))
+
+	var wg sync.WaitGroup
+	for i, reviewer := range p.reviewers {
+		wg.Add(1)
+		go func(i int, reviewer fighters.Reviewer) {
+			defer wg.Done()
+
+			start := time.Now()
+			before := fighters.UsageOf(reviewer)
+			result, err := reviewer.Review(ctx, req)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s review failed: %w", reviewer.Name(), err)
+				cancel()
+				return
+			}
+
+			result.Reviewer = reviewer.Name()
+			result.Duration = time.Since(start)
+			result.Usage = fighters.UsageOf(reviewer).Sub(before)
+			// Reviewers that only report issues as text get them parsed here
+			findings := result.Findings
+			if result.HasIssues && len(findings) == 0 {
+				findings = types.ParseIssues(result.Issues)
+			}
+			result.Findings = append([]types.Issue(nil), findings...)
+			for j := range result.Findings {
+				result.Findings[j].Reviewer = result.Reviewer
+			}
+			result.Verdicts = knownVerdicts(result.Verdicts, req.OpenIssues)
+			for j := range result.Verdicts {
+				result.Verdicts[j].Reviewer = result.Reviewer
+			}
+			reviews[i] = *result
+		}(i, reviewer)
+	}
+	wg.Wait()
+
+	// Report the first reviewer that failed on its own, not one cancelled because of it
+	var cancelled error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		if cancelled == nil {
+			cancelled = err
+		}
+	}
+	if cancelled != nil {
+		return nil, cancelled
+	}
+
+	return p.result(reviews), nil
+}
+
+// result combines the reviews of the panel's reviewers by its policy.
+func (p *Panel) result(reviews []types.ReviewResult) *Result {
+	flagged := 0
+	var usage types.Usage
+	for _, r := range reviews {
+		if r.HasIssues && types.CountAtLeast(r.Findings, p.failOn) > 0 {
+			flagged++
+		}
+		usage = usage.Add(r.Usage)
+	}
+
+	result := &Result{
+		HasIssues: p.policy.HasIssues(flagged, len(reviews)),
+		Findings:  MergeFindings(reviews),
+		Reviews:   reviews,
+		Verdicts:  types.MergeVerdicts(reviews),
+		Usage:     usage,
+	}
+	if result.HasIssues {
+		result.Issues = MergeIssues(reviews, len(reviews) > 1)
+	}
+	return result
+}
+
+// MergeIssues combines the issues of the reviews that found any, dropping
+// duplicates reported by several reviewers. When attribute is true each issue
+// is prefixed with the names of the reviewers that reported it.
+func MergeIssues(reviews []types.ReviewResult, attribute bool) []string {
+	type merged struct {
+		text      string
+		reviewers []string
+	}
+
+	var order []*merged
+	byKey := make(map[string]*merged)
+
+	for _, r := range reviews {
+		if !r.HasIssues {
+			continue
+		}
+		for _, issue := range r.Issues {
+			key := normalizeIssue(issue)
+			if key == "" {
+				continue
+			}
+			m, ok := byKey[key]
+			if !ok {
+				m = &merged{text: strings.TrimSpace(issue)}
+				byKey[key] = m
+				order = append(order, m)
+			}
+			if !containsString(m.reviewers, r.Reviewer) {
+				m.reviewers = append(m.reviewers, r.Reviewer)
+			}
+		}
+	}
+
+	issues := make([]string, 0, len(order))
+	for _, m := range order {
+		if attribute && len(m.reviewers) > 0 {
+			issues = append(issues, fmt.Sprintf("[%s] %s", strings.Join(m.reviewers, ", "), m.text))
+		} else {
+			issues = append(issues, m.text)
+		}
+	}
+	return issues
+}
+
+// MergeFindings combines the structured issues of the reviews that found any.
+// An issue reported by several reviewers is kept once, with the highest
+// severity it was given and the names of all its reviewers.
+func MergeFindings(reviews []types.ReviewResult) []types.Issue {
+	var findings []types.Issue
+	byID := make(map[string]int)
+
+	for _, r := range reviews {
+		if !r.HasIssues {
+			continue
+		}
+		for _, issue := range r.Findings {
+			i, ok := byID[issue.ID]
+			if !ok {
+				byID[issue.ID] = len(findings)
+				findings = append(findings, issue)
+				continue
+			}
+			merged := &findings[i]
+			if !merged.Severity.AtLeast(issue.Severity) {
+				merged.Severity = issue.Severity
+			}
+			switch {
+			case merged.Reviewer == "":
+				merged.Reviewer = issue.Reviewer
+			case issue.Reviewer != "" && !containsString(strings.Split(merged.Reviewer, ", "), issue.Reviewer):
+				merged.Reviewer += ", " + issue.Reviewer
+			}
+		}
+	}
+	return findings
+}
+
+// knownVerdicts returns the verdicts on the issues in open, dropping those on
+// issues the reviewer was not asked about.
+func knownVerdicts(verdicts []types.IssueVerdict, open []types.Issue) []types.IssueVerdict {
+	var known []types.IssueVerdict
+	for _, v := range verdicts {
+		for _, issue := range open {
+			if issue.ID == v.ID {
+				known = append(known, v)
+				break
+			}
+		}
+	}
+	return known
+}
+
+// normalizeIssue returns the comparison key of an issue: lower-cased, with
+// collapsed whitespace and without trailing punctuation.
+func normalizeIssue(issue string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(issue), " "))
+	return strings.TrimRight(key, ".;:!")
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
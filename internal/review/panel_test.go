@@ -0,0 +1,294 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// stubReviewer is a Reviewer returning a fixed result.
+type stubReviewer struct {
+	name   string
+	result *types.ReviewResult
+	err    error
+}
+
+func (s *stubReviewer) Name() string { return s.name }
+
+func (s *stubReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	result := *s.result
+	return &result, nil
+}
+
+func issues(list ...string) *types.ReviewResult {
+	return &types.ReviewResult{HasIssues: len(list) > 0, Issues: list}
+}
+
+func TestNewPanel(t *testing.T) {
+	if _, err := NewPanel(Policy{Kind: PolicyAny}); err == nil {
+		t.Error("NewPanel() without reviewers should return an error")
+	}
+
+	codex := &stubReviewer{name: "CODEX", result: issues()}
+	if _, err := NewPanel(Policy{Kind: PolicyQuorum, Quorum: 2}, codex); err == nil {
+		t.Error("NewPanel() with a quorum larger than the panel should return an error")
+	}
+
+	gemini := &stubReviewer{name: "GEMINI", result: issues()}
+	panel, err := NewPanel(Policy{Kind: PolicyAll}, codex, gemini)
+	if err != nil {
+		t.Fatalf("NewPanel() error = %v", err)
+	}
+	if panel.Name() != "CODEX + GEMINI" {
+		t.Errorf("Name() = %q, want CODEX + GEMINI", panel.Name())
+	}
+}
+
+func TestPanelReview_SingleReviewer(t *testing.T) {
+	codex := &stubReviewer{name: "CODEX", result: issues("Missing tests", "No docs")}
+	panel, err := NewPanel(Policy{Kind: PolicyAny}, codex)
+	if err != nil {
+		t.Fatalf("NewPanel() error = %v", err)
+	}
+
+	result, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	if !result.HasIssues {
+		t.Error("Review() HasIssues = false, want true")
+	}
+	// A single reviewer's issues are passed through without attribution
+	if !reflect.DeepEqual(result.Issues, []string{"Missing tests", "No docs"}) {
+		t.Errorf("Review() Issues = %v", result.Issues)
+	}
+	if len(result.Reviews) != 1 || result.Reviews[0].Reviewer != "CODEX" {
+		t.Errorf("Review() Reviews = %+v", result.Reviews)
+	}
+}
+
+func TestPanelReview_Policies(t *testing.T) {
+	reviewers := []*stubReviewer{
+		{name: "CLAUDE CODE", result: issues("Missing tests")},
+		{name: "CODEX", result: issues()},
+		{name: "GEMINI", result: issues("missing tests.", "SQL injection in query")},
+	}
+
+	tests := []struct {
+		policy Policy
+		want   bool
+	}{
+		{Policy{Kind: PolicyAny}, true},
+		{Policy{Kind: PolicyAll}, false},
+		{Policy{Kind: PolicyQuorum, Quorum: 2}, true},
+		{Policy{Kind: PolicyQuorum, Quorum: 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			panel, err := NewPanel(tt.policy, reviewers[0], reviewers[1], reviewers[2])
+			if err != nil {
+				t.Fatalf("NewPanel() error = %v", err)
+			}
+
+			result, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"})
+			if err != nil {
+				t.Fatalf("Review() error = %v", err)
+			}
+			if result.HasIssues != tt.want {
+				t.Errorf("Review() HasIssues = %v, want %v", result.HasIssues, tt.want)
+			}
+			if len(result.Reviews) != 3 {
+				t.Fatalf("Review() returned %d reviews, want 3", len(result.Reviews))
+			}
+			for i, r := range result.Reviews {
+				if r.Reviewer != reviewers[i].name {
+					t.Errorf("Reviews[%d].Reviewer = %q, want %q", i, r.Reviewer, reviewers[i].name)
+				}
+			}
+			if !tt.want && len(result.Issues) != 0 {
+				t.Errorf("Review() Issues = %v, want none when the policy passes the round", result.Issues)
+			}
+		})
+	}
+}
+
+func TestPanelReview_Error(t *testing.T) {
+	codex := &stubReviewer{name: "CODEX", result: issues()}
+	gemini := &stubReviewer{name: "GEMINI", err: errors.New("quota exceeded")}
+
+	panel, err := NewPanel(Policy{Kind: PolicyAny}, codex, gemini)
+	if err != nil {
+		t.Fatalf("NewPanel() error = %v", err)
+	}
+
+	if _, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"}); err == nil {
+		t.Error("Review() should return an error when a reviewer fails")
+	}
+}
+
+// meteredReviewer is a stubReviewer that reports a fixed cost per review.
+type meteredReviewer struct {
+	stubReviewer
+	cost  float64
+	total types.Usage
+}
+
+func (m *meteredReviewer) Review(ctx context.Context, req types.ReviewRequest) (*types.ReviewResult, error) {
+	m.total = m.total.Add(types.Usage{OutputTokens: 10, CostUSD: m.cost})
+	return m.stubReviewer.Review(ctx, req)
+}
+
+func (m *meteredReviewer) Usage() types.Usage { return m.total }
+
+func TestPanelReview_Usage(t *testing.T) {
+	claude := &meteredReviewer{stubReviewer: stubReviewer{name: "CLAUDE CODE", result: issues()}, cost: 0.5,
+		total: types.Usage{CostUSD: 3}}
+	codex := &stubReviewer{name: "CODEX", result: issues()}
+	panel, err := NewPanel(Policy{Kind: PolicyAny}, claude, codex)
+	if err != nil {
+		t.Fatalf("NewPanel() error = %v", err)
+	}
+
+	result, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	// Only the usage of this review counts, not what the fighter spent before
+	if result.Reviews[0].Usage.CostUSD != 0.5 || result.Reviews[0].Usage.OutputTokens != 10 {
+		t.Errorf("reviewer usage = %+v, want the cost of one review", result.Reviews[0].Usage)
+	}
+	if result.Reviews[1].Usage != (types.Usage{}) {
+		t.Errorf("reviewer without usage reporting has usage %+v", result.Reviews[1].Usage)
+	}
+	if result.Usage.CostUSD != 0.5 {
+		t.Errorf("panel usage = %+v, want 0.5 USD", result.Usage)
+	}
+}
+
+func TestMergeIssues(t *testing.T) {
+	reviews := []types.ReviewResult{
+		{Reviewer: "CLAUDE CODE", HasIssues: true, Issues: []string{"Missing tests", "Unused import"}},
+		{Reviewer: "CODEX", HasIssues: false, Issues: []string{"ignored because LGTM"}},
+		{Reviewer: "GEMINI", HasIssues: true, Issues: []string{"missing  tests.", "SQL injection"}},
+	}
+
+	got := MergeIssues(reviews, true)
+	want := []string{
+		"[CLAUDE CODE, GEMINI] Missing tests",
+		"[CLAUDE CODE] Unused import",
+		"[GEMINI] SQL injection",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeIssues() = %v, want %v", got, want)
+	}
+
+	got = MergeIssues(reviews, false)
+	want = []string{"Missing tests", "Unused import", "SQL injection"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeIssues() without attribution = %v, want %v", got, want)
+	}
+}
+
+func TestPanelReview_FailOn(t *testing.T) {
+	nits := &stubReviewer{name: "CODEX", result: issues("[low] src/main.go:3 - Rename x")}
+	bug := &stubReviewer{name: "GEMINI", result: issues("[high] src/main.go:10 - Nil dereference")}
+
+	panel, err := NewPanel(Policy{Kind: PolicyAny}, nits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	panel.SetFailOn(types.SeverityMedium)
+
+	result, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if result.HasIssues {
+		t.Error("Review() HasIssues = true, want false when only issues below the threshold remain")
+	}
+	if len(result.Findings) != 1 || result.Findings[0].Severity != types.SeverityLow {
+		t.Errorf("Review() Findings = %+v, want the low-severity issue kept", result.Findings)
+	}
+	if result.Findings[0].Reviewer != "CODEX" {
+		t.Errorf("Review() Findings reviewer = %q, want CODEX", result.Findings[0].Reviewer)
+	}
+
+	panel, err = NewPanel(Policy{Kind: PolicyAny}, nits, bug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	panel.SetFailOn(types.SeverityMedium)
+
+	result, err = panel.Review(context.Background(), types.ReviewRequest{Diff: "diff"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if !result.HasIssues {
+		t.Error("Review() HasIssues = false, want true with an issue above the threshold")
+	}
+	if len(result.Issues) != 2 {
+		t.Errorf("Review() Issues = %v, want the nit sent back along with the bug", result.Issues)
+	}
+}
+
+func TestMergeFindings(t *testing.T) {
+	reviews := []types.ReviewResult{
+		{Reviewer: "CLAUDE CODE", HasIssues: true, Findings: []types.Issue{
+			{ID: "a", Severity: types.SeverityLow, Description: "Missing tests", Reviewer: "CLAUDE CODE"},
+		}},
+		{Reviewer: "CODEX", HasIssues: false, Findings: []types.Issue{
+			{ID: "b", Severity: types.SeverityHigh, Description: "ignored because LGTM", Reviewer: "CODEX"},
+		}},
+		{Reviewer: "GEMINI", HasIssues: true, Findings: []types.Issue{
+			{ID: "a", Severity: types.SeverityHigh, Description: "missing tests", Reviewer: "GEMINI"},
+			{ID: "c", Severity: types.SeverityMedium, Description: "SQL injection", Reviewer: "GEMINI"},
+		}},
+	}
+
+	got := MergeFindings(reviews)
+	want := []types.Issue{
+		{ID: "a", Severity: types.SeverityHigh, Description: "Missing tests", Reviewer: "CLAUDE CODE, GEMINI"},
+		{ID: "c", Severity: types.SeverityMedium, Description: "SQL injection", Reviewer: "GEMINI"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeFindings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPanelReview_Verdicts(t *testing.T) {
+	issue := types.ParseIssue("[high] main.go:12 - Unchecked error")
+	codex := &stubReviewer{name: "CODEX", result: &types.ReviewResult{Verdicts: []types.IssueVerdict{
+		{ID: issue.ID, Status: types.IssueResolved},
+		// Verdicts on issues the reviewer was not asked about are dropped
+		{ID: "ffffffff", Status: types.IssueOpen},
+	}}}
+	gemini := &stubReviewer{name: "GEMINI", result: &types.ReviewResult{Verdicts: []types.IssueVerdict{
+		{ID: issue.ID, Status: types.IssueOpen, Note: "still ignored"},
+	}}}
+	panel, err := NewPanel(Policy{Kind: PolicyAny}, codex, gemini)
+	if err != nil {
+		t.Fatalf("NewPanel() error = %v", err)
+	}
+
+	result, err := panel.Review(context.Background(), types.ReviewRequest{Diff: "diff", OpenIssues: []types.Issue{issue}})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	want := []types.IssueVerdict{{ID: issue.ID, Status: types.IssueOpen, Note: "still ignored", Reviewer: "CODEX, GEMINI"}}
+	if !reflect.DeepEqual(result.Verdicts, want) {
+		t.Errorf("Review() Verdicts = %+v, want %+v", result.Verdicts, want)
+	}
+	if len(result.Reviews[0].Verdicts) != 1 || result.Reviews[0].Verdicts[0].Reviewer != "CODEX" {
+		t.Errorf("Reviews[0].Verdicts = %+v, want the known verdict attributed", result.Reviews[0].Verdicts)
+	}
+}
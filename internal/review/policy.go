@@ -0,0 +1,84 @@
+package review
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PolicyKind identifies how reviewer verdicts are combined.
+type PolicyKind string
+
+const (
+	// PolicyAny flags the round when at least one reviewer finds issues
+	PolicyAny PolicyKind = "any"
+
+	// PolicyAll flags the round only when every reviewer finds issues
+	PolicyAll PolicyKind = "all"
+
+	// PolicyQuorum flags the round when at least Quorum reviewers find issues
+	PolicyQuorum PolicyKind = "quorum"
+)
+
+// DefaultPolicy is the policy used when none is configured.
+const DefaultPolicy = string(PolicyAny)
+
+// Policy decides whether a round has issues from the verdicts of a reviewer panel.
+type Policy struct {
+	Kind PolicyKind
+
+	// Quorum is the number of reviewers that must find issues (PolicyQuorum only)
+	Quorum int
+}
+
+// ParsePolicy parses a policy specification: "any", "all" or "quorum:N".
+func ParsePolicy(s string) (Policy, error) {
+	spec := strings.ToLower(strings.TrimSpace(s))
+
+	switch {
+	case spec == "" || spec == string(PolicyAny):
+		return Policy{Kind: PolicyAny}, nil
+	case spec == string(PolicyAll):
+		return Policy{Kind: PolicyAll}, nil
+	case strings.HasPrefix(spec, string(PolicyQuorum)+":"):
+		n, err := strconv.Atoi(strings.TrimPrefix(spec, string(PolicyQuorum)+":"))
+		if err != nil || n < 1 {
+			return Policy{}, fmt.Errorf("invalid quorum in review policy %q: must be a positive number", s)
+		}
+		return Policy{Kind: PolicyQuorum, Quorum: n}, nil
+	default:
+		return Policy{}, fmt.Errorf("unknown review policy: %s (valid: any, all, quorum:N)", s)
+	}
+}
+
+// String returns the policy specification in the form accepted by ParsePolicy.
+func (p Policy) String() string {
+	if p.Kind == PolicyQuorum {
+		return fmt.Sprintf("%s:%d", p.Kind, p.Quorum)
+	}
+	if p.Kind == "" {
+		return DefaultPolicy
+	}
+	return string(p.Kind)
+}
+
+// Validate checks that the policy can be satisfied by a panel of the given size.
+func (p Policy) Validate(reviewers int) error {
+	if p.Kind == PolicyQuorum && p.Quorum > reviewers {
+		return fmt.Errorf("review policy %s needs at least %d reviewers, got %d", p, p.Quorum, reviewers)
+	}
+	return nil
+}
+
+// HasIssues reports whether a round has issues given how many of the total
+// reviewers flagged it.
+func (p Policy) HasIssues(flagged, total int) bool {
+	switch p.Kind {
+	case PolicyAll:
+		return total > 0 && flagged == total
+	case PolicyQuorum:
+		return flagged >= p.Quorum
+	default:
+		return flagged > 0
+	}
+}
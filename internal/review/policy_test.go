@@ -0,0 +1,76 @@
+package review
+
+import "testing"
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Policy
+		wantErr bool
+	}{
+		{"", Policy{Kind: PolicyAny}, false},
+		{"any", Policy{Kind: PolicyAny}, false},
+		{"ALL", Policy{Kind: PolicyAll}, false},
+		{"quorum:2", Policy{Kind: PolicyQuorum, Quorum: 2}, false},
+		{"quorum:0", Policy{}, true},
+		{"quorum:x", Policy{}, true},
+		{"majority", Policy{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParsePolicy(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyString(t *testing.T) {
+	for _, spec := range []string{"any", "all", "quorum:3"} {
+		p, err := ParsePolicy(spec)
+		if err != nil {
+			t.Fatalf("ParsePolicy(%q) error = %v", spec, err)
+		}
+		if p.String() != spec {
+			t.Errorf("String() = %q, want %q", p.String(), spec)
+		}
+	}
+}
+
+func TestPolicyHasIssues(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		flagged int
+		total   int
+		want    bool
+	}{
+		{Policy{Kind: PolicyAny}, 0, 3, false},
+		{Policy{Kind: PolicyAny}, 1, 3, true},
+		{Policy{Kind: PolicyAll}, 2, 3, false},
+		{Policy{Kind: PolicyAll}, 3, 3, true},
+		{Policy{Kind: PolicyAll}, 0, 0, false},
+		{Policy{Kind: PolicyQuorum, Quorum: 2}, 1, 3, false},
+		{Policy{Kind: PolicyQuorum, Quorum: 2}, 2, 3, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.HasIssues(tt.flagged, tt.total); got != tt.want {
+			t.Errorf("%s.HasIssues(%d, %d) = %v, want %v", tt.policy, tt.flagged, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	p := Policy{Kind: PolicyQuorum, Quorum: 3}
+	if err := p.Validate(2); err == nil {
+		t.Error("Validate() should fail when the quorum exceeds the panel size")
+	}
+	if err := p.Validate(3); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+// Package session persists mortal-prompter session checkpoints so that an
+// interrupted battle can be resumed from the next round.
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// CheckpointVersion is the version of the checkpoint file format.
+const CheckpointVersion = 1
+
+// sessionsDir is the subdirectory of the output directory holding session checkpoints.
+const sessionsDir = "sessions"
+
+// checkpointFile is the name of the checkpoint file inside a session directory.
+const checkpointFile = "checkpoint.json"
+
+// ErrSessionNotFound is returned when no checkpoint exists for a session ID.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Settings holds the subset of the configuration needed to rebuild a session.
+type Settings struct {
+	WorkDir       string                 `json:"work_dir"`
+	Implementer   fighters.FighterType   `json:"implementer"`
+	Reviewers     []fighters.FighterType `json:"reviewers"`
+	ReviewPolicy  string                 `json:"review_policy"`
+	FailOn        types.Severity         `json:"fail_on,omitempty"`
+	MaxIterations int                    `json:"max_iterations"`
+	Interactive   bool                   `json:"interactive"`
+	AutoCommit    bool                   `json:"auto_commit"`
+	CommitMessage string                 `json:"commit_message"`
+	Worktree      bool                   `json:"worktree"`
+	OnStall       string                 `json:"on_stall"`
+	OnMaxIter     string                 `json:"on_max_iterations,omitempty"`
+	OnNoChanges   string                 `json:"on_no_changes,omitempty"`
+	OnDirty       string                 `json:"on_dirty,omitempty"`
+	Verify        []string               `json:"verify,omitempty"`
+	Plan          bool                   `json:"plan,omitempty"`
+	Planner       fighters.FighterType   `json:"planner,omitempty"`
+
+	FighterTimeout time.Duration `json:"fighter_timeout,omitempty"`
+	RoundTimeout   time.Duration `json:"round_timeout,omitempty"`
+	SessionTimeout time.Duration `json:"session_timeout,omitempty"`
+	MaxCost        float64       `json:"max_cost,omitempty"`
+
+	ReviewChunkSize int  `json:"review_chunk_size,omitempty"`
+	ParallelChunks  bool `json:"parallel_chunks,omitempty"`
+
+	PromptLanguage string `json:"prompt_language,omitempty"`
+}
+
+// SettingsFromConfig captures the resumable settings from cfg.
+func SettingsFromConfig(cfg *config.Config) Settings {
+	return Settings{
+		WorkDir:       cfg.WorkDir,
+		Implementer:   cfg.Implementer,
+		Reviewers:     append([]fighters.FighterType(nil), cfg.Reviewers...),
+		ReviewPolicy:  cfg.ReviewPolicy,
+		FailOn:        cfg.FailOn,
+		MaxIterations: cfg.MaxIterations,
+		Interactive:   cfg.Interactive,
+		AutoCommit:    cfg.AutoCommit,
+		CommitMessage: cfg.CommitMessage,
+		Worktree:      cfg.Worktree,
+		OnStall:       cfg.OnStall,
+		OnMaxIter:     cfg.OnMaxIterations,
+		OnNoChanges:   cfg.OnNoChanges,
+		OnDirty:       cfg.OnDirty,
+		Verify:        append([]string(nil), cfg.Verify...),
+		Plan:          cfg.Plan,
+		Planner:       cfg.Planner,
+
+		FighterTimeout: cfg.FighterTimeout,
+		RoundTimeout:   cfg.RoundTimeout,
+		SessionTimeout: cfg.SessionTimeout,
+		MaxCost:        cfg.MaxCost,
+
+		ReviewChunkSize: cfg.ReviewChunkSize,
+		ParallelChunks:  cfg.ParallelChunks,
+
+		PromptLanguage: cfg.PromptLanguage,
+	}
+}
+
+// Apply copies the stored settings into cfg.
+func (s Settings) Apply(cfg *config.Config) {
+	cfg.WorkDir = s.WorkDir
+	cfg.Implementer = s.Implementer
+	cfg.Reviewers = append([]fighters.FighterType(nil), s.Reviewers...)
+	cfg.ReviewPolicy = s.ReviewPolicy
+	if s.FailOn != "" {
+		cfg.FailOn = s.FailOn
+	}
+	cfg.MaxIterations = s.MaxIterations
+	cfg.Interactive = s.Interactive
+	cfg.AutoCommit = s.AutoCommit
+	cfg.CommitMessage = s.CommitMessage
+	cfg.Worktree = s.Worktree
+	if s.OnStall != "" {
+		cfg.OnStall = s.OnStall
+	}
+	if s.OnMaxIter != "" {
+		cfg.OnMaxIterations = s.OnMaxIter
+	}
+	if s.OnNoChanges != "" {
+		cfg.OnNoChanges = s.OnNoChanges
+	}
+	if s.OnDirty != "" {
+		cfg.OnDirty = s.OnDirty
+	}
+	cfg.Verify = append([]string(nil), s.Verify...)
+	cfg.Plan = s.Plan
+	cfg.Planner = s.Planner
+	if s.FighterTimeout > 0 {
+		cfg.FighterTimeout = s.FighterTimeout
+	}
+	cfg.RoundTimeout = s.RoundTimeout
+	cfg.SessionTimeout = s.SessionTimeout
+	cfg.MaxCost = s.MaxCost
+	if s.ReviewChunkSize > 0 {
+		cfg.ReviewChunkSize = s.ReviewChunkSize
+	}
+	cfg.ParallelChunks = s.ParallelChunks
+	cfg.PromptLanguage = s.PromptLanguage
+}
+
+// Checkpoint is the persisted state of a session after its last completed round.
+type Checkpoint struct {
+	// Version is the checkpoint file format version
+	Version int `json:"version"`
+
+	// ID is the unique session identifier
+	ID string `json:"id"`
+
+	// State is the session state when the checkpoint was written
+	State types.SessionState `json:"state"`
+
+	// Prompt is the initial prompt of the session
+	Prompt string `json:"prompt"`
+
+	// ImagePath is the image attached to the first round, if any
+	ImagePath string `json:"image_path,omitempty"`
+
+	// Settings are the configuration values the session was started with
+	Settings Settings `json:"settings"`
+
+	// Branch is the session branch in worktree mode
+	Branch string `json:"branch,omitempty"`
+
+	// WorktreePath is the path of the session worktree in worktree mode
+	WorktreePath string `json:"worktree_path,omitempty"`
+
+	// Baseline is the snapshot of the working tree the session started from;
+	// the diffs of its rounds are relative to it
+	Baseline string `json:"baseline,omitempty"`
+
+	// KeptChanges is set when the session started on uncommitted changes,
+	// which are left out of its commit
+	KeptChanges bool `json:"kept_changes,omitempty"`
+
+	// BaselineIndex is the tree of what was staged when the session started
+	// on uncommitted changes, put back after its commit
+	BaselineIndex string `json:"baseline_index,omitempty"`
+
+	// Stash is the stash holding the uncommitted changes set aside when the
+	// session started, restored when it ends
+	Stash string `json:"stash,omitempty"`
+
+	// Plan is the plan approved before round 1, if there was a planning phase
+	Plan *types.Plan `json:"plan,omitempty"`
+
+	// Rounds contains every completed round
+	Rounds []types.Round `json:"rounds"`
+
+	// PendingIssues are the issues to feed into the next round
+	PendingIssues []string `json:"pending_issues"`
+
+	// StartedAt is when the session was first started
+	StartedAt time.Time `json:"started_at"`
+
+	// UpdatedAt is when the checkpoint was last written
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NextRound returns the number of the round a resumed session starts with.
+func (c *Checkpoint) NextRound() int {
+	return len(c.Rounds) + 1
+}
+
+// Resumable reports whether the session can be continued.
+func (c *Checkpoint) Resumable() bool {
+	return c.State != types.StateCompleted
+}
+
+// Interrupted reports whether the session stopped without reaching a final
+// decision, either because the process died or because it was cancelled.
+func (c *Checkpoint) Interrupted() bool {
+	switch c.State {
+	case types.StateRunning, types.StateWaitingConfirmation, types.StateInterrupted:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewID generates a new session identifier based on the current time.
+// A short random suffix keeps concurrent sessions apart.
+func NewID() string {
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return time.Now().Format("2006-01-02_15-04-05")
+	}
+	return time.Now().Format("2006-01-02_15-04-05") + "-" + hex.EncodeToString(suffix)
+}
+
+// SnapshotRef returns the hidden ref holding the snapshot of the given round.
+func SnapshotRef(id string, round int) string {
+	return fmt.Sprintf("refs/mortal-prompter/%s/round-%d", id, round)
+}
+
+// BaselineRef returns the hidden ref holding the baseline of the session.
+func BaselineRef(id string) string {
+	return fmt.Sprintf("refs/mortal-prompter/%s/baseline", id)
+}
+
+// Store reads and writes checkpoints under an output directory.
+type Store struct {
+	outputDir string
+}
+
+// NewStore creates a new Store for the given output directory.
+func NewStore(outputDir string) *Store {
+	return &Store{
+		outputDir: outputDir,
+	}
+}
+
+// Dir returns the directory holding the files of the given session.
+func (s *Store) Dir(id string) string {
+	return filepath.Join(s.outputDir, sessionsDir, id)
+}
+
+// Save writes the checkpoint atomically, replacing any previous one.
+func (s *Store) Save(cp *Checkpoint) error {
+	if cp.ID == "" {
+		return errors.New("checkpoint has no session ID")
+	}
+
+	dir := s.Dir(cp.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	cp.Version = CheckpointVersion
+	cp.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated checkpoint
+	tmp := filepath.Join(dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, checkpointFile)); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the checkpoint of the given session.
+func (s *Store) Load(id string) (*Checkpoint, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir(id), checkpointFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %w", id, err)
+	}
+	if cp.Version > CheckpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has unsupported version %d", id, cp.Version)
+	}
+
+	return &cp, nil
+}
+
+// List returns all readable checkpoints, most recently updated first.
+func (s *Store) List() ([]*Checkpoint, error) {
+	entries, err := os.ReadDir(filepath.Join(s.outputDir, sessionsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		cp, err := s.Load(entry.Name())
+		if err != nil {
+			// Skip sessions without a readable checkpoint
+			continue
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].UpdatedAt.After(checkpoints[j].UpdatedAt)
+	})
+
+	return checkpoints, nil
+}
+
+// Latest returns the most recently updated session, or nil if there is none.
+func (s *Store) Latest() (*Checkpoint, error) {
+	checkpoints, err := s.List()
+	if err != nil || len(checkpoints) == 0 {
+		return nil, err
+	}
+	return checkpoints[0], nil
+}
+
+// LatestInterrupted returns the most recently updated interrupted session,
+// or nil if there is none.
+func (s *Store) LatestInterrupted() (*Checkpoint, error) {
+	checkpoints, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range checkpoints {
+		if cp.Interrupted() {
+			return cp, nil
+		}
+	}
+	return nil, nil
+}
+
+// Rollback restores the working tree of a session to the snapshot taken after
+// the given round and truncates the checkpoint so that resuming continues with
+// the following round, fed with that round's issues.
+func (s *Store) Rollback(id string, round int) (*Checkpoint, error) {
+	cp, err := s.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if round < 1 || round > len(cp.Rounds) {
+		return nil, fmt.Errorf("session %s has no round %d (rounds: %d)", id, round, len(cp.Rounds))
+	}
+
+	g := git.New(cp.workDir())
+	sha := cp.Rounds[round-1].SnapshotSHA
+	if sha == "" {
+		// Older rounds may only be reachable through their ref
+		if sha, err = g.ResolveRef(SnapshotRef(id, round)); err != nil {
+			return nil, fmt.Errorf("round %d of session %s has no snapshot", round, id)
+		}
+	}
+
+	if err := g.RestoreSnapshot(sha); err != nil {
+		return nil, fmt.Errorf("failed to restore round %d: %w", round, err)
+	}
+
+	restored := cp.Rounds[round-1]
+	cp.Rounds = cp.Rounds[:round]
+	cp.PendingIssues = nil
+	if restored.HasIssues {
+		cp.PendingIssues = restored.Issues
+	}
+	cp.State = types.StateAborted
+
+	if err := s.Save(cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// workDir returns the directory the session's changes live in: its worktree
+// if it still exists, otherwise the working directory it was started in.
+func (c *Checkpoint) workDir() string {
+	if c.WorktreePath != "" {
+		if _, err := os.Stat(c.WorktreePath); err == nil {
+			return c.WorktreePath
+		}
+	}
+	return c.Settings.WorkDir
+}
@@ -0,0 +1,321 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/config"
+	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/git"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func TestNewID(t *testing.T) {
+	a := NewID()
+	b := NewID()
+
+	if a == "" {
+		t.Fatal("NewID() returned empty ID")
+	}
+	if strings.ContainsAny(a, "/\\ ") {
+		t.Errorf("NewID() = %q, should be safe to use as a directory name", a)
+	}
+	if a == b {
+		t.Errorf("NewID() returned the same ID twice: %q", a)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	cp := &Checkpoint{
+		ID:        "session-1",
+		State:     types.StateRunning,
+		Prompt:    "implement JWT authentication",
+		ImagePath: "/tmp/image.png",
+		Settings: Settings{
+			WorkDir:       "/tmp/project",
+			Implementer:   fighters.FighterTypeGemini,
+			Reviewers:     []fighters.FighterType{fighters.FighterTypeClaude, fighters.FighterTypeCodex},
+			ReviewPolicy:  "all",
+			MaxIterations: 7,
+		},
+		Rounds: []types.Round{
+			{Number: 1, HasIssues: true, Issues: []string{"missing tests"}, Duration: 2 * time.Minute},
+		},
+		PendingIssues: []string{"missing tests"},
+		StartedAt:     time.Now().Add(-time.Hour),
+	}
+
+	if err := store.Save(cp); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(store.Dir("session-1"), checkpointFile)); err != nil {
+		t.Fatalf("checkpoint file not written: %v", err)
+	}
+
+	loaded, err := store.Load("session-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if loaded.Version != CheckpointVersion {
+		t.Errorf("Version = %d, want %d", loaded.Version, CheckpointVersion)
+	}
+	if loaded.Prompt != cp.Prompt || loaded.ImagePath != cp.ImagePath {
+		t.Errorf("Load() prompt/image = %q/%q, want %q/%q", loaded.Prompt, loaded.ImagePath, cp.Prompt, cp.ImagePath)
+	}
+	if !reflect.DeepEqual(loaded.Settings, cp.Settings) {
+		t.Errorf("Load() settings = %+v, want %+v", loaded.Settings, cp.Settings)
+	}
+	if len(loaded.Rounds) != 1 || loaded.Rounds[0].Duration != 2*time.Minute {
+		t.Errorf("Load() rounds = %+v", loaded.Rounds)
+	}
+	if len(loaded.PendingIssues) != 1 || loaded.PendingIssues[0] != "missing tests" {
+		t.Errorf("Load() pending issues = %v", loaded.PendingIssues)
+	}
+	if loaded.NextRound() != 2 {
+		t.Errorf("NextRound() = %d, want 2", loaded.NextRound())
+	}
+}
+
+func TestLoad_NotFound(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	_, err := store.Load("missing")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Load() error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestList_SkipsUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	if err := store.Save(&Checkpoint{ID: "good", State: types.StateCompleted}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := os.MkdirAll(store.Dir("broken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(store.Dir("broken"), checkpointFile), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoints, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].ID != "good" {
+		t.Errorf("List() = %v, want only the readable session", checkpoints)
+	}
+}
+
+func TestList_NoSessions(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	checkpoints, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(checkpoints) != 0 {
+		t.Errorf("List() returned %d checkpoints, want 0", len(checkpoints))
+	}
+}
+
+func TestLatestInterrupted(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for _, cp := range []*Checkpoint{
+		{ID: "old-interrupted", State: types.StateInterrupted},
+		{ID: "crashed", State: types.StateRunning},
+		{ID: "done", State: types.StateCompleted},
+	} {
+		if err := store.Save(cp); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	latest, err := store.LatestInterrupted()
+	if err != nil {
+		t.Fatalf("LatestInterrupted() error = %v", err)
+	}
+	if latest == nil || latest.ID != "crashed" {
+		t.Errorf("LatestInterrupted() = %v, want crashed", latest)
+	}
+}
+
+func TestCheckpointStates(t *testing.T) {
+	tests := []struct {
+		state       types.SessionState
+		resumable   bool
+		interrupted bool
+	}{
+		{types.StateRunning, true, true},
+		{types.StateWaitingConfirmation, true, true},
+		{types.StateInterrupted, true, true},
+		{types.StateAborted, true, false},
+		{types.StateFailed, true, false},
+		{types.StateCompleted, false, false},
+	}
+
+	for _, tt := range tests {
+		cp := &Checkpoint{State: tt.state}
+		if cp.Resumable() != tt.resumable {
+			t.Errorf("Resumable() for %s = %v, want %v", tt.state, cp.Resumable(), tt.resumable)
+		}
+		if cp.Interrupted() != tt.interrupted {
+			t.Errorf("Interrupted() for %s = %v, want %v", tt.state, cp.Interrupted(), tt.interrupted)
+		}
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	cfg := config.New()
+	cfg.WorkDir = "/tmp/project"
+	cfg.Implementer = fighters.FighterTypeCodex
+	cfg.Reviewers = []fighters.FighterType{fighters.FighterTypeGemini, fighters.FighterTypeClaude}
+	cfg.ReviewPolicy = "quorum:2"
+	cfg.MaxIterations = 3
+	cfg.AutoCommit = true
+	cfg.Verify = []string{"go test ./..."}
+	cfg.FighterTimeout = 2 * time.Minute
+	cfg.SessionTimeout = time.Hour
+	cfg.MaxCost = 3
+	cfg.Plan = true
+	cfg.Planner = fighters.FighterTypeGemini
+	cfg.FailOn = types.SeverityHigh
+	cfg.OnMaxIterations = "continue:2"
+	cfg.ReviewChunkSize = 4096
+	cfg.ParallelChunks = true
+	cfg.PromptLanguage = "es"
+
+	settings := SettingsFromConfig(cfg)
+
+	restored := config.New()
+	settings.Apply(restored)
+
+	if restored.WorkDir != cfg.WorkDir || restored.Implementer != cfg.Implementer ||
+		!reflect.DeepEqual(restored.Reviewers, cfg.Reviewers) || restored.ReviewPolicy != cfg.ReviewPolicy ||
+		restored.MaxIterations != cfg.MaxIterations || restored.AutoCommit != cfg.AutoCommit ||
+		!reflect.DeepEqual(restored.Verify, cfg.Verify) || restored.FighterTimeout != cfg.FighterTimeout ||
+		restored.SessionTimeout != cfg.SessionTimeout || restored.MaxCost != cfg.MaxCost ||
+		restored.Plan != cfg.Plan || restored.Planner != cfg.Planner || restored.FailOn != cfg.FailOn ||
+		restored.OnMaxIterations != cfg.OnMaxIterations || restored.ReviewChunkSize != cfg.ReviewChunkSize ||
+		restored.ParallelChunks != cfg.ParallelChunks || restored.PromptLanguage != cfg.PromptLanguage {
+		t.Errorf("Apply() restored %+v, want values from %+v", restored, cfg)
+	}
+}
+
+func TestSnapshotRef(t *testing.T) {
+	if got := SnapshotRef("abc", 3); got != "refs/mortal-prompter/abc/round-3" {
+		t.Errorf("SnapshotRef() = %q", got)
+	}
+}
+
+// newRollbackRepo creates a repository with one commit and returns its path.
+func newRollbackRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@mortal-prompter.local"},
+		{"config", "user.name", "Test User"},
+		{"commit", "--allow-empty", "-m", "initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+func TestRollback(t *testing.T) {
+	dir := newRollbackRepo(t)
+	g := git.New(dir)
+	store := NewStore(t.TempDir())
+
+	cp := &Checkpoint{
+		ID:       "session-1",
+		State:    types.StateCompleted,
+		Settings: Settings{WorkDir: dir},
+	}
+
+	for i, content := range []string{"round one", "round two"} {
+		if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.StageAll(); err != nil {
+			t.Fatal(err)
+		}
+		sha, err := g.Snapshot(SnapshotRef(cp.ID, i+1), content)
+		if err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+		round := types.Round{Number: i + 1, SnapshotSHA: sha}
+		if i == 0 {
+			round.HasIssues = true
+			round.Issues = []string{"needs work"}
+		}
+		cp.Rounds = append(cp.Rounds, round)
+	}
+	if err := store.Save(cp); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := store.Rollback("session-1", 1)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "feature.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "round one" {
+		t.Errorf("feature.txt = %q, want %q", content, "round one")
+	}
+
+	if len(restored.Rounds) != 1 || restored.NextRound() != 2 {
+		t.Errorf("Rollback() kept %d rounds, want 1", len(restored.Rounds))
+	}
+	if len(restored.PendingIssues) != 1 || restored.PendingIssues[0] != "needs work" {
+		t.Errorf("Rollback() pending issues = %v, want [needs work]", restored.PendingIssues)
+	}
+	if !restored.Resumable() {
+		t.Error("a rolled back session should be resumable")
+	}
+
+	saved, err := store.Load("session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Rounds) != 1 {
+		t.Errorf("saved checkpoint has %d rounds, want 1", len(saved.Rounds))
+	}
+}
+
+func TestRollback_UnknownRound(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(&Checkpoint{ID: "session-1", Rounds: []types.Round{{Number: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Rollback("session-1", 2); err == nil {
+		t.Error("Rollback() to a round that does not exist should return an error")
+	}
+	if _, err := store.Rollback("missing", 1); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Rollback() of a missing session error = %v, want ErrSessionNotFound", err)
+	}
+}
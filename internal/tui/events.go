@@ -15,11 +15,18 @@ const (
 	EventFighterAction
 	EventFighterFinish
 	EventChangesDetected
+	EventVerification
+	EventHooks
+	EventGuardrails
+	EventReviewVerdicts
+	EventUsage
 	EventIssuesFound
 	EventNoIssues
+	EventRoundComplete
 	EventSessionComplete
 	EventError
 	EventConfirmationRequired
+	EventPlanProposed
 )
 
 // Event represents an event from the orchestrator
@@ -55,9 +62,42 @@ type ChangesDetectedPayload struct {
 	FileCount int
 }
 
+// VerificationPayload contains the result of each verification command
+type VerificationPayload struct {
+	Results []types.VerificationResult
+}
+
+// HooksPayload contains the result of each lifecycle hook run at a point of the session
+type HooksPayload struct {
+	Point   string
+	Results []types.HookResult
+}
+
+// GuardrailsPayload contains the guardrails the implementer's changes broke
+type GuardrailsPayload struct {
+	Violations []types.Violation
+}
+
+// ReviewVerdictsPayload contains the result of each reviewer on the panel
+// and their verdicts on the issues of earlier rounds
+type ReviewVerdictsPayload struct {
+	Reviews  []types.ReviewResult
+	Verdicts []types.IssueVerdict
+}
+
+// RoundCompletePayload contains how a round ended
+type RoundCompletePayload struct {
+	Outcome types.RoundOutcome
+}
+
+// UsagePayload contains the total usage reported by the fighters so far
+type UsagePayload struct {
+	Total types.Usage
+}
+
 // IssuesFoundPayload contains data for issues found events
 type IssuesFoundPayload struct {
-	Issues []string
+	Issues []types.Issue
 }
 
 // SessionCompletePayload contains data for session complete events
@@ -75,3 +115,16 @@ type ErrorPayload struct {
 type ConfirmationPayload struct {
 	Message string
 }
+
+// PlanProposedPayload contains the plan awaiting approval and the channel
+// on which the TUI sends the user's decision
+type PlanProposedPayload struct {
+	Plan  types.Plan
+	Reply chan<- PlanDecision
+}
+
+// PlanDecision is the user's answer to a proposed plan
+type PlanDecision struct {
+	Text     string
+	Approved bool
+}
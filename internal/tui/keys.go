@@ -17,6 +17,7 @@ type KeyMap struct {
 	Down        key.Binding
 	PasteImage  key.Binding
 	RemoveImage key.Binding
+	Rollback    key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -74,6 +75,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+x"),
 			key.WithHelp("ctrl+x", "remove image"),
 		),
+		Rollback: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "roll back to round"),
+		),
 	}
 }
 
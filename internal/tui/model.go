@@ -23,6 +23,8 @@ const (
 	ViewBattle
 	ViewResults
 	ViewConfirmation
+	ViewResume
+	ViewPlan
 )
 
 // FighterSelectField represents which field is being edited in fighter selection
@@ -44,13 +46,26 @@ const (
 
 // RoundDisplay holds display data for a single round
 type RoundDisplay struct {
-	Number       int
-	Status       string // "in_progress", "completed", "failed"
-	Issues       []string
-	Duration     time.Duration
-	ClaudeDone   bool
-	CodexDone    bool
-	CurrentPhase string // "claude", "codex", "diff"
+	Number          int
+	Status          string // "in_progress", "completed", "no changes", "failed", "interrupted"
+	Issues          []types.Issue
+	Duration        time.Duration
+	ImplementerDone bool
+	ReviewerDone    bool
+	CurrentPhase    string                     // "claude", "codex", "diff"
+	Verdicts        []types.ReviewResult       // Per-reviewer results when a panel reviews the round
+	Verification    []types.VerificationResult // Verification commands run before the review
+	Hooks           []types.HookResult         // Lifecycle hooks run during the round
+	Violations      []types.Violation          // Guardrails the implementer's changes broke
+	IssueVerdicts   []types.IssueVerdict       // Verdicts on the issues of earlier rounds
+}
+
+// ResumeCandidate describes an interrupted session offered for resumption on startup
+type ResumeCandidate struct {
+	SessionID string
+	Prompt    string
+	NextRound int
+	UpdatedAt time.Time
 }
 
 // ImageAttachment holds data about an attached clipboard image
@@ -83,7 +98,8 @@ type Model struct {
 
 	// Fighter selection
 	implementerType     fighters.FighterType
-	reviewerType        fighters.FighterType
+	reviewerTypes       []fighters.FighterType
+	reviewerCursor      int
 	fighterSelectField  FighterSelectField
 	availableFighters   []fighters.FighterType
 
@@ -100,6 +116,10 @@ type Model struct {
 	sessionSuccess     bool
 	sessionError       error
 
+	// Budget gauges: usage reported so far and when the current round started
+	usage          types.Usage
+	roundStartTime time.Time
+
 	// Image attachment
 	attachedImage *ImageAttachment
 	imageMessage  string // Temporary message about image operations
@@ -112,12 +132,26 @@ type Model struct {
 	confirmMessage string
 	confirmDefault bool
 
+	// Plan awaiting approval, its editor, and where to send the user's decision
+	proposedPlan types.Plan
+	planEditor   textarea.Model
+	planReply    chan<- PlanDecision
+
 	// Battle started flag
 	battleStarted bool
 
+	// Interrupted session offered for resumption, and the user's answer
+	resumeCandidate *ResumeCandidate
+	resumeRequested bool
+	resumeDeclined  bool
+
 	// Detail view toggle
 	showDetails bool
 
+	// Round selected on the results screen for rollback, and the outcome message
+	resultsCursor   int
+	rollbackMessage string
+
 	// Start time for duration display
 	startTime time.Time
 
@@ -150,6 +184,12 @@ func NewModel(cfg *config.Config) Model {
 	ta.SetWidth(70)
 	ta.SetHeight(8)
 
+	// Initialize textarea for editing a proposed plan
+	pe := textarea.New()
+	pe.CharLimit = 20000
+	pe.SetWidth(70)
+	pe.SetHeight(14)
+
 	// Initialize spinner
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
@@ -161,10 +201,21 @@ func NewModel(cfg *config.Config) Model {
 	// Initialize help
 	h := help.New()
 
+	// Start the reviewer cursor on the first configured reviewer
+	availableFighters := fighters.AllFighterTypes()
+	reviewerCursor := 0
+	for i, ft := range availableFighters {
+		if len(cfg.Reviewers) > 0 && ft == cfg.Reviewers[0] {
+			reviewerCursor = i
+			break
+		}
+	}
+
 	return Model{
 		view:              ViewFighterSelect,
 		config:            cfg,
 		textarea:          ta,
+		planEditor:        pe,
 		spinner:           sp,
 		viewport:          vp,
 		help:              h,
@@ -175,8 +226,9 @@ func NewModel(cfg *config.Config) Model {
 		width:             80,
 		height:            24,
 		implementerType:   cfg.Implementer,
-		reviewerType:      cfg.Reviewer,
-		availableFighters: fighters.AllFighterTypes(),
+		reviewerTypes:     append([]fighters.FighterType(nil), cfg.Reviewers...),
+		reviewerCursor:    reviewerCursor,
+		availableFighters: availableFighters,
 		fighterSelectField: FieldImplementer,
 	}
 }
@@ -220,6 +272,67 @@ func (m *Model) SetBattleStarted(prompt string) {
 	m.currentAction = ""
 	m.rounds = make([]RoundDisplay, 0)
 	m.startTime = time.Now()
+	m.roundStartTime = m.startTime
+}
+
+// SetResumeCandidate offers an interrupted session for resumption before fighter selection
+func (m *Model) SetResumeCandidate(candidate *ResumeCandidate) {
+	m.resumeCandidate = candidate
+	if candidate != nil && !m.battleStarted {
+		m.view = ViewResume
+	}
+}
+
+// ResumeRequested returns true if the user chose to resume the offered session
+func (m Model) ResumeRequested() bool {
+	return m.resumeRequested
+}
+
+// ResumeDeclined returns true if the user dismissed the offered session
+func (m Model) ResumeDeclined() bool {
+	return m.resumeDeclined
+}
+
+// SetPreviousRounds shows the rounds completed before a resumed session in the round history
+func (m *Model) SetPreviousRounds(rounds []types.Round) {
+	for _, round := range rounds {
+		var issues []types.Issue
+		if round.HasIssues {
+			issues = round.Findings
+			// Rounds checkpointed before issues were structured only have their text
+			if len(issues) == 0 {
+				issues = types.ParseIssues(round.Issues)
+			}
+		}
+		m.rounds = append(m.rounds, RoundDisplay{
+			Number:          round.Number,
+			Status:          roundStatus(round.Outcome),
+			Issues:          issues,
+			Duration:        round.Duration,
+			ImplementerDone: true,
+			ReviewerDone:    round.ReviewerOutput != "",
+			Verdicts:        round.Reviews,
+			Verification:    round.Verification,
+			Hooks:           round.Hooks,
+			Violations:      round.Violations,
+			IssueVerdicts:   round.Verdicts,
+		})
+		m.usage = m.usage.Add(round.Usage)
+	}
+}
+
+// roundStatus returns the status shown for a round that ended with outcome
+func roundStatus(outcome types.RoundOutcome) string {
+	switch outcome {
+	case types.OutcomeNoChanges:
+		return "no changes"
+	case types.OutcomeImplementerError, types.OutcomeReviewerError:
+		return "failed"
+	case types.OutcomeInterrupted:
+		return "interrupted"
+	default:
+		return "completed"
+	}
 }
 
 // GetImplementerType returns the selected implementer type
@@ -227,9 +340,9 @@ func (m Model) GetImplementerType() fighters.FighterType {
 	return m.implementerType
 }
 
-// GetReviewerType returns the selected reviewer type
-func (m Model) GetReviewerType() fighters.FighterType {
-	return m.reviewerType
+// GetReviewerTypes returns the selected reviewer types, in selection order
+func (m Model) GetReviewerTypes() []fighters.FighterType {
+	return m.reviewerTypes
 }
 
 // SetFighterNames sets the display names for the fighters
@@ -261,27 +374,25 @@ func (m Model) HasImage() bool {
 	return m.attachedImage != nil
 }
 
-// moveFighterSelection moves the fighter selection by delta (-1 or +1)
+// moveFighterSelection moves the fighter selection by delta (-1 or +1).
+// On the reviewer field this selects a single reviewer under the cursor.
 func (m *Model) moveFighterSelection(delta int) {
 	if len(m.availableFighters) == 0 {
 		return
 	}
 
 	var currentIdx int
-	var currentType fighters.FighterType
 
 	if m.fighterSelectField == FieldImplementer {
-		currentType = m.implementerType
-	} else {
-		currentType = m.reviewerType
-	}
-
-	// Find current index
-	for i, ft := range m.availableFighters {
-		if ft == currentType {
-			currentIdx = i
-			break
+		// Find current index
+		for i, ft := range m.availableFighters {
+			if ft == m.implementerType {
+				currentIdx = i
+				break
+			}
 		}
+	} else {
+		currentIdx = m.reviewerCursor
 	}
 
 	// Calculate new index with wrapping
@@ -291,8 +402,38 @@ func (m *Model) moveFighterSelection(delta int) {
 	if m.fighterSelectField == FieldImplementer {
 		m.implementerType = m.availableFighters[newIdx]
 	} else {
-		m.reviewerType = m.availableFighters[newIdx]
+		m.reviewerCursor = newIdx
+		m.reviewerTypes = []fighters.FighterType{m.availableFighters[newIdx]}
+	}
+}
+
+// toggleReviewer adds or removes the reviewer under the cursor from the review panel.
+// The last remaining reviewer cannot be removed.
+func (m *Model) toggleReviewer() {
+	if len(m.availableFighters) == 0 {
+		return
+	}
+
+	ft := m.availableFighters[m.reviewerCursor]
+	for i, selected := range m.reviewerTypes {
+		if selected == ft {
+			if len(m.reviewerTypes) > 1 {
+				m.reviewerTypes = append(m.reviewerTypes[:i:i], m.reviewerTypes[i+1:]...)
+			}
+			return
+		}
+	}
+	m.reviewerTypes = append(m.reviewerTypes, ft)
+}
+
+// isReviewerSelected reports whether ft is on the review panel
+func (m Model) isReviewerSelected(ft fighters.FighterType) bool {
+	for _, selected := range m.reviewerTypes {
+		if selected == ft {
+			return true
+		}
 	}
+	return false
 }
 
 // eventMsg wraps an event from the orchestrator
@@ -1,28 +1,13 @@
 package tui
 
 import (
-	"time"
-
+	"github.com/diegoram/mortal-prompter/internal/confirm"
+	"github.com/diegoram/mortal-prompter/internal/events"
 	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
-// Observer is the interface for receiving orchestrator events
-// This is defined in the tui package to avoid circular dependencies
-// The orchestrator will implement a callback-based approach instead
-type Observer interface {
-	OnRoundStart(number int)
-	OnFighterEnter(fighter string)
-	OnFighterAction(fighter, action string)
-	OnFighterFinish(fighter string, duration time.Duration)
-	OnChangesDetected(fileCount int)
-	OnIssuesFound(issues []string)
-	OnNoIssues()
-	OnSessionComplete(result *types.SessionResult, success bool)
-	OnError(err error)
-	OnConfirmationRequired(message string) bool
-}
-
-// ChannelObserver implements Observer by sending events to a channel
+// ChannelObserver forwards the orchestrator's events to the TUI through a
+// channel, and answers its questions with the user's replies
 type ChannelObserver struct {
 	eventChan    chan<- Event
 	responseChan <-chan bool
@@ -36,84 +21,71 @@ func NewChannelObserver(eventChan chan<- Event, responseChan <-chan bool) *Chann
 	}
 }
 
-// OnRoundStart sends a round start event
-func (o *ChannelObserver) OnRoundStart(number int) {
-	o.eventChan <- Event{
-		Type:    EventRoundStart,
-		Payload: RoundStartPayload{Number: number},
-	}
-}
-
-// OnFighterEnter sends a fighter enter event
-func (o *ChannelObserver) OnFighterEnter(fighter string) {
-	o.eventChan <- Event{
-		Type:    EventFighterEnter,
-		Payload: FighterEnterPayload{Fighter: fighter},
-	}
-}
-
-// OnFighterAction sends a fighter action event
-func (o *ChannelObserver) OnFighterAction(fighter, action string) {
-	o.eventChan <- Event{
-		Type:    EventFighterAction,
-		Payload: FighterActionPayload{Fighter: fighter, Action: action},
-	}
-}
-
-// OnFighterFinish sends a fighter finish event
-func (o *ChannelObserver) OnFighterFinish(fighter string, duration time.Duration) {
-	o.eventChan <- Event{
-		Type:    EventFighterFinish,
-		Payload: FighterFinishPayload{Fighter: fighter, Duration: duration},
-	}
-}
-
-// OnChangesDetected sends a changes detected event
-func (o *ChannelObserver) OnChangesDetected(fileCount int) {
-	o.eventChan <- Event{
-		Type:    EventChangesDetected,
-		Payload: ChangesDetectedPayload{FileCount: fileCount},
-	}
-}
-
-// OnIssuesFound sends an issues found event
-func (o *ChannelObserver) OnIssuesFound(issues []string) {
-	o.eventChan <- Event{
-		Type:    EventIssuesFound,
-		Payload: IssuesFoundPayload{Issues: issues},
-	}
-}
-
-// OnNoIssues sends a no issues event
-func (o *ChannelObserver) OnNoIssues() {
-	o.eventChan <- Event{
-		Type:    EventNoIssues,
-		Payload: nil,
+// HandleEvent sends the TUI event matching e, if the TUI shows it
+func (o *ChannelObserver) HandleEvent(e events.Event) {
+	switch e := e.(type) {
+	case events.RoundStart:
+		o.send(EventRoundStart, RoundStartPayload{Number: e.Round})
+	case events.FighterEnter:
+		o.send(EventFighterEnter, FighterEnterPayload{Fighter: e.Fighter})
+	case events.FighterAction:
+		o.send(EventFighterAction, FighterActionPayload{Fighter: e.Fighter, Action: e.Action})
+	case events.ContenderProgress:
+		o.send(EventFighterAction, FighterActionPayload{Fighter: e.Fighter, Action: e.Contender + ": " + e.Message})
+	case events.FighterFinish:
+		o.send(EventFighterFinish, FighterFinishPayload{Fighter: e.Fighter, Duration: e.Duration})
+	case events.ChangesDetected:
+		o.send(EventChangesDetected, ChangesDetectedPayload{FileCount: e.Files})
+	case events.Verification:
+		o.send(EventVerification, VerificationPayload{Results: e.Results})
+	case events.Hooks:
+		o.send(EventHooks, HooksPayload{Point: e.Point, Results: e.Results})
+	case events.Guardrails:
+		o.send(EventGuardrails, GuardrailsPayload{Violations: e.Violations})
+	case events.ReviewVerdicts:
+		o.send(EventReviewVerdicts, ReviewVerdictsPayload{Reviews: e.Reviews, Verdicts: e.Verdicts})
+	case events.UsageUpdated:
+		o.send(EventUsage, UsagePayload{Total: e.Total})
+	case events.IssuesFound:
+		o.send(EventIssuesFound, IssuesFoundPayload{Issues: e.Issues})
+	case events.NoIssues:
+		o.send(EventNoIssues, nil)
+	case events.RoundComplete:
+		o.send(EventRoundComplete, RoundCompletePayload{Outcome: e.Outcome})
+	case events.SessionComplete:
+		o.send(EventSessionComplete, SessionCompletePayload{Result: e.Result, Success: e.Success})
+	case events.Error:
+		o.send(EventError, ErrorPayload{Error: e.Err})
 	}
 }
 
-// OnSessionComplete sends a session complete event
-func (o *ChannelObserver) OnSessionComplete(result *types.SessionResult, success bool) {
+// send sends an event of the given type to the TUI
+func (o *ChannelObserver) send(eventType EventType, payload interface{}) {
 	o.eventChan <- Event{
-		Type:    EventSessionComplete,
-		Payload: SessionCompletePayload{Result: result, Success: success},
+		Type:    eventType,
+		Payload: payload,
 	}
 }
 
-// OnError sends an error event
-func (o *ChannelObserver) OnError(err error) {
+// Confirm sends a confirmation required event and waits for response
+func (o *ChannelObserver) Confirm(q confirm.Question) (bool, error) {
 	o.eventChan <- Event{
-		Type:    EventError,
-		Payload: ErrorPayload{Error: err},
+		Type:    EventConfirmationRequired,
+		Payload: ConfirmationPayload{Message: q.Message},
 	}
+	// Wait for response from TUI
+	return <-o.responseChan, nil
 }
 
-// OnConfirmationRequired sends a confirmation required event and waits for response
-func (o *ChannelObserver) OnConfirmationRequired(message string) bool {
+// OnPlanProposed sends a plan proposed event and waits for the user to approve,
+// edit or reject the plan
+func (o *ChannelObserver) OnPlanProposed(plan types.Plan) (string, bool) {
+	reply := make(chan PlanDecision, 1)
 	o.eventChan <- Event{
-		Type:    EventConfirmationRequired,
-		Payload: ConfirmationPayload{Message: message},
+		Type:    EventPlanProposed,
+		Payload: PlanProposedPayload{Plan: plan, Reply: reply},
 	}
-	// Wait for response from TUI
-	return <-o.responseChan
+	// Wait for decision from TUI
+	decision := <-reply
+	return decision.Text, decision.Approved
 }
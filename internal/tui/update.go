@@ -1,13 +1,16 @@
 package tui
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/diegoram/mortal-prompter/internal/clipboard"
+	"github.com/diegoram/mortal-prompter/internal/session"
 )
 
 // Update handles messages and updates the model
@@ -22,6 +25,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.textarea.SetWidth(msg.Width - 10)
+		m.planEditor.SetWidth(msg.Width - 10)
 		m.viewport.Width = msg.Width - 4
 		m.viewport.Height = msg.Height - 10
 		return m, nil
@@ -75,6 +79,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textarea, cmd = m.textarea.Update(msg)
 		cmds = append(cmds, cmd)
 
+	case ViewPlan:
+		var cmd tea.Cmd
+		m.planEditor, cmd = m.planEditor.Update(msg)
+		cmds = append(cmds, cmd)
+
 	case ViewBattle:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -97,6 +106,55 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleResultsKeys(msg)
 	case ViewConfirmation:
 		return m.handleConfirmationKeys(msg)
+	case ViewResume:
+		return m.handleResumeKeys(msg)
+	case ViewPlan:
+		return m.handlePlanKeys(msg)
+	}
+	return m, nil
+}
+
+// handlePlanKeys handles keys while the proposed plan is reviewed and edited
+func (m Model) handlePlanKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		m.planReply <- PlanDecision{Approved: false}
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Submit):
+		m.planReply <- PlanDecision{Text: m.planEditor.Value(), Approved: true}
+		m.planEditor.Blur()
+		m.view = ViewBattle
+		return m, tea.Batch(m.spinner.Tick, tick(), waitForEvent(m.eventChan))
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.planReply <- PlanDecision{Approved: false}
+		m.planEditor.Blur()
+		m.view = ViewBattle
+		return m, tea.Batch(m.spinner.Tick, tick(), waitForEvent(m.eventChan))
+
+	default:
+		var cmd tea.Cmd
+		m.planEditor, cmd = m.planEditor.Update(msg)
+		return m, cmd
+	}
+}
+
+// handleResumeKeys handles keys in the resume offer shown on startup
+func (m Model) handleResumeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Confirm):
+		// main.go resumes the session from its checkpoint
+		m.resumeRequested = true
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Deny), key.Matches(msg, m.keys.Cancel):
+		m.resumeDeclined = true
+		m.view = ViewFighterSelect
+		return m, nil
 	}
 	return m, nil
 }
@@ -131,6 +189,13 @@ func (m Model) handleFighterSelectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Move to next fighter option
 		m.moveFighterSelection(1)
 		return m, nil
+
+	case tea.KeySpace:
+		// Add or remove a reviewer from the review panel
+		if m.fighterSelectField == FieldReviewer {
+			m.toggleReviewer()
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -265,10 +330,43 @@ func (m Model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.ViewDiff):
 		m.showDetails = !m.showDetails
 		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.resultsCursor > 0 {
+			m.resultsCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.sessionResult != nil && m.resultsCursor < len(m.sessionResult.Rounds)-1 {
+			m.resultsCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Rollback):
+		return m.handleRollback()
 	}
 	return m, nil
 }
 
+// handleRollback restores the working tree to the snapshot of the selected round
+func (m Model) handleRollback() (tea.Model, tea.Cmd) {
+	if m.sessionResult == nil || m.sessionResult.SessionID == "" || len(m.sessionResult.Rounds) == 0 {
+		return m, nil
+	}
+
+	round := m.sessionResult.Rounds[m.resultsCursor].Number
+	store := session.NewStore(m.config.OutputDir)
+	if _, err := store.Rollback(m.sessionResult.SessionID, round); err != nil {
+		m.rollbackMessage = "Rollback failed: " + err.Error()
+		return m, nil
+	}
+
+	m.rollbackMessage = fmt.Sprintf("Restored round %d - resume with: mortal-prompter resume %s",
+		round, m.sessionResult.SessionID)
+	return m, nil
+}
+
 // handleConfirmationKeys handles keys in the confirmation dialog
 func (m Model) handleConfirmationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
@@ -295,6 +393,7 @@ func (m Model) handleEvent(event Event) (tea.Model, tea.Cmd) {
 	case EventRoundStart:
 		if payload, ok := event.Payload.(RoundStartPayload); ok {
 			m.currentRound = payload.Number
+			m.roundStartTime = time.Now()
 			m.rounds = append(m.rounds, RoundDisplay{
 				Number:       payload.Number,
 				Status:       "in_progress",
@@ -328,7 +427,7 @@ func (m Model) handleEvent(event Event) (tea.Model, tea.Cmd) {
 		if payload, ok := event.Payload.(FighterActionPayload); ok {
 			m.currentAction = payload.Action
 			// Update fighter states based on who is performing the action
-			if payload.Fighter == m.implementerName || payload.Fighter == "Claude Code" {
+			if payload.Fighter == m.implementerName {
 				m.implementerState = FighterActive
 				m.reviewerState = FighterIdle
 			} else {
@@ -342,19 +441,54 @@ func (m Model) handleEvent(event Event) (tea.Model, tea.Cmd) {
 
 	case EventFighterFinish:
 		if payload, ok := event.Payload.(FighterFinishPayload); ok {
-			if payload.Fighter == m.implementerName || payload.Fighter == "Claude Code" {
+			if payload.Fighter == m.implementerName {
 				m.implementerState = FighterFinished
 				if len(m.rounds) > 0 {
-					m.rounds[len(m.rounds)-1].ClaudeDone = true
+					m.rounds[len(m.rounds)-1].ImplementerDone = true
 				}
 			} else {
 				m.reviewerState = FighterFinished
 				if len(m.rounds) > 0 {
-					m.rounds[len(m.rounds)-1].CodexDone = true
+					m.rounds[len(m.rounds)-1].ReviewerDone = true
 				}
 			}
 		}
 
+	case EventVerification:
+		if payload, ok := event.Payload.(VerificationPayload); ok {
+			if len(m.rounds) > 0 {
+				m.rounds[len(m.rounds)-1].Verification = payload.Results
+			}
+		}
+
+	case EventHooks:
+		if payload, ok := event.Payload.(HooksPayload); ok {
+			if len(m.rounds) > 0 {
+				last := &m.rounds[len(m.rounds)-1]
+				last.Hooks = append(last.Hooks, payload.Results...)
+			}
+		}
+
+	case EventGuardrails:
+		if payload, ok := event.Payload.(GuardrailsPayload); ok {
+			if len(m.rounds) > 0 {
+				m.rounds[len(m.rounds)-1].Violations = payload.Violations
+			}
+		}
+
+	case EventReviewVerdicts:
+		if payload, ok := event.Payload.(ReviewVerdictsPayload); ok {
+			if len(m.rounds) > 0 {
+				m.rounds[len(m.rounds)-1].Verdicts = payload.Reviews
+				m.rounds[len(m.rounds)-1].IssueVerdicts = payload.Verdicts
+			}
+		}
+
+	case EventUsage:
+		if payload, ok := event.Payload.(UsagePayload); ok {
+			m.usage = payload.Total
+		}
+
 	case EventIssuesFound:
 		if payload, ok := event.Payload.(IssuesFoundPayload); ok {
 			if len(m.rounds) > 0 {
@@ -368,6 +502,14 @@ func (m Model) handleEvent(event Event) (tea.Model, tea.Cmd) {
 			m.rounds[len(m.rounds)-1].Status = "completed"
 		}
 
+	case EventRoundComplete:
+		// Reviewed rounds are completed once their issues are known
+		if payload, ok := event.Payload.(RoundCompletePayload); ok {
+			if status := roundStatus(payload.Outcome); status != "completed" && len(m.rounds) > 0 {
+				m.rounds[len(m.rounds)-1].Status = status
+			}
+		}
+
 	case EventSessionComplete:
 		if payload, ok := event.Payload.(SessionCompletePayload); ok {
 			m.view = ViewResults
@@ -390,6 +532,16 @@ func (m Model) handleEvent(event Event) (tea.Model, tea.Cmd) {
 			m.view = ViewConfirmation
 		}
 		return m, nil // Don't wait, we need user input
+
+	case EventPlanProposed:
+		if payload, ok := event.Payload.(PlanProposedPayload); ok {
+			m.proposedPlan = payload.Plan
+			m.planReply = payload.Reply
+			m.planEditor.SetValue(payload.Plan.Text)
+			m.planEditor.Focus()
+			m.view = ViewPlan
+			return m, textarea.Blink
+		}
 	}
 
 	// Continue listening for events AND keep ticking for UI updates
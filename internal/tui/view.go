@@ -7,6 +7,8 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/diegoram/mortal-prompter/internal/fighters"
+	"github.com/diegoram/mortal-prompter/internal/tui/components"
+	"github.com/diegoram/mortal-prompter/pkg/types"
 )
 
 // View renders the current view
@@ -22,6 +24,10 @@ func (m Model) View() string {
 		return m.viewResults()
 	case ViewConfirmation:
 		return m.viewConfirmation()
+	case ViewResume:
+		return m.viewResume()
+	case ViewPlan:
+		return m.viewPlan()
 	default:
 		return "Unknown view"
 	}
@@ -96,14 +102,14 @@ func (m Model) viewFighterSelect() string {
 		reviewerLabel = labelStyle.Render("  REVIEWER:    ")
 	}
 	sb.WriteString(reviewerLabel)
-	sb.WriteString(m.renderFighterOptions(m.reviewerType, selectedStyle, unselectedStyle))
+	sb.WriteString(m.renderReviewerOptions(selectedStyle, unselectedStyle, activeFieldStyle))
 	sb.WriteString("\n\n")
 
 	sb.WriteString("═══════════════════════════════════════════════════════════════════════════")
 	sb.WriteString("\n\n")
 
 	// Help
-	sb.WriteString(HelpStyle.Render("  ←/→: select fighter  •  ↑/↓: switch field  •  enter: continue  •  ctrl+c: quit"))
+	sb.WriteString(HelpStyle.Render("  ←/→: select fighter  •  space: add/remove reviewer  •  ↑/↓: switch field  •  enter: continue  •  ctrl+c: quit"))
 	sb.WriteString("\n")
 
 	return sb.String()
@@ -123,6 +129,33 @@ func (m Model) renderFighterOptions(selected fighters.FighterType, selectedStyle
 	return strings.Join(parts, "  ")
 }
 
+// renderReviewerOptions renders the reviewer options, marking every fighter on
+// the review panel and the cursor used to add or remove reviewers
+func (m Model) renderReviewerOptions(selectedStyle, unselectedStyle, cursorStyle lipgloss.Style) string {
+	var parts []string
+	for i, ft := range m.availableFighters {
+		name := strings.ToUpper(string(ft))
+		switch {
+		case m.isReviewerSelected(ft):
+			parts = append(parts, selectedStyle.Render("["+name+"]"))
+		case m.fighterSelectField == FieldReviewer && i == m.reviewerCursor:
+			parts = append(parts, cursorStyle.Render(">"+name+"<"))
+		default:
+			parts = append(parts, unselectedStyle.Render(" "+name+" "))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// reviewerDisplayNames returns the display names of the selected reviewers joined with " + "
+func (m Model) reviewerDisplayNames() string {
+	names := make([]string, len(m.reviewerTypes))
+	for i, ft := range m.reviewerTypes {
+		names[i] = fighters.DisplayName(ft)
+	}
+	return strings.Join(names, " + ")
+}
+
 // viewPrompt renders the prompt input view
 func (m Model) viewPrompt() string {
 	var sb strings.Builder
@@ -151,7 +184,8 @@ func (m Model) viewPrompt() string {
 	sb.WriteString("\n\n")
 	sb.WriteString(SuccessStyle.Render("                           CHOOSE YOUR TASK!"))
 	sb.WriteString("\n\n")
-	sb.WriteString(InfoStyle.Render("         Claude Code vs Codex - Code Review Battle Arena"))
+	sb.WriteString(InfoStyle.Render(fmt.Sprintf("         %s vs %s - Code Review Battle Arena",
+		fighters.DisplayName(m.implementerType), m.reviewerDisplayNames())))
 	sb.WriteString("\n\n")
 	sb.WriteString("═══════════════════════════════════════════════════════════════════════════")
 	sb.WriteString("\n\n")
@@ -176,7 +210,7 @@ func (m Model) viewPrompt() string {
 	}
 
 	// Prompt label
-	sb.WriteString(TitleStyle.Render("  Enter your prompt for Claude Code:"))
+	sb.WriteString(TitleStyle.Render(fmt.Sprintf("  Enter your prompt for %s:", fighters.DisplayName(m.implementerType))))
 	sb.WriteString("\n\n")
 
 	// Textarea
@@ -266,7 +300,7 @@ func (m Model) viewBattle() string {
 	}
 	reviewerName := m.reviewerName
 	if reviewerName == "" {
-		reviewerName = strings.ToUpper(string(m.reviewerType))
+		reviewerName = m.reviewerDisplayNames()
 	}
 
 	// Fighter names line
@@ -387,6 +421,18 @@ func (m Model) viewBattle() string {
 
 	sb.WriteString(midBorder + "\n")
 
+	// Budget gauges
+	if gauges := m.budgetGauges(); len(gauges) > 0 {
+		const barWidth = 24
+		for _, g := range gauges {
+			label := fmt.Sprintf(" %-6s", g.label)
+			value := " " + g.value
+			line := label + components.ProgressBar(g.percent, barWidth) + value
+			sb.WriteString(padLine(line, len(label)+barWidth+len(value)))
+		}
+		sb.WriteString(midBorder + "\n")
+	}
+
 	// Prompt section
 	if m.prompt != "" {
 		// Show image indicator if attached
@@ -428,7 +474,7 @@ func (m Model) viewBattle() string {
 	}
 
 	// Round history
-	for _, round := range m.rounds {
+	for i, round := range m.rounds {
 		var icon, status string
 		var style lipgloss.Style
 
@@ -451,7 +497,7 @@ func (m Model) viewBattle() string {
 
 		status = round.Status
 		if len(round.Issues) > 0 {
-			status += fmt.Sprintf(" (%d issues)", len(round.Issues))
+			status += fmt.Sprintf(" (%d issues: %s)", len(round.Issues), severitySummary(round.Issues))
 		}
 		if round.Duration > 0 {
 			status += fmt.Sprintf(" [%s]", round.Duration.Round(time.Second))
@@ -461,6 +507,78 @@ func (m Model) viewBattle() string {
 		contentWidth := len(content)
 		styledContent := " " + style.Render(fmt.Sprintf("%s Round %d: %s", icon, round.Number, status))
 		sb.WriteString(padLine(styledContent, contentWidth))
+
+		// Verification commands run before the review
+		for _, check := range round.Verification {
+			checkStyle := activeStyle
+			checkText := fmt.Sprintf("ok %s [%s]", check.Command, check.Duration.Round(time.Second))
+			if !check.Passed {
+				checkStyle = warningStyle
+				checkText = fmt.Sprintf("FAIL %s (exit %d)", check.Command, check.ExitCode)
+			}
+			if len(checkText) > W-8 {
+				checkText = checkText[:W-11] + "..."
+			}
+			sb.WriteString(padLine("     "+checkStyle.Render(checkText), 5+len(checkText)))
+		}
+
+		// Guardrails the implementer's changes broke
+		for _, violation := range round.Violations {
+			violationText := "KEPT " + violation.Message
+			if violation.Reverted {
+				violationText = "REVERTED " + violation.Message
+			}
+			if len(violationText) > W-8 {
+				violationText = violationText[:W-11] + "..."
+			}
+			sb.WriteString(padLine("     "+warningStyle.Render(violationText), 5+len(violationText)))
+		}
+
+		// Lifecycle hooks, with the issues they raised
+		for _, hook := range round.Hooks {
+			hookStyle := activeStyle
+			hookText := fmt.Sprintf("ok %s: %s [%s]", hook.Hook, hook.Command, hook.Duration.Round(time.Second))
+			switch {
+			case hook.Vetoed:
+				hookStyle = warningStyle
+				hookText = fmt.Sprintf("VETO %s: %s (exit %d)", hook.Hook, hook.Command, hook.ExitCode)
+			case len(hook.Issues) > 0:
+				hookStyle = warningStyle
+				hookText = fmt.Sprintf("%d issues %s: %s", len(hook.Issues), hook.Hook, hook.Command)
+			}
+			if len(hookText) > W-8 {
+				hookText = hookText[:W-11] + "..."
+			}
+			sb.WriteString(padLine("     "+hookStyle.Render(hookText), 5+len(hookText)))
+		}
+
+		// Per-reviewer verdicts when a panel reviewed the round
+		if len(round.Verdicts) > 1 {
+			for _, verdict := range round.Verdicts {
+				verdictText := "LGTM"
+				verdictStyle := activeStyle
+				if verdict.HasIssues {
+					verdictText = fmt.Sprintf("%d issues", len(verdict.Issues))
+					verdictStyle = warningStyle
+				}
+				verdictLine := fmt.Sprintf("%s: %s", verdict.Reviewer, verdictText)
+				sb.WriteString(padLine("     "+verdictStyle.Render(verdictLine), 5+len(verdictLine)))
+			}
+		}
+
+		// Verdicts on the issues of earlier rounds
+		if summary := verdictSummary(round.IssueVerdicts); summary != "" {
+			summaryLine := "Earlier issues: " + summary
+			sb.WriteString(padLine("     "+infoStyle.Render(summaryLine), 5+len(summaryLine)))
+		}
+
+		// Files with issues in the latest round, grouped by severity
+		if i == len(m.rounds)-1 {
+			for _, group := range issueGroups(round.Issues) {
+				group = truncateString(group, W-8)
+				sb.WriteString(padLine("     "+warningStyle.Render(group), 5+len(group)))
+			}
+		}
 	}
 
 	// Current action
@@ -582,6 +700,132 @@ func (m Model) viewResults() string {
 		sb.WriteString("║" + statsContent + "║\n")
 	}
 
+	// Tournament contenders and the judge's verdict
+	if m.sessionResult != nil && m.sessionResult.Tournament != nil {
+		t := m.sessionResult.Tournament
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		for _, c := range t.Contenders {
+			marker := " "
+			if c.Number == t.Winner {
+				marker = "*"
+			}
+			status := "failed"
+			rounds := 0
+			if c.Result != nil {
+				rounds = c.Result.TotalRounds
+				if c.Result.Success {
+					status = "LGTM"
+				} else if len(c.Result.Rounds) > 0 {
+					status = fmt.Sprintf("%d open issues", len(c.Result.Rounds[len(c.Result.Rounds)-1].Issues))
+				}
+			}
+			contenderText := fmt.Sprintf("  %s #%d %-10s %2d rounds  %s", marker, c.Number, truncateString(c.Implementer, 10), rounds, status)
+			contenderText = truncateString(contenderText, boxW)
+			for len(contenderText) < boxW {
+				contenderText += " "
+			}
+			line := "║" + contenderText + "║"
+			if c.Number == t.Winner {
+				line = SuccessStyle.Render(line)
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, reasonLine := range wrapText("Judge "+t.Judge+": "+t.Reasoning, boxW-4, 4) {
+			reasonText := "  " + reasonLine
+			for len(reasonText) < boxW {
+				reasonText += " "
+			}
+			sb.WriteString(InfoStyle.Render("║" + reasonText + "║"))
+			sb.WriteString("\n")
+		}
+	}
+
+	// Rounds with their snapshots, selectable for rollback
+	canRollback := m.hasSnapshots()
+	if canRollback {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		for i, round := range m.sessionResult.Rounds {
+			marker := " "
+			if i == m.resultsCursor {
+				marker = ">"
+			}
+			verdict := "LGTM"
+			if status := roundStatus(round.Outcome); status != "completed" && !round.HasIssues {
+				verdict = status
+			} else if round.HasIssues {
+				verdict = fmt.Sprintf("%d issues", len(round.Issues))
+			}
+			snapshot := "no snapshot"
+			if len(round.SnapshotSHA) >= 7 {
+				snapshot = round.SnapshotSHA[:7]
+			}
+			roundText := fmt.Sprintf("  %s Round %-3d %-12s %s", marker, round.Number, verdict, snapshot)
+			for len(roundText) < boxW {
+				roundText += " "
+			}
+			line := "║" + roundText + "║"
+			if i == m.resultsCursor {
+				line = SuccessStyle.Render(line)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	// Issues left in the final round, grouped by severity; these are only
+	// the ones below the fail-on threshold when the session succeeded
+	if m.sessionResult != nil && len(m.sessionResult.Rounds) > 0 {
+		last := m.sessionResult.Rounds[len(m.sessionResult.Rounds)-1]
+		if groups := issueGroups(last.Findings); len(groups) > 0 {
+			sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+			label := "  Open issues:"
+			if !last.HasIssues {
+				label = "  Accepted issues:"
+			}
+			for _, line := range append([]string{label}, groups...) {
+				if line != label {
+					line = "    " + truncateString(line, boxW-6)
+				}
+				for len(line) < boxW {
+					line += " "
+				}
+				sb.WriteString(WarningStyle.Render("║" + line + "║"))
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	// Every issue raised during the session and what became of it
+	if m.sessionResult != nil && len(m.sessionResult.Ledger) > 0 {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		lines := []string{"  Issue lifecycle:"}
+		for _, entry := range m.sessionResult.Ledger {
+			closed := "-"
+			if entry.ClosedIn > 0 {
+				closed = fmt.Sprintf("R%d", entry.ClosedIn)
+			}
+			line := fmt.Sprintf("    #%s %-8s R%d-%-3s %-8s %s", entry.Issue.ID, strings.ToUpper(string(entry.Issue.Severity)),
+				entry.RaisedIn, closed, entry.Status, entry.Issue.Description)
+			lines = append(lines, truncateString(line, boxW-2))
+		}
+		for _, line := range lines {
+			for len(line) < boxW {
+				line += " "
+			}
+			sb.WriteString(InfoStyle.Render("║" + line + "║"))
+			sb.WriteString("\n")
+		}
+	}
+
+	if m.rollbackMessage != "" {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		msgText := "  " + truncateString(m.rollbackMessage, boxW-4)
+		for len(msgText) < boxW {
+			msgText += " "
+		}
+		sb.WriteString(InfoStyle.Render("║" + msgText + "║"))
+		sb.WriteString("\n")
+	}
+
 	// Error message if any
 	if m.sessionError != nil {
 		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
@@ -593,6 +837,28 @@ func (m Model) viewResults() string {
 		sb.WriteString("\n")
 	}
 
+	// Why the session stopped
+	if m.sessionResult != nil && m.sessionResult.StopReason != "" {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		reasonText := "  " + truncateString(m.sessionResult.StopReason, boxW-4)
+		for len(reasonText) < boxW {
+			reasonText += " "
+		}
+		sb.WriteString(InfoStyle.Render("║" + reasonText + "║"))
+		sb.WriteString("\n")
+	}
+
+	// Session branch left in the repository (worktree mode)
+	if m.sessionResult != nil && m.sessionResult.Branch != "" {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		branchText := "  Branch: " + truncateString(m.sessionResult.Branch, boxW-12)
+		for len(branchText) < boxW {
+			branchText += " "
+		}
+		sb.WriteString(InfoStyle.Render("║" + branchText + "║"))
+		sb.WriteString("\n")
+	}
+
 	// Report path
 	if m.reportPath != "" {
 		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
@@ -605,7 +871,11 @@ func (m Model) viewResults() string {
 	}
 
 	sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
-	sb.WriteString(HelpStyle.Render("║  v: view diff   │   enter/q: exit                          ║"))
+	if canRollback {
+		sb.WriteString(HelpStyle.Render("║  j/k: round  │  b: roll back  │  v: diff  │  enter/q: exit ║"))
+	} else {
+		sb.WriteString(HelpStyle.Render("║  v: view diff   │   enter/q: exit                          ║"))
+	}
 	sb.WriteString("\n")
 	sb.WriteString("╚════════════════════════════════════════════════════════════╝\n")
 
@@ -633,8 +903,97 @@ func (m Model) viewConfirmation() string {
 	return sb.String()
 }
 
+// viewPlan renders the proposed plan for approval, with the reviewers' critique
+// and an editor holding the plan
+func (m Model) viewPlan() string {
+	var sb strings.Builder
+
+	const boxW = 60
+	padRow := func(text string) string {
+		text = truncateString(text, boxW-4)
+		return fmt.Sprintf("║  %-*s  ║", boxW-4, text)
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString("╔════════════════════════════════════════════════════════════╗\n")
+	sb.WriteString("║                        BATTLE PLAN                         ║\n")
+	sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+	sb.WriteString(InfoStyle.Render(padRow("Drafted by " + m.proposedPlan.Planner)))
+	sb.WriteString("\n")
+
+	if len(m.proposedPlan.Critique) > 0 {
+		sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+		sb.WriteString(WarningStyle.Render(padRow("Critique addressed in this revision:")))
+		sb.WriteString("\n")
+		for _, issue := range m.proposedPlan.Critique {
+			for i, line := range wrapText(issue, boxW-6, 2) {
+				prefix := "- "
+				if i > 0 {
+					prefix = "  "
+				}
+				sb.WriteString(WarningStyle.Render(padRow(prefix + line)))
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	sb.WriteString("╚════════════════════════════════════════════════════════════╝\n")
+	sb.WriteString("\n")
+	sb.WriteString(m.planEditor.View())
+	sb.WriteString("\n\n")
+	sb.WriteString(HelpStyle.Render("  ctrl+s: approve plan  │  esc: reject and stop  │  edit the plan above"))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// viewResume renders the offer to resume an interrupted session
+func (m Model) viewResume() string {
+	var sb strings.Builder
+
+	const boxW = 60
+	padRow := func(text string) string {
+		text = truncateString(text, boxW-4)
+		return fmt.Sprintf("║  %-*s  ║", boxW-4, text)
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString("╔════════════════════════════════════════════════════════════╗\n")
+	sb.WriteString("║                 INTERRUPTED SESSION FOUND                  ║\n")
+	sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+
+	if c := m.resumeCandidate; c != nil {
+		prompt := strings.Join(strings.Fields(c.Prompt), " ")
+		sb.WriteString(InfoStyle.Render(padRow("Session: " + c.SessionID)))
+		sb.WriteString("\n")
+		sb.WriteString(InfoStyle.Render(padRow("Prompt:  " + prompt)))
+		sb.WriteString("\n")
+		sb.WriteString(InfoStyle.Render(padRow(fmt.Sprintf("Resumes at round %d (saved %s)", c.NextRound, c.UpdatedAt.Format("2006-01-02 15:04")))))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("╠════════════════════════════════════════════════════════════╣\n")
+	sb.WriteString("║           [Y] Resume            [N] New battle             ║\n")
+	sb.WriteString("╚════════════════════════════════════════════════════════════╝\n")
+
+	return sb.String()
+}
+
 // Helper functions
 
+// hasSnapshots reports whether any round of the finished session can be rolled back to
+func (m Model) hasSnapshots() bool {
+	if m.sessionResult == nil || m.sessionResult.SessionID == "" {
+		return false
+	}
+	for _, round := range m.sessionResult.Rounds {
+		if round.SnapshotSHA != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // truncateString truncates a string to a maximum length
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -744,3 +1103,137 @@ func (m Model) getRightFighterFrames() [][]string {
 	}
 	return rightIdleFrames
 }
+
+// budgetGauge is a budget shown as a gauge in the battle view
+type budgetGauge struct {
+	label   string
+	percent float64
+	value   string
+}
+
+// budgetGauges returns a gauge for every budget configured for the session
+func (m Model) budgetGauges() []budgetGauge {
+	if m.config == nil {
+		return nil
+	}
+
+	var gauges []budgetGauge
+	if limit := m.config.SessionTimeout; limit > 0 {
+		elapsed := time.Since(m.startTime)
+		gauges = append(gauges, budgetGauge{
+			label:   "TIME",
+			percent: 100 * float64(elapsed) / float64(limit),
+			value:   fmt.Sprintf("%s / %s", elapsed.Round(time.Second), limit),
+		})
+	}
+	if limit := m.config.RoundTimeout; limit > 0 {
+		elapsed := time.Since(m.roundStartTime)
+		gauges = append(gauges, budgetGauge{
+			label:   "ROUND",
+			percent: 100 * float64(elapsed) / float64(limit),
+			value:   fmt.Sprintf("%s / %s", elapsed.Round(time.Second), limit),
+		})
+	}
+	if limit := m.config.MaxCost; limit > 0 {
+		gauges = append(gauges, budgetGauge{
+			label:   "COST",
+			percent: 100 * m.usage.CostUSD / limit,
+			value:   fmt.Sprintf("$%.2f / $%.2f", m.usage.CostUSD, limit),
+		})
+	}
+	return gauges
+}
+
+// wrapText splits s into lines of at most width characters, keeping at most
+// maxLines lines and marking the last one if text was cut.
+func wrapText(s string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	for i := range lines {
+		lines[i] = truncateString(lines[i], width)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncateString(lines[maxLines-1]+" ...", width)
+	}
+	return lines
+}
+
+// severitySummary counts issues per severity, e.g. "1 high, 2 low".
+func severitySummary(issues []types.Issue) string {
+	var parts []string
+	for _, severity := range types.Severities {
+		n := 0
+		for _, issue := range issues {
+			if issue.Severity == severity {
+				n++
+			}
+		}
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, severity))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// verdictSummary counts the verdicts on earlier issues per status, e.g.
+// "2 resolved, 1 open".
+func verdictSummary(verdicts []types.IssueVerdict) string {
+	var parts []string
+	for _, status := range []types.IssueStatus{types.IssueResolved, types.IssueDisputed, types.IssueOpen} {
+		n := 0
+		for _, v := range verdicts {
+			if v.Status == status {
+				n++
+			}
+		}
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// issueGroups lists the files with issues per severity, most serious first,
+// e.g. "high: main.go (2), util.go".
+func issueGroups(issues []types.Issue) []string {
+	sorted := append([]types.Issue(nil), issues...)
+	types.SortIssues(sorted)
+
+	var lines []string
+	for i := 0; i < len(sorted); {
+		severity := sorted[i].Severity
+		var files []string
+		for i < len(sorted) && sorted[i].Severity == severity {
+			file := sorted[i].File
+			n := 0
+			for i < len(sorted) && sorted[i].Severity == severity && sorted[i].File == file {
+				n++
+				i++
+			}
+			if file == "" {
+				file = "(general)"
+			}
+			if n > 1 {
+				file += fmt.Sprintf(" (%d)", n)
+			}
+			files = append(files, file)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", severity, strings.Join(files, ", ")))
+	}
+	return lines
+}
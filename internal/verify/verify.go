@@ -0,0 +1,191 @@
+// Package verify runs the verification commands (builds, tests, linters) that
+// gate each round before the reviewer is called.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/internal/proc"
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+// DefaultTimeout is the default time limit for a single verification command.
+const DefaultTimeout = 10 * time.Minute
+
+// maxOutputSize is the maximum number of bytes of command output kept per result.
+// Longer output is truncated from the start, since failures are usually reported last.
+const maxOutputSize = 64 * 1024
+
+// maxIssueLines is the number of output lines included in the issue of a failed command.
+const maxIssueLines = 40
+
+// Runner runs a list of verification commands in a working directory.
+type Runner struct {
+	commands []string
+	workDir  string
+	timeout  time.Duration
+}
+
+// New creates a new Runner for the given commands.
+// Blank commands are ignored.
+func New(workDir string, commands []string, timeout time.Duration) *Runner {
+	cmds := make([]string, 0, len(commands))
+	for _, c := range commands {
+		if c = strings.TrimSpace(c); c != "" {
+			cmds = append(cmds, c)
+		}
+	}
+	return &Runner{
+		commands: cmds,
+		workDir:  workDir,
+		timeout:  timeout,
+	}
+}
+
+// Commands returns the commands the runner executes.
+func (r *Runner) Commands() []string {
+	return r.commands
+}
+
+// Enabled reports whether there is at least one command to run.
+func (r *Runner) Enabled() bool {
+	return len(r.commands) > 0
+}
+
+// Run executes the commands in order through the shell, stopping at the first
+// failure since later commands (e.g. tests after a broken build) would only
+// repeat it. An error is returned only if ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) ([]types.VerificationResult, error) {
+	results := make([]types.VerificationResult, 0, len(r.commands))
+	for _, command := range r.commands {
+		result := r.runCommand(ctx, command)
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		results = append(results, result)
+		if !result.Passed {
+			break
+		}
+	}
+	return results, nil
+}
+
+// runCommand executes a single command and captures its outcome.
+func (r *Runner) runCommand(ctx context.Context, command string) types.VerificationResult {
+	execCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := shellCommand(execCtx, command)
+	cmd.Dir = r.workDir
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err := proc.Run(cmd)
+	result := types.VerificationResult{
+		Command:  command,
+		Passed:   err == nil,
+		Output:   truncateOutput(output.String()),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case execCtx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.Output = appendLine(result.Output, fmt.Sprintf("timed out after %v", r.timeout))
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+		result.Output = appendLine(result.Output, err.Error())
+	}
+
+	return result
+}
+
+// shellCommand builds the command running command through the platform shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return proc.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return proc.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Passed reports whether every result passed.
+func Passed(results []types.VerificationResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Issues turns failed results into issues for the implementer, each with the
+// tail of the command output.
+func Issues(results []types.VerificationResult) []string {
+	var issues []string
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		issue := fmt.Sprintf("Verification command `%s` failed (exit code %d)", r.Command, r.ExitCode)
+		if tail := lastLines(strings.TrimSpace(r.Output), maxIssueLines); tail != "" {
+			issue += ":\n" + tail
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// Findings returns the failed commands as critical issues, which no fail-on
+// threshold lets through.
+func Findings(results []types.VerificationResult) []types.Issue {
+	var findings []types.Issue
+	for _, issue := range Issues(results) {
+		findings = append(findings, types.Issue{
+			ID:          types.IssueID("", issue),
+			Severity:    types.SeverityCritical,
+			Category:    "verification",
+			Description: issue,
+		})
+	}
+	return findings
+}
+
+// truncateOutput keeps the last maxOutputSize bytes of output.
+func truncateOutput(output string) string {
+	if len(output) <= maxOutputSize {
+		return output
+	}
+	return "...(truncated)\n" + output[len(output)-maxOutputSize:]
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
+
+// appendLine appends line to output on a line of its own.
+func appendLine(output, line string) string {
+	if output == "" || strings.HasSuffix(output, "\n") {
+		return output + line
+	}
+	return output + "\n" + line
+}
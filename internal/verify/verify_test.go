@@ -0,0 +1,133 @@
+package verify
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoram/mortal-prompter/pkg/types"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("verification tests use POSIX shell commands")
+	}
+}
+
+func TestNew_IgnoresBlankCommands(t *testing.T) {
+	r := New(".", []string{"go build ./...", "  ", ""}, DefaultTimeout)
+	if got := r.Commands(); len(got) != 1 || got[0] != "go build ./..." {
+		t.Errorf("Commands() = %v, want [go build ./...]", got)
+	}
+	if !r.Enabled() {
+		t.Error("Enabled() = false, want true")
+	}
+	if New(".", nil, DefaultTimeout).Enabled() {
+		t.Error("Enabled() = true for a runner without commands")
+	}
+}
+
+func TestRun_Passing(t *testing.T) {
+	skipOnWindows(t)
+
+	r := New(t.TempDir(), []string{"echo one", "echo two"}, DefaultTimeout)
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !Passed(results) {
+		t.Errorf("Passed() = false, results: %+v", results)
+	}
+	if results[1].Command != "echo two" || strings.TrimSpace(results[1].Output) != "two" {
+		t.Errorf("unexpected result: %+v", results[1])
+	}
+}
+
+func TestRun_StopsAtFirstFailure(t *testing.T) {
+	skipOnWindows(t)
+
+	r := New(t.TempDir(), []string{"echo broken >&2; exit 3", "echo never"}, DefaultTimeout)
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].Passed || results[0].ExitCode != 3 {
+		t.Errorf("result = %+v, want a failure with exit code 3", results[0])
+	}
+	if !strings.Contains(results[0].Output, "broken") {
+		t.Errorf("Output = %q, want stderr captured", results[0].Output)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	skipOnWindows(t)
+
+	r := New(t.TempDir(), []string{"sleep 5"}, 50*time.Millisecond)
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Passed || results[0].ExitCode != -1 {
+		t.Fatalf("results = %+v, want a timed out failure", results)
+	}
+	if !strings.Contains(results[0].Output, "timed out") {
+		t.Errorf("Output = %q, want a timeout note", results[0].Output)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	skipOnWindows(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := New(t.TempDir(), []string{"echo one"}, DefaultTimeout).Run(ctx); err == nil {
+		t.Error("expected error for a cancelled context")
+	}
+}
+
+func TestIssues(t *testing.T) {
+	var output strings.Builder
+	for i := 1; i <= 100; i++ {
+		output.WriteString("line\n")
+	}
+	output.WriteString("FAIL: TestSomething")
+
+	issues := Issues([]types.VerificationResult{
+		{Command: "go build ./...", Passed: true},
+		{Command: "go test ./...", ExitCode: 1, Output: output.String()},
+	})
+
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	if !strings.HasPrefix(issues[0], "Verification command `go test ./...` failed (exit code 1):") {
+		t.Errorf("unexpected issue header: %q", strings.SplitN(issues[0], "\n", 2)[0])
+	}
+	if !strings.HasSuffix(issues[0], "FAIL: TestSomething") {
+		t.Error("issue should end with the tail of the output")
+	}
+	if lines := strings.Count(issues[0], "\n"); lines != maxIssueLines {
+		t.Errorf("issue has %d output lines, want %d", lines, maxIssueLines)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	long := strings.Repeat("a", maxOutputSize) + "END"
+	got := truncateOutput(long)
+	if !strings.HasSuffix(got, "END") || !strings.HasPrefix(got, "...(truncated)") {
+		t.Errorf("truncateOutput should keep the end of the output")
+	}
+	if truncateOutput("short") != "short" {
+		t.Error("short output should be kept as is")
+	}
+}
@@ -0,0 +1,249 @@
+package types
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks how serious a review issue is.
+type Severity string
+
+const (
+	// SeverityCritical marks issues that break the build, lose data or open a vulnerability
+	SeverityCritical Severity = "critical"
+
+	// SeverityHigh marks bugs and missing error handling
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium marks bad practices, and issues whose reviewer gave no severity
+	SeverityMedium Severity = "medium"
+
+	// SeverityLow marks nits: style, naming, comments
+	SeverityLow Severity = "low"
+)
+
+// Severities lists the severities from the most to the least serious.
+var Severities = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// ParseSeverity converts a severity name, or a P0-P3 priority tag as used by
+// Codex, to a Severity.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical", "p0":
+		return SeverityCritical, nil
+	case "high", "p1":
+		return SeverityHigh, nil
+	case "medium", "p2":
+		return SeverityMedium, nil
+	case "low", "p3", "p4":
+		return SeverityLow, nil
+	default:
+		return "", fmt.Errorf("unknown severity: %s (valid: critical, high, medium, low)", s)
+	}
+}
+
+// rank orders the severities, with 0 for an unknown severity.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as serious as threshold or more. Every
+// severity meets an empty threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+// Issue is a single problem found during a review, in structured form.
+type Issue struct {
+	// ID identifies the issue across reviewers and rounds, derived from its file and description
+	ID string
+
+	// Severity is how serious the issue is
+	Severity Severity
+
+	// Category is the kind of issue as given by the reviewer (e.g. bug, security), if any
+	Category string
+
+	// File is the path of the file the issue is in, if the reviewer named one
+	File string
+
+	// StartLine is the first line of the issue in File (0 if unknown)
+	StartLine int
+
+	// EndLine is the last line of the issue in File (0 if unknown or a single line)
+	EndLine int
+
+	// Description is the issue without its severity, category and location
+	Description string
+
+	// Reviewer is the display name of the reviewers that reported the issue
+	Reviewer string
+}
+
+// Location returns the "file:start-end" location of the issue, or an empty
+// string if the reviewer did not name a file.
+func (i Issue) Location() string {
+	if i.File == "" {
+		return ""
+	}
+	switch {
+	case i.StartLine == 0:
+		return i.File
+	case i.EndLine > i.StartLine:
+		return fmt.Sprintf("%s:%d-%d", i.File, i.StartLine, i.EndLine)
+	default:
+		return fmt.Sprintf("%s:%d", i.File, i.StartLine)
+	}
+}
+
+// String returns the display form of the issue, e.g. "[high] main.go:12: description".
+func (i Issue) String() string {
+	var sb strings.Builder
+	if i.Severity != "" {
+		sb.WriteString("[" + string(i.Severity) + "] ")
+	}
+	if loc := i.Location(); loc != "" {
+		sb.WriteString(loc + ": ")
+	}
+	sb.WriteString(i.Description)
+	return sb.String()
+}
+
+var (
+	// prefixPattern matches a leading list marker and "ISSUE:" label
+	prefixPattern = regexp.MustCompile(`^(?i)(?:[-*•]\s+)?(?:issue\s*:\s*)?`)
+
+	// tagPattern matches a leading "[tag]" such as a severity or a category
+	tagPattern = regexp.MustCompile(`^\[([^\]]*)\]\s*`)
+
+	// labelPattern matches a leading "severity:" label
+	labelPattern = regexp.MustCompile(`^(?i)(critical|high|medium|low)\s*:\s*`)
+
+	// locationPattern matches a "path/file.ext:line" or "path/file.ext:start-end" location
+	locationPattern = regexp.MustCompile("(?:^|[\\s(`'\"])((?:[\\w.-]+/)*[\\w-][\\w.-]*\\.[A-Za-z]\\w*):(\\d+)(?:-(\\d+))?")
+
+	// separatorPattern matches what separates a leading location from the description
+	separatorPattern = regexp.MustCompile("^[`'\")]*\\s*(?:[-:–—]\\s*)?")
+)
+
+// ParseIssue converts an issue in the "[severity] [category] file:line - description"
+// form that reviewers are asked for into an Issue. Every part but the description
+// is optional; an issue without a severity is of medium severity, and a location
+// anywhere in the description is picked up.
+func ParseIssue(text string) Issue {
+	rest := strings.TrimSpace(text)
+	rest = rest[len(prefixPattern.FindString(rest)):]
+	issue := Issue{Severity: SeverityMedium}
+
+	severity := false
+	for {
+		m := tagPattern.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		tag := strings.TrimSpace(m[1])
+		if s, err := ParseSeverity(tag); err == nil && !severity {
+			issue.Severity = s
+			severity = true
+		} else if issue.Category == "" && tag != "" {
+			issue.Category = strings.ToLower(tag)
+		} else {
+			break
+		}
+		rest = rest[len(m[0]):]
+	}
+	if m := labelPattern.FindStringSubmatch(rest); m != nil && !severity {
+		issue.Severity, _ = ParseSeverity(m[1])
+		rest = rest[len(m[0]):]
+	}
+
+	if loc := locationPattern.FindStringSubmatchIndex(rest); loc != nil {
+		issue.File = rest[loc[2]:loc[3]]
+		issue.StartLine, _ = strconv.Atoi(rest[loc[4]:loc[5]])
+		if loc[6] >= 0 {
+			issue.EndLine, _ = strconv.Atoi(rest[loc[6]:loc[7]])
+		}
+		// A leading location is not part of the description
+		if strings.TrimLeft(rest[:loc[2]], "`'\"( ") == "" {
+			tail := rest[loc[1]:]
+			rest = tail[len(separatorPattern.FindString(tail)):]
+		}
+	}
+
+	issue.Description = strings.TrimSpace(rest)
+	if issue.Description == "" {
+		issue.Description = strings.TrimSpace(text)
+	}
+	issue.ID = IssueID(issue.File, issue.Description)
+	return issue
+}
+
+// ParseIssues converts every issue in issues with ParseIssue.
+func ParseIssues(issues []string) []Issue {
+	if len(issues) == 0 {
+		return nil
+	}
+	result := make([]Issue, 0, len(issues))
+	for _, text := range issues {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		result = append(result, ParseIssue(text))
+	}
+	return result
+}
+
+// IssueID derives the identifier of an issue from its file and description,
+// ignoring case, whitespace and trailing punctuation, so that the same issue
+// reported twice gets the same identifier.
+func IssueID(file, description string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(description), " "))
+	key = strings.TrimRight(key, ".;:!")
+	sum := sha1.Sum([]byte(strings.ToLower(file) + "\x00" + key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// CountAtLeast returns the number of issues as serious as threshold or more.
+func CountAtLeast(issues []Issue, threshold Severity) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity.AtLeast(threshold) {
+			n++
+		}
+	}
+	return n
+}
+
+// SortIssues sorts issues from the most to the least serious, and by file and
+// line within a severity. Issues without a file come after those with one.
+func SortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(a, b int) bool {
+		x, y := issues[a], issues[b]
+		if x.Severity.rank() != y.Severity.rank() {
+			return x.Severity.rank() > y.Severity.rank()
+		}
+		if (x.File == "") != (y.File == "") {
+			return x.File != ""
+		}
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		return x.StartLine < y.StartLine
+	})
+}
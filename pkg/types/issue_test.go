@@ -0,0 +1,154 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Severity
+		wantErr bool
+	}{
+		{"critical", SeverityCritical, false},
+		{"HIGH", SeverityHigh, false},
+		{" medium ", SeverityMedium, false},
+		{"low", SeverityLow, false},
+		{"P0", SeverityCritical, false},
+		{"p1", SeverityHigh, false},
+		{"P2", SeverityMedium, false},
+		{"P3", SeverityLow, false},
+		{"blocker", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSeverity(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	if !SeverityHigh.AtLeast(SeverityMedium) {
+		t.Error("high should meet a medium threshold")
+	}
+	if !SeverityMedium.AtLeast(SeverityMedium) {
+		t.Error("medium should meet a medium threshold")
+	}
+	if SeverityLow.AtLeast(SeverityMedium) {
+		t.Error("low should not meet a medium threshold")
+	}
+	if !SeverityLow.AtLeast("") {
+		t.Error("every severity should meet an empty threshold")
+	}
+}
+
+func TestParseIssue(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Issue
+	}{
+		{
+			name:  "plain description",
+			input: "Missing error handling",
+			want:  Issue{Severity: SeverityMedium, Description: "Missing error handling"},
+		},
+		{
+			name:  "requested format",
+			input: "[high] internal/git/git.go:42 - Error from Run is ignored",
+			want: Issue{Severity: SeverityHigh, File: "internal/git/git.go", StartLine: 42,
+				Description: "Error from Run is ignored"},
+		},
+		{
+			name:  "category and line range",
+			input: "[critical] [security] cmd/main.go:10-14: Command built from user input",
+			want: Issue{Severity: SeverityCritical, Category: "security", File: "cmd/main.go", StartLine: 10, EndLine: 14,
+				Description: "Command built from user input"},
+		},
+		{
+			name:  "codex priority tag",
+			input: "[P2] Prefer strings.Builder in `report.go:88`",
+			want: Issue{Severity: SeverityMedium, File: "report.go", StartLine: 88,
+				Description: "Prefer strings.Builder in `report.go:88`"},
+		},
+		{
+			name:  "raw review line",
+			input: "- ISSUE: [P1] Race on counter in worker.go:31-33",
+			want: Issue{Severity: SeverityHigh, File: "worker.go", StartLine: 31, EndLine: 33,
+				Description: "Race on counter in worker.go:31-33"},
+		},
+		{
+			name:  "severity label",
+			input: "Low: rename variable x",
+			want:  Issue{Severity: SeverityLow, Description: "rename variable x"},
+		},
+		{
+			name:  "version numbers are not locations",
+			input: "[low] Bump to v1.2 for the fix",
+			want:  Issue{Severity: SeverityLow, Description: "Bump to v1.2 for the fix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseIssue(tt.input)
+			tt.want.ID = IssueID(tt.want.File, tt.want.Description)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIssue(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueID(t *testing.T) {
+	if IssueID("main.go", "Missing tests.") != IssueID("main.go", "missing  tests") {
+		t.Error("IssueID() should ignore case, whitespace and trailing punctuation")
+	}
+	if IssueID("main.go", "Missing tests") == IssueID("util.go", "Missing tests") {
+		t.Error("IssueID() should differ for different files")
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{Severity: SeverityHigh, File: "main.go", StartLine: 3, EndLine: 5, Description: "Unchecked error"}
+	if got := issue.String(); got != "[high] main.go:3-5: Unchecked error" {
+		t.Errorf("String() = %q", got)
+	}
+
+	issue = Issue{Severity: SeverityLow, Description: "Typo in comment"}
+	if got := issue.String(); got != "[low] Typo in comment" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	issues := []Issue{
+		{Severity: SeverityLow, File: "a.go", Description: "nit"},
+		{Severity: SeverityHigh, Description: "general"},
+		{Severity: SeverityHigh, File: "b.go", StartLine: 9, Description: "b9"},
+		{Severity: SeverityHigh, File: "b.go", StartLine: 2, Description: "b2"},
+		{Severity: SeverityCritical, File: "z.go", Description: "crash"},
+	}
+	SortIssues(issues)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Description)
+	}
+	want := []string{"crash", "b2", "b9", "general", "nit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortIssues() order = %v, want %v", got, want)
+	}
+
+	if n := CountAtLeast(issues, SeverityHigh); n != 4 {
+		t.Errorf("CountAtLeast(high) = %d, want 4", n)
+	}
+}
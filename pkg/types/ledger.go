@@ -0,0 +1,265 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IssueStatus is the state of an issue in the ledger.
+type IssueStatus string
+
+const (
+	// IssueOpen marks issues still present in the changes
+	IssueOpen IssueStatus = "open"
+
+	// IssueResolved marks issues the changes fixed
+	IssueResolved IssueStatus = "resolved"
+
+	// IssueDisputed marks issues the reviewer no longer stands behind, such as
+	// those that turned out not to be real problems
+	IssueDisputed IssueStatus = "disputed"
+)
+
+// ParseIssueStatus converts a status name to an IssueStatus.
+func ParseIssueStatus(s string) (IssueStatus, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "open", "still open", "unresolved":
+		return IssueOpen, nil
+	case "resolved", "fixed":
+		return IssueResolved, nil
+	case "disputed":
+		return IssueDisputed, nil
+	default:
+		return "", fmt.Errorf("unknown issue status: %s (valid: resolved, open, disputed)", s)
+	}
+}
+
+// rank orders the statuses by how much they hold a round back, so that the
+// most cautious verdict of a panel wins.
+func (s IssueStatus) rank() int {
+	switch s {
+	case IssueOpen:
+		return 3
+	case IssueDisputed:
+		return 2
+	case IssueResolved:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IssueVerdict is a reviewer's verdict on an issue raised in an earlier round.
+type IssueVerdict struct {
+	// ID identifies the issue, as given in the ledger
+	ID string
+
+	// Status is whether the issue is resolved, still open or disputed
+	Status IssueStatus
+
+	// Note is the reviewer's reason for the verdict, if any
+	Note string
+
+	// Reviewer is the display name of the reviewers that gave the verdict
+	Reviewer string
+}
+
+// verdictPattern matches a "STATUS: #id resolved - reason" line of a review.
+var verdictPattern = regexp.MustCompile(`(?im)^[ \t>*-]*status[ \t]*:?[ \t]*\**[ \t]*#?([0-9a-f]{8})\b[ \t:*\-–—]*(resolved|fixed|still open|open|unresolved|disputed)\b[ \t*.:\-–—]*(.*)$`)
+
+// ParseVerdicts extracts the verdicts on earlier issues from a review, given
+// as "STATUS: #id resolved|open|disputed - reason" lines.
+func ParseVerdicts(output string) []IssueVerdict {
+	var verdicts []IssueVerdict
+	seen := make(map[string]bool)
+	for _, m := range verdictPattern.FindAllStringSubmatch(output, -1) {
+		id := strings.ToLower(m[1])
+		if seen[id] {
+			continue
+		}
+		status, err := ParseIssueStatus(m[2])
+		if err != nil {
+			continue
+		}
+		seen[id] = true
+		verdicts = append(verdicts, IssueVerdict{ID: id, Status: status, Note: strings.TrimSpace(m[3])})
+	}
+	return verdicts
+}
+
+// LedgerEntry follows an issue from the round it was raised in to the round
+// it was resolved or disputed in.
+type LedgerEntry struct {
+	// Issue is the issue as last reported
+	Issue Issue
+
+	// Status is the current state of the issue
+	Status IssueStatus
+
+	// RaisedIn is the round the issue was first reported in
+	RaisedIn int
+
+	// ClosedIn is the round the issue was resolved or disputed in, 0 while open
+	ClosedIn int
+
+	// Reopened counts how often the issue was reported again after being closed
+	Reopened int
+
+	// Note is the reviewer's reason for the last verdict, if any
+	Note string
+}
+
+// Ledger follows the issues raised by the reviewers across the rounds of a
+// session, so that each one is checked until it is resolved instead of being
+// dropped or raised again in other words.
+type Ledger struct {
+	entries []LedgerEntry
+	byID    map[string]int
+}
+
+// NewLedger returns the ledger of the given rounds.
+func NewLedger(rounds []Round) *Ledger {
+	l := &Ledger{byID: make(map[string]int)}
+	for i := range rounds {
+		l.Record(&rounds[i])
+	}
+	return l
+}
+
+// Record applies the verdicts of a round on the earlier issues, then adds the
+// issues its reviewers raised. Rounds that were not reviewed change nothing.
+// An open issue without a verdict stays open, unless every reviewer of the
+// round approved the changes, which resolves it.
+func (l *Ledger) Record(round *Round) {
+	if len(round.Reviews) == 0 {
+		return
+	}
+
+	judged := make(map[string]bool)
+	for _, v := range round.Verdicts {
+		i, ok := l.byID[v.ID]
+		if !ok {
+			continue
+		}
+		judged[v.ID] = true
+		entry := &l.entries[i]
+		entry.Status = v.Status
+		entry.Note = v.Note
+		if v.Status == IssueOpen {
+			entry.ClosedIn = 0
+		} else if entry.ClosedIn == 0 {
+			entry.ClosedIn = round.Number
+		}
+	}
+
+	if approved(round.Reviews) {
+		for i := range l.entries {
+			entry := &l.entries[i]
+			if entry.Status == IssueOpen && entry.RaisedIn < round.Number && !judged[entry.Issue.ID] {
+				entry.Status = IssueResolved
+				entry.Note = "approved without a verdict"
+				entry.ClosedIn = round.Number
+			}
+		}
+	}
+
+	for _, review := range round.Reviews {
+		if !review.HasIssues {
+			continue
+		}
+		for _, issue := range review.Findings {
+			i, ok := l.byID[issue.ID]
+			if !ok {
+				l.byID[issue.ID] = len(l.entries)
+				l.entries = append(l.entries, LedgerEntry{Issue: issue, Status: IssueOpen, RaisedIn: round.Number})
+				continue
+			}
+			entry := &l.entries[i]
+			if entry.Status != IssueOpen && entry.ClosedIn < round.Number {
+				entry.Reopened++
+			}
+			if entry.Issue.Severity.AtLeast(issue.Severity) {
+				issue.Severity = entry.Issue.Severity
+			}
+			entry.Issue = issue
+			entry.Status = IssueOpen
+			entry.ClosedIn = 0
+		}
+	}
+}
+
+// approved reports whether every review approved the changes.
+func approved(reviews []ReviewResult) bool {
+	for _, review := range reviews {
+		if review.HasIssues {
+			return false
+		}
+	}
+	return true
+}
+
+// Entries returns every issue of the ledger, in the order they were raised.
+func (l *Ledger) Entries() []LedgerEntry {
+	return append([]LedgerEntry(nil), l.entries...)
+}
+
+// Open returns the issues that are still open, in the order they were raised.
+func (l *Ledger) Open() []Issue {
+	var open []Issue
+	for _, entry := range l.entries {
+		if entry.Status == IssueOpen {
+			open = append(open, entry.Issue)
+		}
+	}
+	return open
+}
+
+// Carried returns the open issues raised before round that its reviewers did
+// not report again, which the round still has to address.
+func (l *Ledger) Carried(round *Round) []LedgerEntry {
+	reported := make(map[string]bool)
+	for _, review := range round.Reviews {
+		for _, issue := range review.Findings {
+			reported[issue.ID] = true
+		}
+	}
+
+	var carried []LedgerEntry
+	for _, entry := range l.entries {
+		if entry.Status == IssueOpen && entry.RaisedIn < round.Number && !reported[entry.Issue.ID] {
+			carried = append(carried, entry)
+		}
+	}
+	return carried
+}
+
+// MergeVerdicts combines the verdicts of several reviewers. When they
+// disagree on an issue, open wins over disputed, and disputed over resolved.
+func MergeVerdicts(reviews []ReviewResult) []IssueVerdict {
+	var merged []IssueVerdict
+	byID := make(map[string]int)
+
+	for _, r := range reviews {
+		for _, v := range r.Verdicts {
+			if v.Reviewer == "" {
+				v.Reviewer = r.Reviewer
+			}
+			i, ok := byID[v.ID]
+			if !ok {
+				byID[v.ID] = len(merged)
+				merged = append(merged, v)
+				continue
+			}
+			m := &merged[i]
+			if v.Status.rank() > m.Status.rank() {
+				m.Status = v.Status
+				m.Note = v.Note
+			}
+			if v.Reviewer != "" && !strings.Contains(", "+m.Reviewer+", ", ", "+v.Reviewer+", ") {
+				m.Reviewer += ", " + v.Reviewer
+			}
+		}
+	}
+	return merged
+}
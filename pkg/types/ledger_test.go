@@ -0,0 +1,149 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestParseVerdicts(t *testing.T) {
+	output := `The nil check was added.
+
+STATUS: #1a2b3c4d resolved - guarded in Load
+- **STATUS:** #DEADBEEF still open: the error is still ignored
+status: #0f0f0f0f disputed
+STATUS: #1a2b3c4d open - repeated
+STATUS: #123 resolved
+[high] main.go:3 - New issue`
+
+	got := ParseVerdicts(output)
+	want := []IssueVerdict{
+		{ID: "1a2b3c4d", Status: IssueResolved, Note: "guarded in Load"},
+		{ID: "deadbeef", Status: IssueOpen, Note: "the error is still ignored"},
+		{ID: "0f0f0f0f", Status: IssueDisputed},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseVerdicts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("verdict %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLedger(t *testing.T) {
+	nilDeref := ParseIssue("[high] a.go:3 - Nil dereference")
+	naming := ParseIssue("[low] Rename x")
+	leak := ParseIssue("[medium] b.go:9 - File left open")
+
+	rounds := []Round{
+		{Number: 1, Reviews: []ReviewResult{{HasIssues: true, Findings: []Issue{nilDeref, naming}}}},
+		// Verification failed, so the round was not reviewed
+		{Number: 2},
+		{
+			Number:   3,
+			Reviews:  []ReviewResult{{HasIssues: true, Findings: []Issue{leak}}},
+			Verdicts: []IssueVerdict{{ID: nilDeref.ID, Status: IssueResolved, Note: "guarded"}, {ID: "ffffffff", Status: IssueOpen}},
+		},
+	}
+
+	ledger := NewLedger(rounds[:2])
+	if open := ledger.Open(); len(open) != 2 {
+		t.Fatalf("Open() = %+v, want both issues of round 1", open)
+	}
+
+	ledger.Record(&rounds[2])
+	entries := ledger.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Entries() = %+v, want the unknown verdict ignored", entries)
+	}
+	if e := entries[0]; e.Status != IssueResolved || e.RaisedIn != 1 || e.ClosedIn != 3 || e.Note != "guarded" {
+		t.Errorf("resolved entry = %+v", e)
+	}
+	if e := entries[1]; e.Status != IssueOpen || e.ClosedIn != 0 {
+		t.Errorf("issue without a verdict = %+v, want it still open", e)
+	}
+
+	carried := ledger.Carried(&rounds[2])
+	if len(carried) != 1 || carried[0].Issue.ID != naming.ID {
+		t.Errorf("Carried() = %+v, want the issue without a verdict", carried)
+	}
+}
+
+func TestLedger_ApprovalResolves(t *testing.T) {
+	nilDeref := ParseIssue("[high] a.go:3 - Nil dereference")
+	naming := ParseIssue("[low] Rename x")
+
+	ledger := NewLedger([]Round{
+		{Number: 1, Reviews: []ReviewResult{{HasIssues: true, Findings: []Issue{nilDeref, naming}}}},
+		// Approved, leaving out the verdicts but for the one kept open
+		{Number: 2, Reviews: []ReviewResult{{}}, Verdicts: []IssueVerdict{{ID: naming.ID, Status: IssueOpen}}},
+	})
+
+	entries := ledger.Entries()
+	if e := entries[0]; e.Status != IssueResolved || e.ClosedIn != 2 {
+		t.Errorf("issue without a verdict = %+v, want it resolved by the approval", e)
+	}
+	if e := entries[1]; e.Status != IssueOpen || e.ClosedIn != 0 {
+		t.Errorf("issue confirmed open = %+v, want it still open", e)
+	}
+
+	// Not every reviewer approved, so the issue stays open
+	ledger = NewLedger([]Round{
+		{Number: 1, Reviews: []ReviewResult{{HasIssues: true, Findings: []Issue{nilDeref}}}},
+		{Number: 2, Reviews: []ReviewResult{{}, {HasIssues: true, Findings: []Issue{naming}}}},
+	})
+	if open := ledger.Open(); len(open) != 2 {
+		t.Errorf("Open() = %+v, want both issues", open)
+	}
+}
+
+func TestLedger_Reopen(t *testing.T) {
+	issue := ParseIssue("[medium] a.go:3 - Nil dereference")
+	worse := issue
+	worse.Severity = SeverityLow
+
+	ledger := NewLedger([]Round{
+		{Number: 1, Reviews: []ReviewResult{{HasIssues: true, Findings: []Issue{issue}}}},
+		{Number: 2, Reviews: []ReviewResult{{}}, Verdicts: []IssueVerdict{{ID: issue.ID, Status: IssueResolved}}},
+		{Number: 3, Reviews: []ReviewResult{{HasIssues: true, Findings: []Issue{worse}}}},
+	})
+
+	entries := ledger.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Entries() = %+v, want the issue reopened, not raised again", entries)
+	}
+	e := entries[0]
+	if e.Status != IssueOpen || e.ClosedIn != 0 || e.Reopened != 1 || e.RaisedIn != 1 {
+		t.Errorf("reopened entry = %+v", e)
+	}
+	if e.Issue.Severity != SeverityMedium {
+		t.Errorf("Severity = %s, want the higher severity kept", e.Issue.Severity)
+	}
+
+	// Re-reported issues are not carried, the round already has them
+	if carried := ledger.Carried(&Round{Number: 3, Reviews: []ReviewResult{{Findings: []Issue{worse}}}}); len(carried) != 0 {
+		t.Errorf("Carried() = %+v, want nothing", carried)
+	}
+}
+
+func TestMergeVerdicts(t *testing.T) {
+	reviews := []ReviewResult{
+		{Reviewer: "CODEX", Verdicts: []IssueVerdict{{ID: "aaaaaaaa", Status: IssueResolved}, {ID: "bbbbbbbb", Status: IssueResolved}}},
+		{Reviewer: "GEMINI", Verdicts: []IssueVerdict{{ID: "aaaaaaaa", Status: IssueOpen, Note: "still there"}, {ID: "bbbbbbbb", Status: IssueDisputed}}},
+		{Reviewer: "CLAUDE", Verdicts: []IssueVerdict{{ID: "bbbbbbbb", Status: IssueResolved}}},
+	}
+
+	got := MergeVerdicts(reviews)
+	want := []IssueVerdict{
+		{ID: "aaaaaaaa", Status: IssueOpen, Note: "still there", Reviewer: "CODEX, GEMINI"},
+		{ID: "bbbbbbbb", Status: IssueDisputed, Reviewer: "CODEX, GEMINI, CLAUDE"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MergeVerdicts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("verdict %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -3,60 +3,379 @@ package types
 
 import "time"
 
-// Round represents a single iteration in the code review battle between Claude and Codex.
-// Each round consists of Claude implementing changes and Codex reviewing them.
+// Round represents a single iteration in the code review battle between the implementer and reviewer.
+// Each round consists of the implementer making changes and the reviewer reviewing them.
 type Round struct {
 	// Number is the sequential round number (1-indexed)
 	Number int
 
-	// ClaudePrompt is the prompt sent to Claude Code for this round
-	ClaudePrompt string
+	// Implementer is the display name of the fighter that implemented this round
+	Implementer string
 
-	// ClaudeOutput is the raw output captured from Claude Code execution
-	ClaudeOutput string
+	// Reviewer is the display name of the fighter that reviewed this round
+	Reviewer string
 
-	// GitDiff contains the git diff of changes made by Claude in this round
+	// ImplementerPrompt is the prompt sent to the implementer for this round
+	ImplementerPrompt string
+
+	// ImplementerOutput is the raw output captured from the implementer execution
+	ImplementerOutput string
+
+	// GitDiff contains the git diff of changes made by the implementer in this round
 	GitDiff string
 
-	// CodexReview contains the raw review output from Codex
-	CodexReview string
+	// SnapshotSHA is the commit recording the tree after the implementer's changes
+	SnapshotSHA string
+
+	// Verification contains the result of each verification command run before the review
+	Verification []VerificationResult
 
-	// HasIssues indicates whether Codex found any issues in this round
+	// ReviewerOutput contains the raw review output from the reviewer
+	ReviewerOutput string
+
+	// HasIssues indicates whether the reviewer found any issues in this round
 	HasIssues bool
 
-	// Issues is the list of specific issues found by Codex
+	// Issues is the list of specific issues found by the reviewer
 	Issues []string
 
+	// Findings are the issues of the round in structured form, including those
+	// below the fail-on threshold that did not block it
+	Findings []Issue
+
+	// Reviews contains the result of each reviewer on the panel
+	Reviews []ReviewResult
+
+	// Verdicts are the reviewers' verdicts on the issues of earlier rounds
+	Verdicts []IssueVerdict
+
+	// Chunks are the verdicts on the parts of a diff too large to review at
+	// once, empty when it was reviewed whole
+	Chunks []ChunkReview
+
+	// Hooks contains the result of each lifecycle hook run during this round
+	Hooks []HookResult
+
+	// Violations are the guardrails the implementer's changes broke
+	Violations []Violation
+
+	// Usage is the usage reported by the fighters during this round
+	Usage Usage
+
 	// Duration is how long this round took to complete
 	Duration time.Duration
 
 	// Timestamp is when this round started
 	Timestamp time.Time
+
+	// Outcome is how the round ended. Rounds that did not complete hold
+	// what they got done
+	Outcome RoundOutcome
+
+	// Error is the error that ended the round early, if any
+	Error string
 }
 
-// ReviewResult represents the parsed output from Codex's code review.
+// RoundOutcome is how a round ended.
+type RoundOutcome string
+
+const (
+	// OutcomeReviewed indicates the reviewers reviewed the changes of the round
+	OutcomeReviewed RoundOutcome = "reviewed"
+
+	// OutcomeRejected indicates the changes failed verification, were vetoed
+	// by a hook or broke the guardrails, and went back to the implementer
+	// without a review
+	OutcomeRejected RoundOutcome = "rejected"
+
+	// OutcomeNoChanges indicates the implementer left the tree unchanged
+	OutcomeNoChanges RoundOutcome = "no_changes"
+
+	// OutcomeImplementerError indicates the implementer failed
+	OutcomeImplementerError RoundOutcome = "implementer_error"
+
+	// OutcomeReviewerError indicates the review failed
+	OutcomeReviewerError RoundOutcome = "reviewer_error"
+
+	// OutcomeInterrupted indicates the session was stopped during the round
+	OutcomeInterrupted RoundOutcome = "interrupted"
+)
+
+// Completed reports whether the round ran to the end, whatever the verdict.
+func (o RoundOutcome) Completed() bool {
+	switch o {
+	case OutcomeImplementerError, OutcomeReviewerError, OutcomeInterrupted:
+		return false
+	default:
+		return true
+	}
+}
+
+// ReviewRequest is what a reviewer is asked to review: the changes of a round,
+// checked against the approved plan if there is one, or the plan alone
+// during the planning phase.
+type ReviewRequest struct {
+	// Prompt is the task of the session, empty when reviewing changes on their own
+	Prompt string
+
+	// Round is the number of the round under review, 0 outside of the battle rounds
+	Round int
+
+	// Diff is the git diff of the changes to review, empty when reviewing a plan
+	Diff string
+
+	// Plan is the approved plan the changes should follow, or the plan to critique
+	Plan string
+
+	// Range is the git revision range Diff was taken from (e.g. "main...HEAD"),
+	// empty when Diff holds the uncommitted changes of the working tree
+	Range string
+
+	// OpenIssues are the issues of earlier rounds that are still open, for
+	// the reviewer to mark as resolved, still open or disputed
+	OpenIssues []Issue
+
+	// Chunk places Diff within a larger diff reviewed in parts, nil when Diff
+	// holds all the changes
+	Chunk *ReviewChunk
+}
+
+// ReviewChunk places the diff of a review request within a larger diff that
+// was split into parts to be reviewed.
+type ReviewChunk struct {
+	// Index is the 1-based number of the part, 0 for the cross-file pass,
+	// whose diff is an outline of all the parts
+	Index int
+
+	// Total is the number of parts
+	Total int
+
+	// Files are the files changed in the whole diff
+	Files []string
+
+	// Findings are the issues found in the parts, for the cross-file pass
+	Findings []Issue
+}
+
+// CrossFile reports whether the chunk is the cross-file pass over all the parts.
+func (c ReviewChunk) CrossFile() bool {
+	return c.Index == 0
+}
+
+// ChunkReview is the panel's verdict on one part of a diff reviewed in parts.
+type ChunkReview struct {
+	// Index is the 1-based number of the part, 0 for the cross-file pass
+	Index int
+
+	// Total is the number of parts
+	Total int
+
+	// Files are the files changed in the part
+	Files []string
+
+	// Bytes is the size of the diff of the part
+	Bytes int
+
+	// HasIssues is the panel's verdict on the part
+	HasIssues bool
+
+	// Findings are the issues found in the part
+	Findings []Issue
+
+	// Duration is how long the review of the part took
+	Duration time.Duration
+}
+
+// IsPlanReview reports whether the request asks for a critique of the plan
+// rather than a review of code changes.
+func (r ReviewRequest) IsPlanReview() bool {
+	return r.Diff == "" && r.Plan != ""
+}
+
+// Plan represents the plan agreed on before the first implementation round.
+type Plan struct {
+	// Planner is the display name of the fighter that drafted the plan
+	Planner string
+
+	// Text is the approved plan, as included in every implementer prompt
+	Text string
+
+	// Critique contains the issues the reviewers raised on the draft
+	Critique []string
+
+	// Edited indicates whether the user changed the plan before approving it
+	Edited bool
+
+	// Usage is the usage reported by the fighters during planning
+	Usage Usage
+}
+
+// ReviewResult represents the parsed output from a reviewer's code review.
 type ReviewResult struct {
+	// Reviewer is the display name of the fighter that produced this review
+	Reviewer string
+
 	// HasIssues indicates whether any issues were found during review
 	HasIssues bool
 
 	// Issues is the list of specific issues identified
 	Issues []string
 
-	// RawOutput is the complete raw output from Codex
+	// Findings are the issues in structured form, parsed from Issues
+	Findings []Issue
+
+	// Verdicts are the reviewer's verdicts on the open issues of the request
+	Verdicts []IssueVerdict
+
+	// RawOutput is the complete raw output from the reviewer
 	RawOutput string
+
+	// Duration is how long the review took
+	Duration time.Duration
+
+	// Usage is the usage reported by the reviewer for this review
+	Usage Usage
+}
+
+// Usage represents the tokens and money spent by fighters that report it.
+type Usage struct {
+	// InputTokens is the number of prompt tokens consumed
+	InputTokens int
+
+	// OutputTokens is the number of tokens generated
+	OutputTokens int
+
+	// CostUSD is the cost in US dollars
+	CostUSD float64
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		CostUSD:      u.CostUSD + other.CostUSD,
+	}
+}
+
+// Sub returns the difference between u and other.
+func (u Usage) Sub(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens - other.InputTokens,
+		OutputTokens: u.OutputTokens - other.OutputTokens,
+		CostUSD:      u.CostUSD - other.CostUSD,
+	}
+}
+
+// VerificationResult represents the outcome of a verification command run on a round's changes.
+type VerificationResult struct {
+	// Command is the shell command that was run
+	Command string
+
+	// Passed indicates whether the command exited successfully
+	Passed bool
+
+	// ExitCode is the exit code of the command, or -1 if it could not be run
+	ExitCode int
+
+	// Output is the combined stdout and stderr of the command
+	Output string
+
+	// Duration is how long the command took
+	Duration time.Duration
+}
+
+// HookResult represents the outcome of a user-defined lifecycle hook.
+type HookResult struct {
+	// Hook is the point of the session the hook ran at (e.g. "after_diff")
+	Hook string
+
+	// Command is the shell command that was run
+	Command string
+
+	// Vetoed indicates whether the hook exited with a non-zero code, vetoing the step
+	Vetoed bool
+
+	// ExitCode is the exit code of the command, or -1 if it could not be run
+	ExitCode int
+
+	// Output is what the hook wrote to stderr
+	Output string
+
+	// Issues are the issues the hook wrote to stdout, one per line
+	Issues []string
+
+	// Duration is how long the hook took
+	Duration time.Duration
+}
+
+// Violation is a guardrail broken by the changes of an implementer run.
+type Violation struct {
+	// Rule is the guardrail broken (e.g. "protected_path")
+	Rule string
+
+	// Path is the protected path changed, empty for size limits
+	Path string
+
+	// Message describes the violation
+	Message string
+
+	// Reverted indicates whether the offending changes were reverted
+	Reverted bool
+}
+
+// PathRules are glob patterns limiting the paths staged, reviewed and committed.
+type PathRules struct {
+	// Include are the patterns of the paths to keep; all paths are kept without any
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are the patterns of the paths to leave out
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Empty reports whether the rules keep every path.
+func (r PathRules) Empty() bool {
+	return len(r.Include) == 0 && len(r.Exclude) == 0
 }
 
 // SessionResult represents the final outcome of a mortal-prompter session.
 type SessionResult struct {
+	// SessionID is the identifier of the session, used for checkpoints
+	SessionID string
+
 	// Success indicates whether the session completed successfully (no issues remaining)
 	Success bool
 
+	// Implementer is the display name of the fighter used as implementer
+	Implementer string
+
+	// Reviewer is the display name of the fighter used as reviewer
+	Reviewer string
+
+	// ReviewPolicy is the consensus policy of the reviewer panel
+	ReviewPolicy string
+
+	// FailOn is the lowest severity of the issues that keep the battle going
+	FailOn Severity
+
+	// State is the state the session ended in
+	State SessionState
+
+	// StopReason explains why the session ended
+	StopReason string
+
+	// Branch is the session branch left in the repository in worktree mode
+	Branch string
+
 	// TotalRounds is the number of rounds executed during the session
 	TotalRounds int
 
 	// TotalDuration is the total time the session took
 	TotalDuration time.Duration
 
+	// Usage is the total usage reported by the fighters across all rounds
+	Usage Usage
+
 	// Rounds contains the history of all rounds in the session
 	Rounds []Round
 
@@ -65,6 +384,63 @@ type SessionResult struct {
 
 	// FilesModified is a list of all files that were modified during the session
 	FilesModified []string
+
+	// Tournament holds the contenders and the judge's verdict in tournament mode
+	Tournament *TournamentResult
+
+	// Plan is the plan approved before the first round, nil without a planning phase
+	Plan *Plan
+
+	// Ledger follows every issue the reviewers raised, from the round it was
+	// raised in to the round it was resolved in
+	Ledger []LedgerEntry
+
+	// Hooks contains the result of each lifecycle hook run outside the rounds,
+	// at the start and the end of the session
+	Hooks []HookResult
+
+	// PathRules are the project's rules limiting the paths reviewed and committed
+	PathRules PathRules
+
+	// ExcludedPaths are the changed paths the path rules left out of the
+	// review and the commit
+	ExcludedPaths []string
+}
+
+// TournamentResult represents the outcome of a tournament between several implementers.
+type TournamentResult struct {
+	// Contenders contains the outcome of every contender, in start order
+	Contenders []ContenderResult
+
+	// Judge is the display name of the fighter that picked the winner
+	Judge string
+
+	// Winner is the number of the winning contender (1-indexed), or 0 if there is none
+	Winner int
+
+	// Reasoning is the judge's explanation of the verdict
+	Reasoning string
+
+	// Applied indicates whether the winning diff was merged into the user's branch
+	Applied bool
+}
+
+// ContenderResult represents the outcome of a single contender in a tournament.
+type ContenderResult struct {
+	// Number identifies the contender (1-indexed)
+	Number int
+
+	// Implementer is the display name of the contender's implementer
+	Implementer string
+
+	// Branch is the branch holding the contender's changes
+	Branch string
+
+	// Result is the contender's session result, nil if it failed to run
+	Result *SessionResult
+
+	// Error describes why the contender failed, if it did
+	Error string
 }
 
 // FighterType represents the type of LLM fighter.
@@ -99,4 +475,13 @@ const (
 
 	// StateFailed indicates the session failed due to an error
 	StateFailed SessionState = "failed"
+
+	// StateInterrupted indicates the session was cancelled before reaching a decision
+	StateInterrupted SessionState = "interrupted"
+
+	// StateStalled indicates the session was stopped because it stopped making progress
+	StateStalled SessionState = "stalled"
+
+	// StateBudgetExhausted indicates the session ran out of time or spend budget
+	StateBudgetExhausted SessionState = "budget_exhausted"
 )
@@ -8,22 +8,24 @@ import (
 func TestRoundStruct(t *testing.T) {
 	now := time.Now()
 	round := Round{
-		Number:       1,
-		ClaudePrompt: "implement feature X",
-		ClaudeOutput: "implementation output",
-		GitDiff:      "+ added line\n- removed line",
-		CodexReview:  "ISSUE: missing error handling",
-		HasIssues:    true,
-		Issues:       []string{"missing error handling"},
-		Duration:     45 * time.Second,
-		Timestamp:    now,
+		Number:            1,
+		Implementer:       "CLAUDE CODE",
+		Reviewer:          "CODEX",
+		ImplementerPrompt: "implement feature X",
+		ImplementerOutput: "implementation output",
+		GitDiff:           "+ added line\n- removed line",
+		ReviewerOutput:    "ISSUE: missing error handling",
+		HasIssues:         true,
+		Issues:            []string{"missing error handling"},
+		Duration:          45 * time.Second,
+		Timestamp:         now,
 	}
 
 	if round.Number != 1 {
 		t.Errorf("expected Number to be 1, got %d", round.Number)
 	}
-	if round.ClaudePrompt != "implement feature X" {
-		t.Errorf("unexpected ClaudePrompt: %s", round.ClaudePrompt)
+	if round.ImplementerPrompt != "implement feature X" {
+		t.Errorf("unexpected ImplementerPrompt: %s", round.ImplementerPrompt)
 	}
 	if !round.HasIssues {
 		t.Error("expected HasIssues to be true")
@@ -92,6 +94,9 @@ func TestSessionStateConstants(t *testing.T) {
 		StateCompleted,
 		StateAborted,
 		StateFailed,
+		StateInterrupted,
+		StateStalled,
+		StateBudgetExhausted,
 	}
 
 	expectedValues := []string{
@@ -101,6 +106,9 @@ func TestSessionStateConstants(t *testing.T) {
 		"completed",
 		"aborted",
 		"failed",
+		"interrupted",
+		"stalled",
+		"budget_exhausted",
 	}
 
 	for i, state := range states {
@@ -109,3 +117,34 @@ func TestSessionStateConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestUsageAddSub(t *testing.T) {
+	a := Usage{InputTokens: 100, OutputTokens: 20, CostUSD: 0.5}
+	b := Usage{InputTokens: 50, OutputTokens: 5, CostUSD: 0.25}
+
+	sum := a.Add(b)
+	if sum.InputTokens != 150 || sum.OutputTokens != 25 || sum.CostUSD != 0.75 {
+		t.Errorf("Add() = %+v", sum)
+	}
+	if diff := sum.Sub(b); diff != a {
+		t.Errorf("Sub() = %+v, want %+v", diff, a)
+	}
+}
+
+func TestReviewRequestIsPlanReview(t *testing.T) {
+	tests := []struct {
+		req  ReviewRequest
+		want bool
+	}{
+		{ReviewRequest{Diff: "+line"}, false},
+		{ReviewRequest{Diff: "+line", Plan: "1. step"}, false},
+		{ReviewRequest{Plan: "1. step"}, true},
+		{ReviewRequest{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.req.IsPlanReview(); got != tt.want {
+			t.Errorf("%+v.IsPlanReview() = %v, want %v", tt.req, got, tt.want)
+		}
+	}
+}